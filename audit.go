@@ -0,0 +1,71 @@
+package cedar
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// A DenyReason explains why a request was denied.
+type DenyReason string
+
+// The reasons for a Deny decision.
+const (
+	// DenyReasonForbidden means that a satisfied forbid policy overrode any
+	// permits.
+	DenyReasonForbidden DenyReason = "forbidden"
+	// DenyReasonNoPermit means that no permit policy was satisfied.
+	DenyReasonNoPermit DenyReason = "noPermit"
+)
+
+// An AuditRecord captures an authorization decision together with everything
+// that led to it, for logging. Its JSON encoding is stable: every field is
+// always present, lists are empty rather than null, and lists are sorted.
+type AuditRecord struct {
+	// Timestamp is the time, in UTC, at which authorization started.
+	Timestamp time.Time `json:"timestamp"`
+	Request   Request   `json:"request"`
+	Decision  Decision  `json:"decision"`
+	// DeterminingPolicies are the satisfied forbids of a denied request and
+	// the satisfied permits of an allowed one, sorted.
+	DeterminingPolicies []PolicyID `json:"determiningPolicies"`
+	// DenyReason is empty for an allowed request.
+	DenyReason DenyReason `json:"denyReason"`
+	// Errors are the errors of the policies that failed to evaluate, sorted
+	// by policy.
+	Errors []DiagnosticError `json:"errors"`
+	// AccessedEntities are the entities that evaluation looked up, as
+	// reported by AuthorizeTracked.
+	AccessedEntities []types.EntityUID `json:"accessedEntities"`
+}
+
+// AuthorizeAudit is like AuthorizeTracked, but returns all the details of the
+// decision as an AuditRecord, so that services log the same fields.
+func AuthorizeAudit(policies PolicyIterator, entities types.EntityGetter, req Request) AuditRecord {
+	record := AuditRecord{
+		Timestamp:           time.Now().UTC(),
+		Request:             req,
+		DeterminingPolicies: []PolicyID{},
+		Errors:              []DiagnosticError{},
+	}
+	decision, diag, accessed := AuthorizeTracked(policies, entities, req)
+	record.Decision = decision
+	record.AccessedEntities = accessed
+	for _, r := range diag.Reasons {
+		record.DeterminingPolicies = append(record.DeterminingPolicies, r.PolicyID)
+	}
+	slices.Sort(record.DeterminingPolicies)
+	record.Errors = append(record.Errors, diag.Errors...)
+	slices.SortFunc(record.Errors, func(a, b DiagnosticError) int {
+		return cmp.Compare(a.PolicyID, b.PolicyID)
+	})
+	if decision == Deny {
+		record.DenyReason = DenyReasonNoPermit
+		if len(diag.Reasons) > 0 {
+			record.DenyReason = DenyReasonForbidden
+		}
+	}
+	return record
+}
@@ -0,0 +1,81 @@
+package cedar_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestAuthorizeAudit(t *testing.T) {
+	t.Parallel()
+	alice := cedar.NewEntityUID("User", "alice")
+	doc := cedar.NewEntityUID("Document", "readme")
+	entities := cedar.EntityMap{
+		alice: {UID: alice},
+		doc:   {UID: doc, Attributes: types.NewRecord(types.RecordMap{"owner": alice})},
+	}
+	ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`
+		permit(principal, action == Action::"view", resource) when { resource.owner == principal };
+		permit(principal == User::"alice", action, resource);
+		forbid(principal, action == Action::"delete", resource);
+		permit(principal, action, resource) when { resource.missing };
+	`))
+	testutil.OK(t, err)
+	req := cedar.Request{
+		Principal: alice,
+		Action:    cedar.NewEntityUID("Action", "view"),
+		Resource:  doc,
+		Context:   types.Record{},
+	}
+
+	t.Run("Allow", func(t *testing.T) {
+		t.Parallel()
+		before := time.Now()
+		record := cedar.AuthorizeAudit(ps, entities, req)
+		testutil.FatalIf(t, record.Timestamp.Before(before.Add(-time.Second)) || record.Timestamp.After(time.Now()), "unexpected timestamp %v", record.Timestamp)
+		testutil.Equals(t, record.Timestamp.Location(), time.UTC)
+		testutil.Equals(t, record.Request, req)
+		testutil.Equals(t, record.Decision, cedar.Allow)
+		testutil.Equals(t, record.DeterminingPolicies, []cedar.PolicyID{"policy0", "policy1"})
+		testutil.Equals(t, record.DenyReason, "")
+		testutil.Equals(t, len(record.Errors), 1)
+		testutil.Equals(t, record.Errors[0].PolicyID, "policy3")
+		testutil.Equals(t, record.AccessedEntities, []types.EntityUID{doc})
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		del := req
+		del.Action = cedar.NewEntityUID("Action", "delete")
+		record := cedar.AuthorizeAudit(ps, entities, del)
+		testutil.Equals(t, record.Decision, cedar.Deny)
+		testutil.Equals(t, record.DenyReason, cedar.DenyReasonForbidden)
+		testutil.Equals(t, record.DeterminingPolicies, []cedar.PolicyID{"policy2"})
+	})
+
+	t.Run("NoPermit", func(t *testing.T) {
+		t.Parallel()
+		bob := req
+		bob.Principal = cedar.NewEntityUID("User", "bob")
+		record := cedar.AuthorizeAudit(ps, entities, bob)
+		testutil.Equals(t, record.Decision, cedar.Deny)
+		testutil.Equals(t, record.DenyReason, cedar.DenyReasonNoPermit)
+		testutil.Equals(t, record.DeterminingPolicies, []cedar.PolicyID{})
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		record := cedar.AuthorizeAudit(cedar.NewPolicySet(), nil, req)
+		record.Timestamp = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		b, err := json.Marshal(record)
+		testutil.OK(t, err)
+		testutil.Equals(t, string(b), `{"timestamp":"2026-01-02T03:04:05Z",`+
+			`"request":{"principal":{"__entity":{"type":"User","id":"alice"}},"action":{"__entity":{"type":"Action","id":"view"}},`+
+			`"resource":{"__entity":{"type":"Document","id":"readme"}},"context":{}},`+
+			`"decision":"deny","determiningPolicies":[],"denyReason":"noPermit","errors":[],"accessedEntities":[]}`)
+	})
+}
@@ -2,6 +2,7 @@ package cedar
 
 import (
 	"iter"
+	"slices"
 
 	"github.com/cedar-policy/cedar-go/internal/eval"
 	"github.com/cedar-policy/cedar-go/types"
@@ -13,60 +14,176 @@ type PolicyIterator interface {
 	All() iter.Seq2[PolicyID, *Policy]
 }
 
+// AuthorizeOption configures Authorize.
+type AuthorizeOption func(*authorizeConfig)
+
+type authorizeConfig struct {
+	fastDeny        bool
+	contextProvider ContextProvider
+	// onError, if set, is called with each policy evaluation error.
+	onError func(*EvaluationError)
+}
+
+// WithFastDeny makes Authorize stop evaluating policies as soon as a forbid
+// policy is satisfied, since the decision is then Deny whatever the remaining
+// policies say. The decision is unchanged, but the Diagnostic is incomplete:
+// Reasons holds only the forbid that was found first, which may differ from
+// call to call, and Errors only the errors of the policies evaluated before
+// it. Use it on hot paths where the diagnostic detail of denials is not
+// needed.
+func WithFastDeny() AuthorizeOption {
+	return func(c *authorizeConfig) {
+		c.fastDeny = true
+	}
+}
+
+// WithContextProvider makes Authorize fetch the context attributes that
+// req.Context does not hold from p, the first time a policy reads them by
+// name with . or has. Each attribute is fetched at most once per call, and an
+// error from p is reported in the Diagnostic for every policy that needed the
+// attribute. Policies that use the whole context as a value, such as
+// `context == {}`, see only the attributes of req.Context.
+func WithContextProvider(p ContextProvider) AuthorizeOption {
+	return func(c *authorizeConfig) {
+		c.contextProvider = p
+	}
+}
+
 // Authorize uses the combination of the PolicySet and Entities to determine
 // if the given Request to determine Decision and Diagnostic.
-func Authorize(policies PolicyIterator, entities types.EntityGetter, req Request) (Decision, Diagnostic) {
+func Authorize(policies PolicyIterator, entities types.EntityGetter, req Request, opts ...AuthorizeOption) (Decision, Diagnostic) {
+	var cfg authorizeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	env := requestEnv(entities, req)
+	if cfg.contextProvider != nil {
+		env.ContextProvider = eval.MemoizeContextProvider(cfg.contextProvider)
+	}
+	var diag Diagnostic
+	forbids, permits := cfg.evalPolicies(policiesForRequest(policies, req), env, &diag)
+	if len(forbids) > 0 {
+		diag.Reasons = forbids
+		return Deny, diag
+	}
+	if len(permits) > 0 {
+		diag.Reasons = permits
+		return Allow, diag
+	}
+	return Deny, diag
+}
+
+// AuthorizeFailOnError is like Authorize, but fails the request if any policy
+// raises an error during evaluation. The decision is then Deny, whatever the
+// satisfied policies say, the Diagnostic has no reasons, and the returned
+// error is the *EvaluationError of the failing policy with the lowest
+// PolicyID. The errors of all the failing policies remain in the Diagnostic.
+func AuthorizeFailOnError(policies PolicyIterator, entities types.EntityGetter, req Request, opts ...AuthorizeOption) (Decision, Diagnostic, error) {
+	var first *EvaluationError
+	opts = append(slices.Clone(opts), func(c *authorizeConfig) {
+		c.onError = func(e *EvaluationError) {
+			if first == nil || e.PolicyID < first.PolicyID {
+				first = e
+			}
+		}
+	})
+	decision, diag := Authorize(policies, entities, req, opts...)
+	if first == nil {
+		return decision, diag, nil
+	}
+	diag.Reasons = nil
+	return Deny, diag, first
+}
+
+// requestEnv returns the environment in which policies are evaluated for req.
+func requestEnv(entities types.EntityGetter, req Request) eval.Env {
 	if entities == nil {
 		var zero types.EntityMap
 		entities = zero
 	}
-	env := eval.Env{
+	return eval.Env{
 		Entities:  entities,
 		Principal: req.Principal,
 		Action:    req.Action,
 		Resource:  req.Resource,
 		Context:   req.Context,
 	}
-	var diag Diagnostic
-	var forbids []DiagnosticReason
-	var permits []DiagnosticReason
+}
 
-	// Use indexed iteration if available and beneficial for faster authorization
-	// Indexing overhead is only worth it for larger policy sets (>50 policies)
-	var policyIter iter.Seq2[PolicyID, *Policy]
+// policiesForRequest uses indexed iteration if available and beneficial for
+// faster authorization. Indexing overhead is only worth it for larger policy
+// sets (>50 policies).
+func policiesForRequest(policies PolicyIterator, req Request) iter.Seq2[PolicyID, *Policy] {
 	if ps, ok := policies.(*PolicySet); ok && len(ps.loadSnapshot().policies) > 50 {
-		policyIter = ps.forRequest(req)
-	} else {
-		policyIter = policies.All()
+		return ps.forRequest(req)
 	}
+	return policies.All()
+}
 
-	// Don't try to short circuit this.
+// evalPolicies returns the satisfied forbid and permit policies, recording
+// evaluation errors in diag.
+func (c authorizeConfig) evalPolicies(policies iter.Seq2[PolicyID, *Policy], env eval.Env, diag *Diagnostic) (forbids, permits []DiagnosticReason) {
+	// Don't try to short circuit this, unless asked to with WithFastDeny.
 	// - Even though single forbid means forbid
 	// - All policy should be run to collect errors
 	// - For permit, all permits must be run to collect annotations
 	// - For forbid, forbids must be run to collect annotations
-	for id, po := range policyIter {
+	for id, po := range policies {
 		result, err := po.eval.Eval(env)
 		if err != nil {
 			diag.Errors = append(diag.Errors, DiagnosticError{PolicyID: id, Position: po.Position(), Message: err.Error()})
+			if c.onError != nil {
+				c.onError(newEvaluationError(id, po, err))
+			}
 			continue
 		}
 		if !result {
 			continue
 		}
-		if po.Effect() == Forbid {
-			forbids = append(forbids, DiagnosticReason{PolicyID: id, Position: po.Position()})
-		} else {
-			permits = append(permits, DiagnosticReason{PolicyID: id, Position: po.Position()})
+		reason := DiagnosticReason{PolicyID: id, Position: po.Position()}
+		if po.Effect() == Permit {
+			permits = append(permits, reason)
+			continue
+		}
+		forbids = append(forbids, reason)
+		if c.fastDeny {
+			break
 		}
 	}
-	if len(forbids) > 0 {
-		diag.Reasons = forbids
-		return Deny, diag
+	return forbids, permits
+}
+
+// AuthorizeTracked is like Authorize, but also returns, sorted, the UIDs of the
+// entities that evaluation looked up, whether for their attributes, their tags
+// or their ancestry during an `in` check. Lookups of entities that are absent
+// from entities are included too, since adding them could change the
+// decision. The decision only depends on the returned entities, so a cached
+// decision can be invalidated when any of them changes.
+//
+// Tracking bypasses the ancestry cache of a [types.AncestryCacheGetter], so
+// every entity visited while checking ancestry is observed.
+func AuthorizeTracked(policies PolicyIterator, entities types.EntityGetter, req Request) (Decision, Diagnostic, []types.EntityUID) {
+	if entities == nil {
+		var zero types.EntityMap
+		entities = zero
 	}
-	if len(permits) > 0 {
-		diag.Reasons = permits
-		return Allow, diag
+	tracker := &trackingEntityGetter{entities: entities, accessed: map[types.EntityUID]bool{}}
+	decision, diag := Authorize(policies, tracker, req)
+	uids := make([]types.EntityUID, 0, len(tracker.accessed))
+	for uid := range tracker.accessed {
+		uids = append(uids, uid)
 	}
-	return Deny, diag
+	slices.SortFunc(uids, types.EntityUID.Compare)
+	return decision, diag, uids
+}
+
+// trackingEntityGetter records the UIDs of all entities looked up through it.
+type trackingEntityGetter struct {
+	entities types.EntityGetter
+	accessed map[types.EntityUID]bool
+}
+
+func (t *trackingEntityGetter) Get(uid types.EntityUID) (types.Entity, bool) {
+	t.accessed[uid] = true
+	return t.entities.Get(uid)
 }
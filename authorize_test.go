@@ -1,6 +1,9 @@
 package cedar_test
 
 import (
+	"errors"
+	"fmt"
+	"iter"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go"
@@ -325,6 +328,112 @@ func TestIsAuthorized(t *testing.T) {
 			Want:      true,
 			DiagErr:   0,
 		},
+		{
+			Name:   "permit-when-resource-in-principal",
+			Policy: `permit(principal,action,resource) when { resource in principal };`,
+			Entities: cedar.EntityMap{
+				cedar.NewEntityUID("table", "whatever"): cedar.Entity{
+					UID:     cedar.NewEntityUID("table", "whatever"),
+					Parents: cedar.NewEntityUIDSet(cedar.NewEntityUID("folder", "shared")),
+				},
+				cedar.NewEntityUID("folder", "shared"): cedar.Entity{
+					UID:     cedar.NewEntityUID("folder", "shared"),
+					Parents: cedar.NewEntityUIDSet(cuzco),
+				},
+			},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      true,
+			DiagErr:   0,
+		},
+		{
+			Name:   "deny-when-resource-in-other-principal",
+			Policy: `permit(principal,action,resource) when { resource in principal };`,
+			Entities: cedar.EntityMap{
+				cedar.NewEntityUID("table", "whatever"): cedar.Entity{
+					UID:     cedar.NewEntityUID("table", "whatever"),
+					Parents: cedar.NewEntityUIDSet(cedar.NewEntityUID("coder", "kronk")),
+				},
+			},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      false,
+			DiagErr:   0,
+		},
+		{
+			Name:   "permit-when-principal-in-resource-attribute",
+			Policy: `permit(principal,action,resource) when { principal in resource.group };`,
+			Entities: cedar.EntityMap{
+				cuzco: cedar.Entity{
+					UID:     cuzco,
+					Parents: cedar.NewEntityUIDSet(cedar.NewEntityUID("team", "osiris")),
+				},
+				cedar.NewEntityUID("table", "whatever"): cedar.Entity{
+					UID:        cedar.NewEntityUID("table", "whatever"),
+					Attributes: cedar.NewRecord(cedar.RecordMap{"group": cedar.NewEntityUID("team", "osiris")}),
+				},
+			},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      true,
+			DiagErr:   0,
+		},
+		{
+			Name:   "permit-when-principal-in-resource-set-attribute",
+			Policy: `permit(principal,action,resource) when { principal in resource.owners };`,
+			Entities: cedar.EntityMap{
+				cedar.NewEntityUID("table", "whatever"): cedar.Entity{
+					UID: cedar.NewEntityUID("table", "whatever"),
+					Attributes: cedar.NewRecord(cedar.RecordMap{
+						"owners": cedar.NewSet(cedar.NewEntityUID("coder", "kronk"), cuzco),
+					}),
+				},
+			},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      true,
+			DiagErr:   0,
+		},
+		{
+			Name:   "error-when-principal-in-non-entity-attribute",
+			Policy: `permit(principal,action,resource) when { principal in resource.name };`,
+			Entities: cedar.EntityMap{
+				cedar.NewEntityUID("table", "whatever"): cedar.Entity{
+					UID:        cedar.NewEntityUID("table", "whatever"),
+					Attributes: cedar.NewRecord(cedar.RecordMap{"name": cedar.String("whatever")}),
+				},
+			},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      false,
+			DiagErr:   1,
+		},
+		{
+			Name:   "error-when-non-entity-attribute-in-resource",
+			Policy: `permit(principal,action,resource) when { principal.name in resource };`,
+			Entities: cedar.EntityMap{
+				cuzco: cedar.Entity{
+					UID:        cuzco,
+					Attributes: cedar.NewRecord(cedar.RecordMap{"name": cedar.String("cuzco")}),
+				},
+			},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      false,
+			DiagErr:   1,
+		},
 		{
 			Name:   "permit-when-relations-has",
 			Policy: `permit(principal,action,resource) when { principal has name };`,
@@ -575,6 +684,21 @@ func TestIsAuthorized(t *testing.T) {
 			Want:      true,
 			DiagErr:   0,
 		},
+		{
+			Name: "permit-when-extension-method-chain",
+			Policy: `permit(principal,action,resource) when {
+				datetime("2024-01-01T12:00:00Z").toDate().durationSince(datetime("2024-01-01")) == duration("0s") &&
+				datetime("2024-01-03T12:00:00Z").durationSince(datetime("2024-01-01")).toDays() == 2 &&
+				datetime("2024-01-01T12:00:00Z").offset(duration("1h")).toTime().toHours() == 13 &&
+				datetime("2024-01-01T12:00:00Z").toTime() > duration("11h")};`,
+			Entities:  cedar.EntityMap{},
+			Principal: cuzco,
+			Action:    dropTable,
+			Resource:  cedar.NewEntityUID("table", "whatever"),
+			Context:   cedar.Record{},
+			Want:      true,
+			DiagErr:   0,
+		},
 		{
 			Name:      "permit-when-datetime-fun-wrong-arity",
 			Policy:    `permit(principal,action,resource) when { datetime("1970-01-01", "UTC") };`,
@@ -846,3 +970,237 @@ func TestIsAuthorized(t *testing.T) {
 		})
 	}
 }
+
+// TestEntityEqualityIsByUID checks that entities compare equal by UID alone,
+// so the same request gives the same decision against entity snapshots that
+// disagree on an entity's attributes.
+func TestEntityEqualityIsByUID(t *testing.T) {
+	t.Parallel()
+	alice := cedar.NewEntityUID("User", "alice")
+	bob := cedar.NewEntityUID("User", "bob")
+	ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`
+		permit(principal, action, resource) when { principal == resource && principal.manager == resource.manager };
+	`))
+	testutil.OK(t, err)
+
+	before := types.EntityMap{
+		alice: types.Entity{UID: alice, Attributes: types.NewRecord(types.RecordMap{
+			"name":    types.String("Alice"),
+			"manager": bob,
+		})},
+	}
+	after := types.EntityMap{
+		alice: types.Entity{UID: alice, Attributes: types.NewRecord(types.RecordMap{
+			"name":    types.String("Alice Smith"),
+			"level":   types.Long(3),
+			"manager": bob,
+		})},
+	}
+	testutil.FatalIf(t, before[alice].Equal(after[alice]), "snapshots should hold different entity data")
+
+	req := cedar.Request{Principal: alice, Action: cedar.NewEntityUID("Action", "view"), Resource: alice, Context: cedar.Record{}}
+	for _, entities := range []types.EntityMap{before, after} {
+		ok, diag := cedar.Authorize(ps, entities, req)
+		testutil.Equals(t, len(diag.Errors), 0)
+		testutil.Equals(t, ok, cedar.Allow)
+	}
+
+	req.Resource = bob
+	ok, _ := cedar.Authorize(ps, before, req)
+	testutil.Equals(t, ok, cedar.Deny)
+}
+
+func TestAuthorizeEmptyPolicySet(t *testing.T) {
+	t.Parallel()
+	req := cedar.Request{
+		Principal: cedar.NewEntityUID("User", "alice"),
+		Action:    cedar.NewEntityUID("Action", "view"),
+		Resource:  cedar.NewEntityUID("Document", "readme"),
+		Context:   cedar.Record{},
+	}
+
+	// With no policies the request is implicitly denied: no policy determines
+	// the decision and nothing is reported as an error.
+	ok, diag := cedar.Authorize(cedar.NewPolicySet(), nil, req)
+	testutil.Equals(t, ok, cedar.Deny)
+	testutil.Equals(t, diag, cedar.Diagnostic{})
+}
+
+func TestAuthorizeTracked(t *testing.T) {
+	t.Parallel()
+	alice := cedar.NewEntityUID("User", "alice")
+	bob := cedar.NewEntityUID("User", "bob")
+	eng := cedar.NewEntityUID("Group", "eng")
+	admins := cedar.NewEntityUID("Group", "admins")
+	doc := cedar.NewEntityUID("Document", "readme")
+	entities := cedar.EntityMap{
+		alice:  {UID: alice, Parents: types.NewEntityUIDSet(eng)},
+		bob:    {UID: bob},
+		eng:    {UID: eng, Parents: types.NewEntityUIDSet(admins)},
+		admins: {UID: admins},
+		doc:    {UID: doc, Attributes: types.NewRecord(types.RecordMap{"owner": alice})},
+	}
+	ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`
+		permit(principal in Group::"admins", action, resource) when { resource.owner == principal };
+	`))
+	testutil.OK(t, err)
+	req := cedar.Request{
+		Principal: alice,
+		Action:    cedar.NewEntityUID("Action", "view"),
+		Resource:  doc,
+	}
+
+	decision, diag, accessed := cedar.AuthorizeTracked(ps, entities, req)
+	wantDecision, wantDiag := cedar.Authorize(ps, entities, req)
+	testutil.Equals(t, decision, wantDecision)
+	testutil.Equals(t, decision, cedar.Allow)
+	testutil.Equals(t, diag, wantDiag)
+	// eng is only consulted for its ancestry; bob is never looked up.
+	testutil.Equals(t, accessed, []types.EntityUID{doc, eng, alice})
+
+	// A lookup of an entity that does not exist is tracked as well.
+	req.Principal = cedar.NewEntityUID("User", "carol")
+	decision, _, accessed = cedar.AuthorizeTracked(ps, entities, req)
+	testutil.Equals(t, decision, cedar.Deny)
+	testutil.Equals(t, accessed, []types.EntityUID{req.Principal})
+
+	// Entities served from an ancestry cache are tracked too.
+	req.Principal = alice
+	_, _, accessed = cedar.AuthorizeTracked(ps, types.NewCachedEntityGetter(entities), req)
+	testutil.Equals(t, accessed, []types.EntityUID{doc, eng, alice})
+}
+
+// orderedPolicies yields policies in a fixed order, named policy0, policy1, ...
+type orderedPolicies cedar.PolicyList
+
+func (o orderedPolicies) All() iter.Seq2[cedar.PolicyID, *cedar.Policy] {
+	return func(yield func(cedar.PolicyID, *cedar.Policy) bool) {
+		for i, p := range o {
+			if !yield(cedar.PolicyID(fmt.Sprintf("policy%d", i)), p) {
+				return
+			}
+		}
+	}
+}
+
+func TestAuthorizeFastDeny(t *testing.T) {
+	t.Parallel()
+	req := cedar.Request{
+		Principal: cedar.NewEntityUID("User", "alice"),
+		Action:    cedar.NewEntityUID("Action", "view"),
+		Resource:  cedar.NewEntityUID("Document", "readme"),
+	}
+	parse := func(t *testing.T, src string) orderedPolicies {
+		t.Helper()
+		list, err := cedar.NewPolicyListFromBytes("policy.cedar", []byte(src))
+		testutil.OK(t, err)
+		return orderedPolicies(list)
+	}
+
+	t.Run("stops at first forbid", func(t *testing.T) {
+		t.Parallel()
+		policies := parse(t, `
+			permit(principal, action, resource);
+			forbid(principal, action, resource) when { principal.missing };
+			forbid(principal, action, resource);
+			forbid(principal, action, resource);
+			forbid(principal, action, resource) when { resource.missing };
+		`)
+		decision, diag := cedar.Authorize(policies, nil, req)
+		testutil.Equals(t, decision, cedar.Deny)
+		testutil.Equals(t, len(diag.Reasons), 2)
+		testutil.Equals(t, len(diag.Errors), 2)
+
+		decision, diag = cedar.Authorize(policies, nil, req, cedar.WithFastDeny())
+		testutil.Equals(t, decision, cedar.Deny)
+		testutil.Equals(t, len(diag.Reasons), 1)
+		testutil.Equals(t, diag.Reasons[0].PolicyID, "policy2")
+		testutil.Equals(t, len(diag.Errors), 1)
+		testutil.Equals(t, diag.Errors[0].PolicyID, "policy1")
+	})
+
+	t.Run("no forbid", func(t *testing.T) {
+		t.Parallel()
+		policies := parse(t, `
+			permit(principal, action, resource);
+			forbid(principal, action, resource) when { false };
+			permit(principal, action, resource);
+		`)
+		decision, diag := cedar.Authorize(policies, nil, req, cedar.WithFastDeny())
+		wantDecision, wantDiag := cedar.Authorize(policies, nil, req)
+		testutil.Equals(t, decision, cedar.Allow)
+		testutil.Equals(t, decision, wantDecision)
+		testutil.Equals(t, diag, wantDiag)
+	})
+}
+
+type recordingContextProvider struct {
+	values types.RecordMap
+	calls  []string
+}
+
+func (p *recordingContextProvider) Get(key string) (types.Value, bool, error) {
+	p.calls = append(p.calls, key)
+	if key == "broken" {
+		return nil, false, errors.New("lookup failed")
+	}
+	v, ok := p.values[types.String(key)]
+	return v, ok, nil
+}
+
+func TestAuthorizeWithContextProvider(t *testing.T) {
+	t.Parallel()
+	policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+		permit(principal, action, resource) when { context.country == "NZ" };
+		forbid(principal, action, resource) when { context.country == "XX" && context.vpn };
+	`))
+	testutil.OK(t, err)
+	req := types.Request{
+		Principal: types.NewEntityUID("User", "alice"),
+		Action:    types.NewEntityUID("Action", "view"),
+		Resource:  types.NewEntityUID("Document", "readme"),
+	}
+
+	t.Run("fetches only what is read", func(t *testing.T) {
+		p := &recordingContextProvider{values: types.RecordMap{"country": types.String("NZ")}}
+		decision, diag := cedar.Authorize(policies, nil, req, cedar.WithContextProvider(p))
+		testutil.Equals(t, decision, types.Allow)
+		testutil.Equals(t, len(diag.Errors), 0)
+		testutil.Equals(t, p.calls, []string{"country"})
+	})
+
+	t.Run("concrete context is used first", func(t *testing.T) {
+		p := &recordingContextProvider{values: types.RecordMap{"vpn": types.True}}
+		req := req
+		req.Context = types.NewRecord(types.RecordMap{"country": types.String("XX")})
+		decision, _ := cedar.Authorize(policies, nil, req, cedar.WithContextProvider(p))
+		testutil.Equals(t, decision, types.Deny)
+		testutil.Equals(t, p.calls, []string{"vpn"})
+	})
+
+	t.Run("whole context", func(t *testing.T) {
+		p := &recordingContextProvider{values: types.RecordMap{"country": types.String("NZ")}}
+		policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+			permit(principal, action, resource) when { context == {} };
+		`))
+		testutil.OK(t, err)
+		decision, diag := cedar.Authorize(policies, nil, req, cedar.WithContextProvider(p))
+		testutil.Equals(t, decision, types.Allow)
+		testutil.Equals(t, len(diag.Errors), 0)
+		testutil.Equals(t, len(p.calls), 0)
+	})
+
+	t.Run("errors follow Cedar semantics", func(t *testing.T) {
+		p := &recordingContextProvider{}
+		policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+			permit(principal, action, resource) when { context.broken };
+			permit(principal, action, resource) when { context has broken || true };
+			permit(principal, action, resource) when { context.missing };
+		`))
+		testutil.OK(t, err)
+		decision, diag := cedar.Authorize(policies, nil, req, cedar.WithContextProvider(p))
+		testutil.Equals(t, decision, types.Deny)
+		testutil.Equals(t, len(diag.Errors), 3)
+		testutil.Equals(t, len(p.calls), 2)
+	})
+}
@@ -0,0 +1,153 @@
+package cedar
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"slices"
+	"sync"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// CachingAuthorizer memoizes authorization decisions for a fixed policy set.
+// Decisions are keyed by a hash of the request's principal, action, resource
+// and context, so requests that differ only in their context are cached
+// separately. Each entry remembers the entities its evaluation looked up, as
+// reported by AuthorizeTracked, and InvalidateEntity evicts the entries that
+// depend on an entity when it changes.
+//
+// The cache assumes that every call sees the same entity data unless told
+// otherwise through InvalidateEntity or Clear. A CachingAuthorizer is safe for
+// concurrent use.
+type CachingAuthorizer struct {
+	policies PolicyIterator
+	size     int
+
+	mu       sync.Mutex
+	entries  map[cacheKey]*list.Element
+	lru      *list.List // of *cacheEntry, most recently used first
+	byEntity map[types.EntityUID]map[cacheKey]struct{}
+	// generation counts invalidations, so that a decision evaluated while an
+	// entity it depends on was invalidated is not cached.
+	generation uint64
+}
+
+type cacheKey [sha256.Size]byte
+
+type cacheEntry struct {
+	key      cacheKey
+	decision Decision
+	diag     Diagnostic
+	deps     []types.EntityUID
+}
+
+// NewCachingAuthorizer returns a CachingAuthorizer that evaluates policies and
+// keeps up to cacheSize decisions, evicting the least recently used. A
+// cacheSize of zero or less disables caching.
+func NewCachingAuthorizer(policies PolicyIterator, cacheSize int) *CachingAuthorizer {
+	return &CachingAuthorizer{
+		policies: policies,
+		size:     cacheSize,
+		entries:  map[cacheKey]*list.Element{},
+		lru:      list.New(),
+		byEntity: map[types.EntityUID]map[cacheKey]struct{}{},
+	}
+}
+
+// Authorize returns the decision for req, evaluating the policies against
+// entities only if no cached decision exists.
+func (c *CachingAuthorizer) Authorize(entities types.EntityGetter, req Request) (Decision, Diagnostic) {
+	if c.size <= 0 {
+		return Authorize(c.policies, entities, req)
+	}
+	key := requestKey(req)
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		e := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return e.decision, cloneDiagnostic(e.diag)
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	decision, diag, deps := AuthorizeTracked(c.policies, entities, req)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && generation == c.generation {
+		c.add(&cacheEntry{key: key, decision: decision, diag: cloneDiagnostic(diag), deps: deps})
+	}
+	return decision, diag
+}
+
+// InvalidateEntity evicts every cached decision whose evaluation looked up
+// uid. Call it whenever the entity is changed, added or removed.
+func (c *CachingAuthorizer) InvalidateEntity(uid types.EntityUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for key := range c.byEntity[uid] {
+		c.remove(c.entries[key])
+	}
+}
+
+// Clear evicts every cached decision.
+func (c *CachingAuthorizer) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	clear(c.entries)
+	clear(c.byEntity)
+	c.lru.Init()
+}
+
+// Len returns the number of cached decisions.
+func (c *CachingAuthorizer) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *CachingAuthorizer) add(e *cacheEntry) {
+	c.entries[e.key] = c.lru.PushFront(e)
+	for _, uid := range e.deps {
+		keys, ok := c.byEntity[uid]
+		if !ok {
+			keys = map[cacheKey]struct{}{}
+			c.byEntity[uid] = keys
+		}
+		keys[e.key] = struct{}{}
+	}
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *CachingAuthorizer) remove(elem *list.Element) {
+	e := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, e.key)
+	for _, uid := range e.deps {
+		delete(c.byEntity[uid], e.key)
+		if len(c.byEntity[uid]) == 0 {
+			delete(c.byEntity, uid)
+		}
+	}
+}
+
+// requestKey hashes the Cedar encoding of the request, in which record keys
+// and set elements are in a canonical order.
+func requestKey(req Request) cacheKey {
+	h := sha256.New()
+	for _, v := range []types.Value{req.Principal, req.Action, req.Resource} {
+		h.Write(v.MarshalCedar())
+		h.Write([]byte{0})
+	}
+	h.Write(req.Context.MarshalCedar())
+	var key cacheKey
+	h.Sum(key[:0])
+	return key
+}
+
+func cloneDiagnostic(d Diagnostic) Diagnostic {
+	return Diagnostic{Reasons: slices.Clone(d.Reasons), Errors: slices.Clone(d.Errors)}
+}
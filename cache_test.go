@@ -0,0 +1,139 @@
+package cedar_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// countingEntities counts the lookups made through it.
+type countingEntities struct {
+	mu       sync.Mutex
+	entities types.EntityMap
+	gets     int
+}
+
+func (c *countingEntities) Get(uid types.EntityUID) (types.Entity, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gets++
+	e, ok := c.entities[uid]
+	return e, ok
+}
+
+func TestCachingAuthorizer(t *testing.T) {
+	t.Parallel()
+	alice := cedar.NewEntityUID("User", "alice")
+	bob := cedar.NewEntityUID("User", "bob")
+	view := cedar.NewEntityUID("Action", "view")
+	doc := cedar.NewEntityUID("Document", "readme")
+	ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`
+		permit(principal, action, resource) when { principal.active && context.mfa };
+	`))
+	testutil.OK(t, err)
+	newEntities := func() *countingEntities {
+		return &countingEntities{entities: types.EntityMap{
+			alice: {UID: alice, Attributes: types.NewRecord(types.RecordMap{"active": types.True})},
+			bob:   {UID: bob, Attributes: types.NewRecord(types.RecordMap{"active": types.True})},
+		}}
+	}
+	request := func(principal types.EntityUID, mfa bool) cedar.Request {
+		return cedar.Request{
+			Principal: principal,
+			Action:    view,
+			Resource:  doc,
+			Context:   types.NewRecord(types.RecordMap{"mfa": types.Boolean(mfa)}),
+		}
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		t.Parallel()
+		entities := newEntities()
+		c := cedar.NewCachingAuthorizer(ps, 10)
+		decision, diag := c.Authorize(entities, request(alice, true))
+		testutil.Equals(t, decision, cedar.Allow)
+		gets := entities.gets
+		decision2, diag2 := c.Authorize(entities, request(alice, true))
+		testutil.Equals(t, decision2, decision)
+		testutil.Equals(t, diag2, diag)
+		testutil.Equals(t, entities.gets, gets)
+		testutil.Equals(t, c.Len(), 1)
+	})
+
+	t.Run("context is part of the key", func(t *testing.T) {
+		t.Parallel()
+		entities := newEntities()
+		c := cedar.NewCachingAuthorizer(ps, 10)
+		decision, _ := c.Authorize(entities, request(alice, true))
+		testutil.Equals(t, decision, cedar.Allow)
+		decision, _ = c.Authorize(entities, request(alice, false))
+		testutil.Equals(t, decision, cedar.Deny)
+		testutil.Equals(t, c.Len(), 2)
+	})
+
+	t.Run("invalidate entity", func(t *testing.T) {
+		t.Parallel()
+		entities := newEntities()
+		c := cedar.NewCachingAuthorizer(ps, 10)
+		c.Authorize(entities, request(alice, true))
+		c.Authorize(entities, request(bob, true))
+
+		entities.entities[alice] = types.Entity{UID: alice, Attributes: types.NewRecord(types.RecordMap{"active": types.False})}
+		decision, _ := c.Authorize(entities, request(alice, true))
+		testutil.Equals(t, decision, cedar.Allow) // stale until invalidated
+
+		c.InvalidateEntity(doc) // not read by the policy
+		testutil.Equals(t, c.Len(), 2)
+		c.InvalidateEntity(alice)
+		testutil.Equals(t, c.Len(), 1)
+		decision, _ = c.Authorize(entities, request(alice, true))
+		testutil.Equals(t, decision, cedar.Deny)
+
+		c.Clear()
+		testutil.Equals(t, c.Len(), 0)
+	})
+
+	t.Run("least recently used is evicted", func(t *testing.T) {
+		t.Parallel()
+		entities := newEntities()
+		c := cedar.NewCachingAuthorizer(ps, 2)
+		c.Authorize(entities, request(alice, true))
+		c.Authorize(entities, request(bob, true))
+		c.Authorize(entities, request(alice, true))
+		c.Authorize(entities, request(alice, false))
+		testutil.Equals(t, c.Len(), 2)
+
+		gets := entities.gets
+		c.Authorize(entities, request(alice, true))
+		testutil.Equals(t, entities.gets, gets)
+		c.Authorize(entities, request(bob, true))
+		testutil.Equals(t, entities.gets > gets, true)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		c := cedar.NewCachingAuthorizer(ps, 0)
+		decision, _ := c.Authorize(newEntities(), request(alice, true))
+		testutil.Equals(t, decision, cedar.Allow)
+		testutil.Equals(t, c.Len(), 0)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+		entities := newEntities()
+		c := cedar.NewCachingAuthorizer(ps, 4)
+		var wg sync.WaitGroup
+		for i := range 16 {
+			wg.Go(func() {
+				principal := []types.EntityUID{alice, bob}[i%2]
+				decision, _ := c.Authorize(entities, request(principal, i%3 != 0))
+				testutil.Equals(t, decision == cedar.Allow, i%3 != 0)
+				c.InvalidateEntity(bob)
+			})
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,73 @@
+package cedar
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cedar-policy/cedar-go/internal/eval"
+)
+
+// EvaluationErrorKind classifies an EvaluationError. The kinds are named as in
+// the error JSON of the Cedar reference implementation.
+type EvaluationErrorKind string
+
+// The kinds of evaluation errors. EvaluationErrorOther covers errors that
+// have no counterpart in the reference implementation, such as those raised
+// by a lazily provided context.
+const (
+	EvaluationErrorEntityDoesNotExist            EvaluationErrorKind = eval.KindEntityDoesNotExist
+	EvaluationErrorEntityAttrDoesNotExist        EvaluationErrorKind = eval.KindEntityAttrDoesNotExist
+	EvaluationErrorRecordAttrDoesNotExist        EvaluationErrorKind = eval.KindRecordAttrDoesNotExist
+	EvaluationErrorUnspecifiedEntityAccess       EvaluationErrorKind = eval.KindUnspecifiedEntityAccess
+	EvaluationErrorTypeError                     EvaluationErrorKind = eval.KindTypeError
+	EvaluationErrorIntegerOverflow               EvaluationErrorKind = eval.KindIntegerOverflow
+	EvaluationErrorFailedExtensionFunctionLookup EvaluationErrorKind = eval.KindFailedExtensionFunctionLookup
+	EvaluationErrorWrongNumArguments             EvaluationErrorKind = eval.KindWrongNumArguments
+	EvaluationErrorExtensionFunctionExecution    EvaluationErrorKind = eval.KindExtensionFunctionExecution
+	EvaluationErrorRecursionLimit                EvaluationErrorKind = eval.KindEntityDepthExceeded
+	EvaluationErrorOther                         EvaluationErrorKind = eval.KindOther
+)
+
+// An EvaluationError is the error raised while evaluating a policy, as
+// returned by AuthorizeFailOnError. Its JSON encoding follows the error JSON
+// of the Cedar reference implementation, so that clients of both engines can
+// handle it the same way.
+type EvaluationError struct {
+	PolicyID PolicyID
+	Position Position
+	Kind     EvaluationErrorKind
+	// Expression is the offending expression in Cedar syntax, such as
+	// `principal.manager` or `resource.getTag("owner")`, with operands
+	// that are not variables rendered by their value. It is empty for errors,
+	// such as type errors, that are not tied to an attribute or tag access.
+	Expression string
+	Err        error
+}
+
+func newEvaluationError(id PolicyID, p *Policy, err error) *EvaluationError {
+	return &EvaluationError{
+		PolicyID:   id,
+		Position:   p.Position(),
+		Kind:       EvaluationErrorKind(eval.ErrorKind(err)),
+		Expression: eval.ErrorExpression(err),
+		Err:        err,
+	}
+}
+
+func (e *EvaluationError) Error() string {
+	return fmt.Sprintf("while evaluating policy `%v`: %v", e.PolicyID, e.Err)
+}
+
+func (e *EvaluationError) Unwrap() error { return e.Err }
+
+// MarshalJSON encodes e as an object with the policyId, kind, expression,
+// message and position of the error. The expression is omitted when empty.
+func (e *EvaluationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		PolicyID   PolicyID            `json:"policyId"`
+		Kind       EvaluationErrorKind `json:"kind"`
+		Expression string              `json:"expression,omitempty"`
+		Message    string              `json:"message"`
+		Position   Position            `json:"position"`
+	}{e.PolicyID, e.Kind, e.Expression, e.Err.Error(), e.Position})
+}
@@ -0,0 +1,105 @@
+package cedar_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestAuthorizeFailOnError(t *testing.T) {
+	t.Parallel()
+	alice := cedar.NewEntityUID("User", "alice")
+	req := cedar.Request{
+		Principal: alice,
+		Action:    cedar.NewEntityUID("Action", "view"),
+		Resource:  cedar.NewEntityUID("Document", "readme"),
+		Context:   cedar.NewRecord(cedar.RecordMap{"n": cedar.Long(1)}),
+	}
+	entities := types.EntityMap{
+		alice: {UID: alice, Attributes: cedar.NewRecord(cedar.RecordMap{"name": cedar.String("alice")})},
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		kind       cedar.EvaluationErrorKind
+		expression string
+	}{
+		{"entityDoesNotExist", `permit(principal, action, resource) when { resource.owner == principal };`,
+			cedar.EvaluationErrorEntityDoesNotExist, "resource.owner"},
+		{"entityAttrDoesNotExist", `permit(principal, action, resource) when { principal.age > 18 };`,
+			cedar.EvaluationErrorEntityAttrDoesNotExist, "principal.age"},
+		{"recordAttrDoesNotExist", `permit(principal, action, resource) when { context["the flag"] };`,
+			cedar.EvaluationErrorRecordAttrDoesNotExist, `context["the flag"]`},
+		{"tag", `permit(principal, action, resource) when { principal.getTag("role") == "admin" };`,
+			cedar.EvaluationErrorEntityAttrDoesNotExist, `principal.getTag("role")`},
+		{"typeError", `permit(principal, action, resource) when { principal.name > 1 };`,
+			cedar.EvaluationErrorTypeError, ""},
+		{"integerOverflow", `permit(principal, action, resource) when { 9223372036854775807 + context.n > 0 };`,
+			cedar.EvaluationErrorIntegerOverflow, ""},
+		{"extensionFunctionExecutionError", `permit(principal, action, resource) when { ip(principal.name).isLoopback() };`,
+			cedar.EvaluationErrorExtensionFunctionExecution, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(tt.policy))
+			testutil.OK(t, err)
+			decision, diag, err := cedar.AuthorizeFailOnError(ps, entities, req)
+			testutil.Equals(t, decision, cedar.Deny)
+			testutil.Equals(t, len(diag.Errors), 1)
+			var evalErr *cedar.EvaluationError
+			testutil.Equals(t, errors.As(err, &evalErr), true)
+			testutil.Equals(t, evalErr.PolicyID, cedar.PolicyID("policy0"))
+			testutil.Equals(t, evalErr.Kind, tt.kind)
+			testutil.Equals(t, evalErr.Expression, tt.expression)
+			testutil.Equals(t, err.Error(), diag.Errors[0].String())
+		})
+	}
+
+	t.Run("errorOverridesPermit", func(t *testing.T) {
+		t.Parallel()
+		ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`
+			permit(principal, action, resource);
+			permit(principal, action, resource) when { principal.age > 18 };
+			permit(principal, action, resource) when { resource.age > 18 };
+		`))
+		testutil.OK(t, err)
+		decision, diag, err := cedar.AuthorizeFailOnError(ps, entities, req)
+		testutil.Equals(t, decision, cedar.Deny)
+		testutil.Equals(t, len(diag.Reasons), 0)
+		testutil.Equals(t, len(diag.Errors), 2)
+		var evalErr *cedar.EvaluationError
+		testutil.Equals(t, errors.As(err, &evalErr), true)
+		testutil.Equals(t, evalErr.PolicyID, cedar.PolicyID("policy1"))
+	})
+
+	t.Run("noError", func(t *testing.T) {
+		t.Parallel()
+		ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`permit(principal, action, resource);`))
+		testutil.OK(t, err)
+		decision, diag, err := cedar.AuthorizeFailOnError(ps, entities, req)
+		testutil.OK(t, err)
+		testutil.Equals(t, decision, cedar.Allow)
+		testutil.Equals(t, len(diag.Reasons), 1)
+	})
+}
+
+func TestEvaluationErrorMarshalJSON(t *testing.T) {
+	t.Parallel()
+	ps, err := cedar.NewPolicySetFromBytes("policy.cedar", []byte(`permit(principal, action, resource) when { principal.age > 18 };`))
+	testutil.OK(t, err)
+	alice := cedar.NewEntityUID("User", "alice")
+	_, _, err = cedar.AuthorizeFailOnError(ps, types.EntityMap{alice: {UID: alice}}, cedar.Request{Principal: alice})
+	testutil.Error(t, err)
+	b, err := json.Marshal(err)
+	testutil.OK(t, err)
+	testutil.Equals(t, string(b),
+		`{"policyId":"policy0","kind":"entityAttrDoesNotExist","expression":"principal.age",`+
+			`"message":"`+"`User::\\\"alice\\\"` does not have the attribute `age`"+`",`+
+			`"position":{"filename":"policy.cedar","offset":0,"line":1,"column":1}}`)
+}
@@ -29,6 +29,14 @@ func Compile(p *ast.Policy) BoolEvaler {
 	return BoolEvaler{eval: ToEval(node)}
 }
 
+// CompileScope compiles only the scope of p, ignoring its conditions, into
+// the same checks that Compile makes for the scope.
+func CompileScope(p *ast.Policy) BoolEvaler {
+	scope := *p
+	scope.Conditions = nil
+	return Compile(&scope)
+}
+
 func PolicyToNode(p *ast.Policy) ast.Node {
 	var nodes []ast.Node
 	_, principalAll := p.Principal.(ast.ScopeTypeAll)
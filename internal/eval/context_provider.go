@@ -0,0 +1,84 @@
+package eval
+
+import (
+	"fmt"
+
+	"github.com/cedar-policy/cedar-go/internal/consts"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+type contextResult struct {
+	value types.Value
+	ok    bool
+	err   error
+}
+
+type memoContextProvider struct {
+	provider types.ContextProvider
+	results  map[string]contextResult
+}
+
+// MemoizeContextProvider returns a ContextProvider that calls p at most once
+// for each key. It is not safe for concurrent use, so a new one should be
+// created for each evaluation.
+func MemoizeContextProvider(p types.ContextProvider) types.ContextProvider {
+	return &memoContextProvider{provider: p, results: map[string]contextResult{}}
+}
+
+func (m *memoContextProvider) Get(key string) (types.Value, bool, error) {
+	r, ok := m.results[key]
+	if !ok {
+		r.value, r.ok, r.err = m.provider.Get(key)
+		m.results[key] = r
+	}
+	return r.value, r.ok, r.err
+}
+
+// isContextVariable reports whether n is the context variable.
+func isContextVariable(n ast.IsNode) bool {
+	v, ok := n.(ast.NodeTypeVariable)
+	return ok && v.Name == consts.Context
+}
+
+// contextAttributeEval accesses, or with has tests for, an attribute of the
+// context. When env.ContextProvider is set, an attribute that env.Context does
+// not hold is fetched from the provider; otherwise it evaluates as the plain
+// access or has expression.
+type contextAttributeEval struct {
+	attribute types.String
+	has       bool
+	plain     Evaler
+}
+
+func newContextAttributeEval(attribute types.String, has bool) *contextAttributeEval {
+	n := &contextAttributeEval{attribute: attribute, has: has}
+	if has {
+		n.plain = newHasEval(newVariableEval(consts.Context), attribute)
+	} else {
+		n.plain = newAttributeAccessEval(newVariableEval(consts.Context), attribute)
+	}
+	return n
+}
+
+func (n *contextAttributeEval) Eval(env Env) (types.Value, error) {
+	if env.ContextProvider == nil {
+		return n.plain.Eval(env)
+	}
+	if rec, ok := env.Context.(types.Record); ok {
+		if _, ok := rec.Get(n.attribute); ok {
+			return n.plain.Eval(env)
+		}
+	}
+	v, ok, err := env.ContextProvider.Get(string(n.attribute))
+	switch {
+	case err != nil:
+		return zeroValue(), fmt.Errorf("context attribute `%s`: %w", n.attribute, err)
+	case n.has:
+		return types.Boolean(ok), nil
+	case !ok:
+		// The attribute is missing from the context as well.
+		return n.plain.Eval(env)
+	}
+	return v, nil
+}
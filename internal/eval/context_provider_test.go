@@ -0,0 +1,107 @@
+package eval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+type countingContextProvider struct {
+	values types.RecordMap
+	err    error
+	calls  map[string]int
+}
+
+func (p *countingContextProvider) Get(key string) (types.Value, bool, error) {
+	p.calls[key]++
+	if p.err != nil {
+		return nil, false, p.err
+	}
+	v, ok := p.values[types.String(key)]
+	return v, ok, nil
+}
+
+func TestContextProvider(t *testing.T) {
+	t.Parallel()
+	newEnv := func(p types.ContextProvider) Env {
+		return Env{
+			Context:         types.NewRecord(types.RecordMap{"local": types.Long(1)}),
+			ContextProvider: MemoizeContextProvider(p),
+		}
+	}
+
+	t.Run("Access", func(t *testing.T) {
+		t.Parallel()
+		p := &countingContextProvider{values: types.RecordMap{"country": types.String("NZ")}, calls: map[string]int{}}
+		env := newEnv(p)
+		for range 2 {
+			v, err := ToEval(ast.Context().Access("country").AsIsNode()).Eval(env)
+			testutil.OK(t, err)
+			testutil.Equals(t, v, types.Value(types.String("NZ")))
+		}
+		testutil.Equals(t, p.calls, map[string]int{"country": 1})
+	})
+
+	t.Run("ConcreteAttributeWins", func(t *testing.T) {
+		t.Parallel()
+		p := &countingContextProvider{values: types.RecordMap{"local": types.Long(2)}, calls: map[string]int{}}
+		v, err := ToEval(ast.Context().Access("local").AsIsNode()).Eval(newEnv(p))
+		testutil.OK(t, err)
+		testutil.Equals(t, v, types.Value(types.Long(1)))
+		testutil.Equals(t, p.calls, map[string]int{})
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		p := &countingContextProvider{calls: map[string]int{}}
+		env := newEnv(p)
+		_, err := ToEval(ast.Context().Access("country").AsIsNode()).Eval(env)
+		testutil.ErrorIs(t, err, errAttributeAccess)
+		v, err := ToEval(ast.Context().Has("country").AsIsNode()).Eval(env)
+		testutil.OK(t, err)
+		testutil.Equals(t, v, types.Value(types.False))
+		testutil.Equals(t, p.calls, map[string]int{"country": 1})
+	})
+
+	t.Run("Has", func(t *testing.T) {
+		t.Parallel()
+		p := &countingContextProvider{values: types.RecordMap{"country": types.String("NZ")}, calls: map[string]int{}}
+		v, err := ToEval(ast.Context().Has("country").AsIsNode()).Eval(newEnv(p))
+		testutil.OK(t, err)
+		testutil.Equals(t, v, types.Value(types.True))
+	})
+
+	t.Run("ProviderError", func(t *testing.T) {
+		t.Parallel()
+		errLookup := errors.New("lookup failed")
+		p := &countingContextProvider{err: errLookup, calls: map[string]int{}}
+		env := newEnv(p)
+		_, err := ToEval(ast.Context().Access("country").AsIsNode()).Eval(env)
+		testutil.ErrorIs(t, err, errLookup)
+		_, err = ToEval(ast.Context().Has("country").AsIsNode()).Eval(env)
+		testutil.ErrorIs(t, err, errLookup)
+		testutil.Equals(t, p.calls, map[string]int{"country": 1})
+	})
+
+	t.Run("NoProvider", func(t *testing.T) {
+		t.Parallel()
+		env := Env{Context: types.NewRecord(types.RecordMap{"local": types.Long(1)})}
+		_, err := ToEval(ast.Context().Access("country").AsIsNode()).Eval(env)
+		testutil.ErrorIs(t, err, errRecordAttributeAccess)
+		v, err := ToEval(ast.Context().Has("local").AsIsNode()).Eval(env)
+		testutil.OK(t, err)
+		testutil.Equals(t, v, types.Value(types.True))
+	})
+
+	t.Run("WholeContext", func(t *testing.T) {
+		t.Parallel()
+		p := &countingContextProvider{calls: map[string]int{}}
+		v, err := ToEval(ast.Context().Equal(ast.Record(nil)).AsIsNode()).Eval(newEnv(p))
+		testutil.OK(t, err)
+		testutil.Equals(t, v, types.Value(types.False))
+		testutil.Equals(t, p.calls, map[string]int{})
+	})
+}
@@ -27,8 +27,14 @@ func ToEval(n ast.IsNode) Evaler {
 func convertUnaryNode(n ast.IsNode) Evaler {
 	switch v := n.(type) {
 	case ast.NodeTypeAccess:
+		if isContextVariable(v.Arg) {
+			return newContextAttributeEval(v.Value, false)
+		}
 		return newAttributeAccessEval(ToEval(v.Arg), v.Value)
 	case ast.NodeTypeHas:
+		if isContextVariable(v.Arg) {
+			return newContextAttributeEval(v.Value, true)
+		}
 		return newHasEval(ToEval(v.Arg), v.Value)
 	case ast.NodeTypeLike:
 		return newLikeEval(ToEval(v.Arg), v.Value)
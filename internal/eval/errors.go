@@ -0,0 +1,139 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cedar-policy/cedar-go/internal"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// The kinds of evaluation errors, named as in the error JSON of the Cedar
+// reference implementation.
+const (
+	KindEntityDoesNotExist            = "entityDoesNotExist"
+	KindEntityAttrDoesNotExist        = "entityAttrDoesNotExist"
+	KindRecordAttrDoesNotExist        = "recordAttrDoesNotExist"
+	KindUnspecifiedEntityAccess       = "unspecifiedEntityAccess"
+	KindTypeError                     = "typeError"
+	KindIntegerOverflow               = "integerOverflow"
+	KindFailedExtensionFunctionLookup = "failedExtensionFunctionLookup"
+	KindWrongNumArguments             = "wrongNumArguments"
+	KindExtensionFunctionExecution    = "extensionFunctionExecutionError"
+	KindEntityDepthExceeded           = "recursionLimit"
+	KindOther                         = "evaluationError"
+)
+
+// errRecordAttributeAccess is errAttributeAccess raised on a record rather
+// than an entity.
+var errRecordAttributeAccess = fmt.Errorf("%w", errAttributeAccess)
+
+// ExpressionError annotates an evaluation error with the Cedar expression
+// whose evaluation raised it: the access of an attribute of object or, for a
+// tag error, the lookup of a tag on it. Its message is that of the wrapped
+// error. The expression is only rendered when Expression is called, so that
+// routine errors cost no more than the error itself.
+type ExpressionError struct {
+	object Evaler
+	value  types.Value
+	key    types.String
+	tag    bool
+	Err    error
+}
+
+func newAccessError(object Evaler, v types.Value, attr types.String, err error) *ExpressionError {
+	return &ExpressionError{object: object, value: v, key: attr, Err: err}
+}
+
+func newTagError(object Evaler, v types.Value, tag types.String, err error) *ExpressionError {
+	return &ExpressionError{object: object, value: v, key: tag, tag: true, Err: err}
+}
+
+func (e *ExpressionError) Error() string { return e.Err.Error() }
+
+func (e *ExpressionError) Unwrap() error { return e.Err }
+
+// Expression renders the expression that raised the error in Cedar syntax.
+func (e *ExpressionError) Expression() string {
+	if e.tag {
+		return tagExpression(e.object, e.value, e.key)
+	}
+	return accessExpression(e.object, e.value, e.key)
+}
+
+// ErrorExpression returns the expression recorded in err by an
+// ExpressionError, or "" if there is none.
+func ErrorExpression(err error) string {
+	var ee *ExpressionError
+	if errors.As(err, &ee) {
+		return ee.Expression()
+	}
+	return ""
+}
+
+// ErrorKind classifies an error returned by Eval into one of the Kind
+// constants.
+func ErrorKind(err error) string {
+	kinds := []struct {
+		target error
+		kind   string
+	}{
+		{errEntityNotExist, KindEntityDoesNotExist},
+		{errRecordAttributeAccess, KindRecordAttrDoesNotExist},
+		{errAttributeAccess, KindEntityAttrDoesNotExist},
+		{errTagAccess, KindEntityAttrDoesNotExist},
+		{errUnspecifiedEntity, KindUnspecifiedEntityAccess},
+		{ErrType, KindTypeError},
+		{internal.ErrNotComparable, KindTypeError},
+		{errOverflow, KindIntegerOverflow},
+		{errUnknownExtensionFunction, KindFailedExtensionFunctionLookup},
+		{errArity, KindWrongNumArguments},
+		{internal.ErrDatetime, KindExtensionFunctionExecution},
+		{internal.ErrDecimal, KindExtensionFunctionExecution},
+		{internal.ErrDuration, KindExtensionFunctionExecution},
+		{internal.ErrDurationRange, KindExtensionFunctionExecution},
+		{internal.ErrIP, KindExtensionFunctionExecution},
+		{ErrEntityDepthExceeded, KindEntityDepthExceeded},
+		{ErrAncestorDepthExceeded, KindEntityDepthExceeded},
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.target) {
+			return k.kind
+		}
+	}
+	return KindOther
+}
+
+// accessExpression renders the access of attr on object, whose value is v,
+// in Cedar syntax.
+func accessExpression(object Evaler, v types.Value, attr types.String) string {
+	s := objectExpression(object, v)
+	if isIdent(string(attr)) {
+		return s + "." + string(attr)
+	}
+	return s + "[" + string(attr.MarshalCedar()) + "]"
+}
+
+// tagExpression renders the lookup of tag on object, whose value is v, in
+// Cedar syntax.
+func tagExpression(object Evaler, v types.Value, tag types.String) string {
+	return objectExpression(object, v) + ".getTag(" + string(tag.MarshalCedar()) + ")"
+}
+
+// objectExpression renders object, whose value is v, in Cedar syntax.
+// Variables are rendered by name and other objects by their value.
+func objectExpression(object Evaler, v types.Value) string {
+	if ve, ok := object.(*variableEval); ok {
+		return string(ve.variableName)
+	}
+	return string(v.MarshalCedar())
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && (i == 0 || !('0' <= r && r <= '9')) {
+			return false
+		}
+	}
+	return s != ""
+}
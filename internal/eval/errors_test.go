@@ -0,0 +1,58 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestErrorKind(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		kind string
+	}{
+		{fmt.Errorf("entity `a` %w", errEntityNotExist), KindEntityDoesNotExist},
+		{fmt.Errorf("`a` %w `b`", errAttributeAccess), KindEntityAttrDoesNotExist},
+		{fmt.Errorf("record %w `b`", errRecordAttributeAccess), KindRecordAttrDoesNotExist},
+		{fmt.Errorf("`a` %w `b`", errTagAccess), KindEntityAttrDoesNotExist},
+		{errUnspecifiedEntity, KindUnspecifiedEntityAccess},
+		{fmt.Errorf("%w: expected bool", ErrType), KindTypeError},
+		{errOverflow, KindIntegerOverflow},
+		{errUnknownExtensionFunction, KindFailedExtensionFunctionLookup},
+		{errArity, KindWrongNumArguments},
+		{ErrEntityDepthExceeded, KindEntityDepthExceeded},
+		{newAccessError(newVariableEval("principal"), types.NewEntityUID("User", "alice"), "a", errEntityNotExist), KindEntityDoesNotExist},
+		{errors.New("other"), KindOther},
+	}
+	for _, tt := range tests {
+		testutil.Equals(t, ErrorKind(tt.err), tt.kind)
+	}
+}
+
+func TestErrorExpression(t *testing.T) {
+	t.Parallel()
+	err := fmt.Errorf("wrapped: %w", newAccessError(newVariableEval("principal"), types.NewEntityUID("User", "alice"), "a", errEntityNotExist))
+	testutil.Equals(t, ErrorExpression(err), "principal.a")
+	testutil.Equals(t, err.Error(), "wrapped: does not exist")
+	testutil.Equals(t, ErrorExpression(errEntityNotExist), "")
+}
+
+func TestAccessExpression(t *testing.T) {
+	t.Parallel()
+	rec := types.NewRecord(types.RecordMap{"a": types.Long(1)})
+	testutil.Equals(t, accessExpression(newVariableEval("context"), rec, "b_1"), "context.b_1")
+	testutil.Equals(t, accessExpression(newLiteralEval(rec), rec, "b"), `{"a":1}.b`)
+	testutil.Equals(t, accessExpression(newVariableEval("context"), rec, "1b"), `context["1b"]`)
+	testutil.Equals(t, accessExpression(newVariableEval("context"), rec, ""), `context[""]`)
+}
+
+func TestTagExpression(t *testing.T) {
+	t.Parallel()
+	uid := types.NewEntityUID("User", "alice")
+	testutil.Equals(t, tagExpression(newVariableEval("principal"), uid, "dept"), `principal.getTag("dept")`)
+	testutil.Equals(t, tagExpression(newLiteralEval(uid), uid, "dept"), `User::"alice".getTag("dept")`)
+}
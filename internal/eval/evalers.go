@@ -3,6 +3,7 @@ package eval
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/cedar-policy/cedar-go/internal/consts"
 	"github.com/cedar-policy/cedar-go/internal/extensions"
@@ -26,6 +27,9 @@ type Env struct {
 	Entities                    types.EntityGetter
 	Principal, Action, Resource types.Value
 	Context                     types.Value
+	// ContextProvider optionally supplies context attributes that Context
+	// does not hold, fetching them when a policy first accesses them.
+	ContextProvider types.ContextProvider
 	// Limits optionally configures resource limits for evaluation.
 	// Nil means no limits are applied.
 	Limits *Limits
@@ -767,21 +771,21 @@ func (n *attributeAccessEval) Eval(env Env) (types.Value, error) {
 	case types.EntityUID:
 		var unspecified types.EntityUID
 		if vv == unspecified {
-			return zeroValue(), fmt.Errorf("cannot access attribute `%s` of %w", n.attribute, errUnspecifiedEntity)
+			return zeroValue(), n.error(v, fmt.Errorf("cannot access attribute `%s` of %w", n.attribute, errUnspecifiedEntity))
 		}
 		rec, ok := env.Entities.Get(vv)
 		if !ok {
-			return zeroValue(), fmt.Errorf("entity `%v` %w", vv.String(), errEntityNotExist)
+			return zeroValue(), n.error(v, fmt.Errorf("entity `%v` %w", vv.String(), errEntityNotExist))
 		}
 		val, ok := rec.Attributes.Get(n.attribute)
 		if !ok {
-			return zeroValue(), fmt.Errorf("`%s` %w `%s`", vv.String(), errAttributeAccess, n.attribute)
+			return zeroValue(), n.error(v, fmt.Errorf("`%s` %w `%s`", vv.String(), errAttributeAccess, n.attribute))
 		}
 		return val, nil
 	case types.Record:
 		val, ok := vv.Get(n.attribute)
 		if !ok {
-			return zeroValue(), fmt.Errorf("record %w `%s`", errAttributeAccess, n.attribute)
+			return zeroValue(), n.error(v, fmt.Errorf("record %w `%s`", errRecordAttributeAccess, n.attribute))
 		}
 		return val, nil
 	default:
@@ -789,6 +793,11 @@ func (n *attributeAccessEval) Eval(env Env) (types.Value, error) {
 	}
 }
 
+// error records the failed access of the attribute on v in err.
+func (n *attributeAccessEval) error(v types.Value, err error) error {
+	return newAccessError(n.object, v, n.attribute, err)
+}
+
 // hasEval
 type hasEval struct {
 	object    Evaler
@@ -846,12 +855,12 @@ func (n *getTagEval) Eval(env Env) (types.Value, error) {
 
 	e, ok := env.Entities.Get(eid)
 	if !ok {
-		return zeroValue(), fmt.Errorf("entity `%v` %w", eid.String(), errEntityNotExist)
+		return zeroValue(), newTagError(n.lhs, eid, t, fmt.Errorf("entity `%v` %w", eid.String(), errEntityNotExist))
 	}
 
 	val, ok := e.Tags.Get(t)
 	if !ok {
-		return zeroValue(), fmt.Errorf("`%s` %w `%s`", eid.String(), errTagAccess, t)
+		return zeroValue(), newTagError(n.lhs, eid, t, fmt.Errorf("`%s` %w `%s`", eid.String(), errTagAccess, t))
 	}
 
 	return val, nil
@@ -940,9 +949,20 @@ type entityTraverser struct {
 	env      Env
 	entity   types.EntityUID
 	known    mapset.MapSet[types.EntityUID]
-	todo     []types.EntityUID
+	todo     []ancestor
 	depth    int
 	maxDepth int
+	// maxHops is the number of parent hops followed from entity, or 0 for
+	// no limit. truncated records whether any ancestor lay beyond it.
+	maxHops   int
+	truncated bool
+}
+
+// ancestor is an entity queued for traversal along with the number of parent
+// hops that separate it from the traversal's starting entity.
+type ancestor struct {
+	uid  types.EntityUID
+	hops int
 }
 
 // newEntityTraverser creates a new traverser for BFS hierarchy traversal.
@@ -954,6 +974,9 @@ func newEntityTraverser(env Env, entity types.EntityUID) *entityTraverser {
 	if env.Limits != nil && env.Limits.MaxEntityGraphDepth > 0 {
 		t.maxDepth = env.Limits.MaxEntityGraphDepth
 	}
+	if env.Limits != nil && env.Limits.MaxAncestorDepth > 0 {
+		t.maxHops = env.Limits.MaxAncestorDepth
+	}
 	return t
 }
 
@@ -968,56 +991,86 @@ func (t *entityTraverser) checkDepthLimit() error {
 	return nil
 }
 
-// addParentsToQueue adds eligible parents to the traversal queue.
-func (t *entityTraverser) addParentsToQueue(fe types.Entity) {
-	for k := range fe.Parents.All() {
+// addParentsToQueue adds eligible parents to the traversal queue. Parents are
+// queued in sorted order so that traversal, and therefore any limit error, is
+// deterministic across runs.
+func (t *entityTraverser) addParentsToQueue(fe types.Entity, hops int) {
+	for _, k := range slices.SortedFunc(fe.Parents.All(), types.EntityUID.Compare) {
 		p, ok := t.env.Entities.Get(k)
 		if !ok || p.Parents.Len() == 0 || k == t.entity || t.known.Contains(k) {
 			continue
 		}
-		t.todo = append(t.todo, k)
+		t.todo = append(t.todo, ancestor{uid: k, hops: hops})
 		t.known.Add(k)
 	}
 }
 
 // next returns the next candidate to process, or false if done.
-func (t *entityTraverser) next() (types.EntityUID, bool) {
+func (t *entityTraverser) next() (ancestor, bool) {
 	if len(t.todo) == 0 {
-		return types.EntityUID{}, false
+		return ancestor{}, false
 	}
-	candidate := t.todo[len(t.todo)-1]
-	t.todo = t.todo[:len(t.todo)-1]
+	candidate := t.todo[0]
+	t.todo = t.todo[1:]
 	return candidate, true
 }
 
-func entityInOne(env Env, entity types.EntityUID, parent types.EntityUID) (bool, error) {
-	if entity == parent {
-		return true, nil
-	}
-
-	// Fast path: use cached ancestry if available
-	if cached, ok := env.Entities.(types.AncestryCacheGetter); ok {
-		return cached.GetAncestryCache().IsAncestor(entity, parent), nil
-	}
-
-	// Slow path: BFS traversal with optional depth limit
-	t := newEntityTraverser(env, entity)
-	candidate := entity
+// search walks the ancestors of the traverser's entity breadth first and
+// reports whether found holds for the parents of any entity on the way.
+// Parents more than maxHops away are not examined; if ErrorOnAncestorDepth
+// is set and the search failed because of that, it returns
+// ErrAncestorDepthExceeded.
+func (t *entityTraverser) search(found func(parents types.EntityUIDSet) bool) (bool, error) {
+	candidate := ancestor{uid: t.entity}
 	for {
 		if err := t.checkDepthLimit(); err != nil {
 			return false, err
 		}
-		if fe, ok := env.Entities.Get(candidate); ok {
-			if fe.Parents.Contains(parent) {
+		if fe, ok := t.env.Entities.Get(candidate.uid); ok {
+			if t.maxHops > 0 && candidate.hops >= t.maxHops {
+				t.truncated = t.truncated || fe.Parents.Len() > 0
+			} else if found(fe.Parents) {
 				return true, nil
+			} else {
+				t.addParentsToQueue(fe, candidate.hops+1)
 			}
-			t.addParentsToQueue(fe)
 		}
 		var ok bool
 		if candidate, ok = t.next(); !ok {
-			return false, nil
+			break
+		}
+	}
+	if t.truncated && t.env.Limits.ErrorOnAncestorDepth {
+		return false, ErrAncestorDepthExceeded
+	}
+	return false, nil
+}
+
+// hopLimited reports whether env limits the number of parent hops followed
+// by "in" checks.
+func hopLimited(env Env) bool {
+	return env.Limits != nil && env.Limits.MaxAncestorDepth > 0
+}
+
+func entityInOne(env Env, entity types.EntityUID, parent types.EntityUID) (bool, error) {
+	if entity == parent {
+		return true, nil
+	}
+
+	// Fast path: use cached ancestry if available. The cache does not record
+	// how far away an ancestor is, so a hop limit only uses it to rule
+	// ancestry out.
+	if cached, ok := env.Entities.(types.AncestryCacheGetter); ok {
+		isAncestor := cached.GetAncestryCache().IsAncestor(entity, parent)
+		if !isAncestor || !hopLimited(env) {
+			return isAncestor, nil
 		}
 	}
+
+	// Slow path: BFS traversal with optional depth limit
+	return newEntityTraverser(env, entity).search(func(parents types.EntityUIDSet) bool {
+		return parents.Contains(parent)
+	})
 }
 
 func entityInSet(env Env, entity types.EntityUID, parents mapset.Container[types.EntityUID]) (bool, error) {
@@ -1028,27 +1081,16 @@ func entityInSet(env Env, entity types.EntityUID, parents mapset.Container[types
 	// Fast path: use cached ancestry if available
 	if cached, ok := env.Entities.(types.AncestryCacheGetter); ok {
 		ancestors := cached.GetAncestryCache().GetAncestors(entity)
-		return ancestors.Intersects(parents), nil
+		isAncestor := ancestors.Intersects(parents)
+		if !isAncestor || !hopLimited(env) {
+			return isAncestor, nil
+		}
 	}
 
 	// Slow path: BFS traversal with optional depth limit
-	t := newEntityTraverser(env, entity)
-	candidate := entity
-	for {
-		if err := t.checkDepthLimit(); err != nil {
-			return false, err
-		}
-		if fe, ok := env.Entities.Get(candidate); ok {
-			if fe.Parents.Intersects(parents) {
-				return true, nil
-			}
-			t.addParentsToQueue(fe)
-		}
-		var ok bool
-		if candidate, ok = t.next(); !ok {
-			return false, nil
-		}
-	}
+	return newEntityTraverser(env, entity).search(func(ps types.EntityUIDSet) bool {
+		return ps.Intersects(parents)
+	})
 }
 
 func (n *inEval) Eval(env Env) (types.Value, error) {
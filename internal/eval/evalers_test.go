@@ -179,6 +179,8 @@ func TestNotNode(t *testing.T) {
 		}{
 			{"Error", newErrorEval(errTest), errTest},
 			{"TypeError", newLiteralEval(types.Long(1)), ErrType},
+			{"NestedTypeError", newAndEval(newLiteralEval(types.True), newLiteralEval(types.Long(1))), ErrType},
+			{"LongOperand", newAddEval(newLiteralEval(types.Long(1)), newLiteralEval(types.Long(2))), ErrType},
 		}
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
@@ -191,6 +193,27 @@ func TestNotNode(t *testing.T) {
 	}
 }
 
+func TestNotNodeNested(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		arg    Evaler
+		result bool
+	}{
+		{"NotAnd", newAndEval(newLiteralEval(types.True), newLiteralEval(types.False)), true},
+		{"NotOr", newOrEval(newLiteralEval(types.False), newLiteralEval(types.True)), false},
+		{"NotNot", newNotEval(newLiteralEval(types.True)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := newNotEval(tt.arg).Eval(Env{})
+			testutil.OK(t, err)
+			AssertBoolValue(t, v, tt.result)
+		})
+	}
+}
+
 func TestCheckedAddI64(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -479,6 +502,22 @@ func TestNegateNode(t *testing.T) {
 		AssertLongValue(t, v, 3)
 	})
 
+	t.Run("Nested", func(t *testing.T) {
+		t.Parallel()
+		n := newNegateEval(newAddEval(newLiteralEval(types.Long(1)), newLiteralEval(types.Long(2))))
+		v, err := n.Eval(Env{})
+		testutil.OK(t, err)
+		AssertLongValue(t, v, -3)
+	})
+
+	t.Run("MaxLong", func(t *testing.T) {
+		t.Parallel()
+		n := newNegateEval(newNegateEval(newLiteralEval(types.Long(9_223_372_036_854_775_807))))
+		v, err := n.Eval(Env{})
+		testutil.OK(t, err)
+		AssertLongValue(t, v, 9_223_372_036_854_775_807)
+	})
+
 	tests := []struct {
 		name string
 		arg  Evaler
@@ -487,6 +526,14 @@ func TestNegateNode(t *testing.T) {
 		{"Error", newErrorEval(errTest), errTest},
 		{"TypeError", newLiteralEval(types.True), ErrType},
 		{"Overflow", newLiteralEval(types.Long(-9_223_372_036_854_775_808)), errOverflow},
+		{"NestedTypeError", newNotEval(newLiteralEval(types.True)), ErrType},
+		{"NestedOverflow",
+			newSubtractEval(newLiteralEval(types.Long(-9_223_372_036_854_775_807)), newLiteralEval(types.Long(1))),
+			errOverflow},
+		{"InnerOverflow",
+			newAddEval(newLiteralEval(types.Long(9_223_372_036_854_775_807)), newLiteralEval(types.Long(1))),
+			errOverflow},
+		{"DoubleNegateOverflow", newNegateEval(newLiteralEval(types.Long(-9_223_372_036_854_775_808))), errOverflow},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -750,6 +797,7 @@ func TestComparableValueComparisonNodes(t *testing.T) {
 		zeroDuration = types.NewDuration(time.Duration(0))
 		negDuration  = types.NewDuration(-1 * time.Millisecond)
 		posDuration  = types.NewDuration(1 * time.Millisecond)
+		oneDecimal   = testutil.Must(types.NewDecimalFromInt(1))
 	)
 
 	type test struct {
@@ -897,6 +945,11 @@ func TestComparableValueComparisonNodes(t *testing.T) {
 				{pastDate, neg1, false, ErrType},
 				{negDuration, futureDate, false, ErrType},
 				{neg1, negDuration, false, ErrType},
+
+				// Decimals are compared with methods, never with operators.
+				{oneDecimal, zero, false, ErrType},
+				{pos1, oneDecimal, false, ErrType},
+				{oneDecimal, oneDecimal, false, ErrType},
 			},
 		},
 		{name: ">=",
@@ -977,6 +1030,12 @@ func TestIfThenElseNode(t *testing.T) {
 		{"Else", newLiteralEval(types.False), newLiteralEval(types.Long(-1)),
 			newLiteralEval(types.Long(42)), types.Long(42),
 			nil},
+		{"ThenString", newLiteralEval(types.True), newLiteralEval(types.String("gold")),
+			newLiteralEval(types.String("basic")), types.String("gold"),
+			nil},
+		{"ElseSet", newLiteralEval(types.False), newLiteralEval(types.NewSet(types.String("admin"))),
+			newLiteralEval(types.NewSet(types.String("viewer"))), types.NewSet(types.String("viewer")),
+			nil},
 		{"Err", newErrorEval(errTest), newLiteralEval(zeroValue()), newLiteralEval(zeroValue()), zeroValue(),
 			errTest},
 		{"ErrType", newLiteralEval(types.Long(123)), newLiteralEval(zeroValue()), newLiteralEval(zeroValue()), zeroValue(),
@@ -1018,6 +1077,41 @@ func TestEqualNode(t *testing.T) {
 	}
 }
 
+func TestEqualNodeCrossVariable(t *testing.T) {
+	t.Parallel()
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	aliceDoc := types.NewEntityUID("Doc", "alice")
+	doc := types.NewEntityUID("Doc", "report")
+	entities := types.EntityMap{
+		alice: types.Entity{UID: alice, Attributes: types.NewRecord(types.RecordMap{"name": types.String(`User::"alice"`)})},
+		doc:   types.Entity{UID: doc, Attributes: types.NewRecord(types.RecordMap{"owner": alice})},
+	}
+	owner := newAttributeAccessEval(newVariableEval(consts.Resource), "owner")
+	tests := []struct {
+		name      string
+		lhs, rhs  Evaler
+		principal types.EntityUID
+		resource  types.EntityUID
+		result    bool
+	}{
+		{"ownerIsPrincipal", owner, newVariableEval(consts.Principal), alice, doc, true},
+		{"ownerIsNotPrincipal", owner, newVariableEval(consts.Principal), bob, doc, false},
+		{"principalIsResource", newVariableEval(consts.Principal), newVariableEval(consts.Resource), alice, alice, true},
+		{"sameIDDifferentType", newVariableEval(consts.Principal), newVariableEval(consts.Resource), alice, aliceDoc, false},
+		{"entityIsNotString", newVariableEval(consts.Principal), newAttributeAccessEval(newVariableEval(consts.Principal), "name"), alice, doc, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := Env{Entities: entities, Principal: tt.principal, Resource: tt.resource}
+			v, err := newEqualEval(tt.lhs, tt.rhs).Eval(env)
+			testutil.OK(t, err)
+			AssertBoolValue(t, v, tt.result)
+		})
+	}
+}
+
 func TestNotEqualNode(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1967,6 +2061,32 @@ func TestInNode(t *testing.T) {
 			types.False,
 			nil,
 		},
+		{
+			"SetAncestor",
+			newLiteralEval(types.NewEntityUID("human", "joe")),
+			newLiteralEval(types.NewSet(
+				types.NewEntityUID("kingdom", "plant"),
+				types.NewEntityUID("species", "human"),
+			)),
+			map[string][]string{
+				`human::"joe"`: {`species::"human"`},
+			},
+			types.True,
+			nil,
+		},
+		{
+			"SetUnrelated",
+			newLiteralEval(types.NewEntityUID("human", "joe")),
+			newLiteralEval(types.NewSet(
+				types.NewEntityUID("human", "jane"),
+				types.NewEntityUID("kingdom", "plant"),
+			)),
+			map[string][]string{
+				`human::"jane"`: {`human::"joe"`},
+			},
+			types.False,
+			nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1992,6 +2112,30 @@ func TestInNode(t *testing.T) {
 	}
 }
 
+// TestInSetVersusContains checks that in with a set tests ancestry against each
+// element, while contains tests membership only.
+func TestInSetVersusContains(t *testing.T) {
+	t.Parallel()
+	joe := types.NewEntityUID("human", "joe")
+	species := types.NewEntityUID("species", "human")
+	env := Env{Entities: types.EntityMap{
+		joe: {UID: joe, Parents: types.NewEntityUIDSet(species)},
+	}}
+	set := newLiteralEval(types.NewSet(species))
+
+	v, err := newInEval(newLiteralEval(joe), set).Eval(env)
+	testutil.OK(t, err)
+	AssertBoolValue(t, v, true)
+
+	v, err = newContainsEval(set, newLiteralEval(joe)).Eval(env)
+	testutil.OK(t, err)
+	AssertBoolValue(t, v, false)
+
+	v, err = newContainsEval(set, newLiteralEval(species)).Eval(env)
+	testutil.OK(t, err)
+	AssertBoolValue(t, v, true)
+}
+
 func TestIsInNode(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
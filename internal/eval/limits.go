@@ -10,15 +10,39 @@ import (
 var (
 	ErrEntityDepthExceeded = errors.New("entity graph depth limit exceeded")
 	ErrEvaluationTimeout   = errors.New("evaluation timeout")
+	// ErrAncestorDepthExceeded is raised by an "in" check that could not
+	// find the ancestor it looked for within MaxAncestorDepth hops, but may
+	// have found it further away.
+	ErrAncestorDepthExceeded = errors.New("ancestor depth limit exceeded")
 )
 
 // Limits configures resource limits for policy evaluation to protect against
 // DoS attacks. Zero values indicate no limit.
 type Limits struct {
-	// MaxEntityGraphDepth limits how deep entity hierarchy traversal can go
-	// when evaluating "in" operators. Default 0 means unlimited.
+	// MaxEntityGraphDepth limits how many entities, counting the one on its
+	// left, an "in" check examines while walking the entity hierarchy,
+	// whatever their distance. Exceeding it fails the check with
+	// ErrEntityDepthExceeded. Default 0 means unlimited.
 	MaxEntityGraphDepth int
 
+	// MaxAncestorDepth limits how many parent hops an "in" check follows from
+	// the entity on its left. Ancestors further away are treated as
+	// unreachable, so a limit below the depth of a legitimate hierarchy
+	// changes the result of the check. Default 0 means unlimited.
+	//
+	// The two limits are independent and both apply to the same walk: entities
+	// beyond MaxAncestorDepth hops are never examined, so they do not count
+	// toward MaxEntityGraphDepth, and whichever limit is reached first ends
+	// the walk. When the entity count is exceeded, the check fails with
+	// ErrEntityDepthExceeded even if the hop limit also cut the walk short.
+	MaxAncestorDepth int
+
+	// ErrorOnAncestorDepth makes an "in" check fail with
+	// ErrAncestorDepthExceeded, rather than evaluate to false, when it did
+	// not find the ancestor and MaxAncestorDepth kept it from looking
+	// further.
+	ErrorOnAncestorDepth bool
+
 	// MaxPolicyConditions limits the number of conditions that can be evaluated
 	// in a single policy. Default 0 means unlimited.
 	MaxPolicyConditions int
@@ -199,4 +199,28 @@ func TestEntityGraphDepthLimit(t *testing.T) {
 		)
 		testutil.Equals(t, err, ErrEntityDepthExceeded)
 	})
+
+	t.Run("SortedParentOrder", func(t *testing.T) {
+		t.Parallel()
+		// alice is in groups a and b, both one step from their own parent.
+		// Parents are visited in sorted order, so a is always checked before
+		// the limit is reached and b never is.
+		a := types.NewEntityUID("Group", "a")
+		b := types.NewEntityUID("Group", "b")
+		entities := types.EntityMap{
+			a: {UID: a, Parents: types.NewEntityUIDSet(types.NewEntityUID("Team", "a"))},
+			b: {UID: b, Parents: types.NewEntityUIDSet(types.NewEntityUID("Team", "b"))},
+		}
+		alice := types.NewEntityUID("User", "alice")
+		entities[alice] = types.Entity{UID: alice, Parents: types.NewEntityUIDSet(b, a)}
+		env := Env{Entities: entities, Limits: &Limits{MaxEntityGraphDepth: 2}}
+		for range 20 {
+			result, err := entityInOne(env, alice, types.NewEntityUID("Team", "a"))
+			testutil.OK(t, err)
+			testutil.Equals(t, result, true)
+
+			_, err = entityInOne(env, alice, types.NewEntityUID("Team", "b"))
+			testutil.Equals(t, err, ErrEntityDepthExceeded)
+		}
+	})
 }
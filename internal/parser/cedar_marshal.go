@@ -137,6 +137,12 @@ func marshalCondition(c ast.ConditionType, buf *bytes.Buffer) {
 	buf.WriteString(" }")
 }
 
+// MarshalCondition encodes a single when or unless clause, such as
+// `when { principal.active }`.
+func MarshalCondition(c ast.ConditionType, buf *bytes.Buffer) {
+	marshalCondition(c, buf)
+}
+
 func (n NodeValue) marshalCedar(buf *bytes.Buffer) {
 	buf.Write(n.Value.MarshalCedar())
 }
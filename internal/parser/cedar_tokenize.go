@@ -7,6 +7,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/cedar-policy/cedar-go/internal/rust"
@@ -332,7 +333,11 @@ func (s *scanner) scanEscape() rune {
 		}
 		ch = s.next()
 	default:
-		s.error("invalid char escape")
+		if unicode.IsPrint(ch) {
+			s.error(fmt.Sprintf(`invalid char escape \%c`, ch))
+		} else {
+			s.error("invalid char escape")
+		}
 	}
 	return ch
 }
@@ -121,18 +121,20 @@ func TestTokenizeErrors(t *testing.T) {
         `, "comment not terminated", Position{Line: 1, Column: 6}},
 		{`okay "
         " foo bar`, "literal not terminated", Position{Line: 1, Column: 6}},
-		{`"okay" "\a"`, "invalid char escape", Position{Line: 1, Column: 8}},
-		{`"okay" "\b"`, "invalid char escape", Position{Line: 1, Column: 8}},
-		{`"okay" "\f"`, "invalid char escape", Position{Line: 1, Column: 8}},
-		{`"okay" "\v"`, "invalid char escape", Position{Line: 1, Column: 8}},
-		{`"okay" "\1"`, "invalid char escape", Position{Line: 1, Column: 8}},
+		{`"okay" "\a"`, `invalid char escape \a`, Position{Line: 1, Column: 8}},
+		{`"okay" "\b"`, `invalid char escape \b`, Position{Line: 1, Column: 8}},
+		{`"okay" "\f"`, `invalid char escape \f`, Position{Line: 1, Column: 8}},
+		{`"okay" "\v"`, `invalid char escape \v`, Position{Line: 1, Column: 8}},
+		{`"okay" "\1"`, `invalid char escape \1`, Position{Line: 1, Column: 8}},
 		{`"okay" "\x"`, "invalid char escape", Position{Line: 1, Column: 8}},
 		{`"okay" "\x1"`, "invalid char escape", Position{Line: 1, Column: 8}},
 		{`"okay" "\ubadf"`, "invalid char escape", Position{Line: 1, Column: 8}},
-		{`"okay" "\U0000badf"`, "invalid char escape", Position{Line: 1, Column: 8}},
+		{`"okay" "\U0000badf"`, `invalid char escape \U`, Position{Line: 1, Column: 8}},
 		{`"okay" "\u{}"`, "invalid char escape", Position{Line: 1, Column: 8}},
 		{`"okay" "\u{0000000}"`, "invalid char escape", Position{Line: 1, Column: 8}},
 		{`"okay" "\u{z"`, "invalid char escape", Position{Line: 1, Column: 8}},
+		{`"okay" "foo\q"`, `invalid char escape \q`, Position{Line: 1, Column: 8}},
+		{"\"okay\" \"\\\t\"", "invalid char escape", Position{Line: 1, Column: 8}},
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
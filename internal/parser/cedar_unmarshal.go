@@ -127,9 +127,38 @@ func (p *Policy) fromCedar(parser *parser) error {
 	return nil
 }
 
+// ParseExpression parses a single Cedar expression. Every bare identifier in
+// the expression, including principal, action, resource and context, must be
+// a key of vars and is replaced by its value. Input that is not exactly one
+// expression, such as a policy, is rejected.
+func ParseExpression(b []byte, vars map[string]types.Value) (ast.Node, error) {
+	tokens, err := Tokenize(b)
+	if err != nil {
+		return ast.Node{}, err
+	}
+
+	if vars == nil {
+		vars = map[string]types.Value{}
+	}
+	parser := newParser(tokens)
+	parser.vars = vars
+	expr, err := parser.expression()
+	if err != nil {
+		return ast.Node{}, err
+	}
+	if !parser.peek().isEOF() {
+		return ast.Node{}, parser.errorf("unexpected token after expression")
+	}
+	return expr, nil
+}
+
 type parser struct {
 	tokens []Token
 	pos    int
+
+	// vars, when non-nil, holds the values bound to bare identifiers by
+	// ParseExpression.
+	vars map[string]types.Value
 }
 
 func newParser(tokens []Token) parser {
@@ -168,6 +197,10 @@ func (p *parser) errorf(s string, args ...any) error {
 	if p.pos < len(p.tokens) {
 		t = p.tokens[p.pos]
 	}
+	return errorAt(t, s, args...)
+}
+
+func errorAt(t Token, s string, args ...any) error {
 	err := fmt.Errorf(s, args...)
 	return fmt.Errorf("parse error at %v %q: %w", t.Pos, t.Text, err)
 }
@@ -791,18 +824,7 @@ func (p *parser) primary() (ast.Node, error) {
 		if next.Text == "::" || next.Text == "(" {
 			return p.entityOrExtFun(t.Text)
 		}
-		switch t.Text {
-		case consts.Principal:
-			res = ast.Principal()
-		case consts.Action:
-			res = ast.Action()
-		case consts.Resource:
-			res = ast.Resource()
-		case consts.Context:
-			res = ast.Context()
-		default:
-			return res, p.errorf("invalid primary")
-		}
+		return p.variable(t.Text)
 	case t.Text == "(":
 		expr, err := p.expression()
 		if err != nil {
@@ -831,6 +853,28 @@ func (p *parser) primary() (ast.Node, error) {
 	return res, nil
 }
 
+func (p *parser) variable(name string) (ast.Node, error) {
+	if p.vars != nil {
+		v, ok := p.vars[name]
+		if !ok {
+			return ast.Node{}, p.errorf("unbound variable `%v`", name)
+		}
+		return ast.Value(v), nil
+	}
+	switch name {
+	case consts.Principal:
+		return ast.Principal(), nil
+	case consts.Action:
+		return ast.Action(), nil
+	case consts.Resource:
+		return ast.Resource(), nil
+	case consts.Context:
+		return ast.Context(), nil
+	default:
+		return ast.Node{}, p.errorf("invalid primary")
+	}
+}
+
 func (p *parser) entityOrExtFun(prefix string) (ast.Node, error) {
 	for {
 		t := p.advance()
@@ -902,13 +946,14 @@ func (p *parser) record() (ast.Node, error) {
 			p.advance()
 			return ast.Record(elements), nil
 		}
+		keyToken := t
 		k, v, err := p.recordEntry()
 		if err != nil {
 			return res, err
 		}
 
 		if known.Contains(k) {
-			return res, p.errorf("duplicate key: %v", k)
+			return res, errorAt(keyToken, "duplicate key: %v", k)
 		}
 		known.Add(k)
 		elements = append(elements, ast.Pair{Key: types.String(k), Value: v})
@@ -341,6 +341,12 @@ when { principal.firstName like "johnny" };`,
 when { principal.firstName like "joh\*nny" };`,
 			ast.Permit().When(ast.Principal().Access("firstName").Like(types.NewPattern(types.String("joh*nny")))),
 		},
+		{
+			"like escaped backslash",
+			`permit ( principal, action, resource )
+when { principal.firstName like "C:\\*\*" };`,
+			ast.Permit().When(ast.Principal().Access("firstName").Like(types.NewPattern(types.String("C:\\"), types.Wildcard{}, types.String("*")))),
+		},
 		{
 			"like wildcard",
 			`permit ( principal, action, resource )
@@ -695,6 +701,13 @@ func TestParseApproximateErrors(t *testing.T) {
 		{"func", `permit (principal, action, resource) when { ip(}`, "invalid primary"},
 		{"args", `permit (principal, action, resource) when { ip(42 42)`, "got 42 want ,"},
 		{"dupeKey", `permit (principal, action, resource) when { {k:42,k:43}`, "duplicate key"},
+		{"dupeKeyPosition", `permit (principal, action, resource) when { {k:42,k:43}`, `parse error at <input>:1:51 "k": duplicate key: k`},
+		{"dupeKeyQuoted", `permit (principal, action, resource) when { {k:42,"k":43}`, `"\"k\"": duplicate key: k`},
+		{"dupeKeyNested", `permit (principal, action, resource) when { {a:{b:1,b:2}} }`, "duplicate key: b"},
+		{"likeInvalidEscape", `permit (principal, action, resource) when { principal.email like "foo\q" };`, `<input>:1:66: invalid char escape \q`},
+		{"likeTrailingBackslash", `permit (principal, action, resource) when { principal.email like "foo\" };`, "literal not terminated"},
+		{"likeTrailingBackslashAfterWildcard", `permit (principal, action, resource) when { principal.email like "*\" };`, "literal not terminated"},
+		{"dupeKeyContextEquals", `permit (principal, action, resource) when { context == {a:1,a:2} }`, "duplicate key: a"},
 		{"reservedKeywordAsRecordKey", `permit (principal, action, resource) when { {false:43} }`, "expected ident or string"},
 		{"reservedKeywordAsHas", `permit (principal, action, resource) when { {} has false }`, "expected ident or string"},
 		{"reservedKeywordAsEntityType", `permit (principal == false::"42", action, resource)`, "expected ident"},
@@ -716,6 +729,51 @@ func TestParseApproximateErrors(t *testing.T) {
 	}
 }
 
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+	vars := map[string]types.Value{
+		"principal": johnny,
+		"flag":      types.True,
+	}
+	tests := []struct {
+		name string
+		in   string
+		out  ast.Node
+	}{
+		{"variable", `principal`, ast.Value(johnny)},
+		{"custom", `flag && 1 < 2`, ast.Value(types.True).And(ast.Long(1).LessThan(ast.Long(2)))},
+		{"entity", `principal in Group::"x"`, ast.Value(johnny).In(ast.EntityUID("Group", "x"))},
+		{"recordKey", `{flag: 1}`, ast.Record(ast.Pairs{{Key: "flag", Value: ast.Long(1)}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parser.ParseExpression([]byte(tt.in), vars)
+			testutil.OK(t, err)
+			testutil.Equals(t, got, tt.out)
+		})
+	}
+
+	errTests := []struct {
+		name            string
+		in              string
+		outErrSubstring string
+	}{
+		{"unbound", `resource`, "unbound variable `resource`"},
+		{"policy", `permit (principal, action, resource);`, "`permit` is not a function"},
+		{"trailing", `flag flag`, "unexpected token after expression"},
+		{"empty", ``, "invalid primary"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := parser.ParseExpression([]byte(tt.in), vars)
+			testutil.Error(t, err)
+			testutil.FatalIf(t, !strings.Contains(err.Error(), tt.outErrSubstring), "got %v want %v", err.Error(), tt.outErrSubstring)
+		})
+	}
+}
+
 func TestPolicySliceErrors(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -2,6 +2,9 @@ package cedar
 
 import (
 	"bytes"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/cedar-policy/cedar-go/ast"
 	"github.com/cedar-policy/cedar-go/internal/eval"
@@ -12,12 +15,20 @@ import (
 
 // A Policy is the parsed form of a single Cedar language policy statement.
 type Policy struct {
-	eval eval.BoolEvaler // determines if a policy matches a request.
-	ast  *internalast.Policy
+	eval  eval.BoolEvaler // determines if a policy matches a request.
+	scope *scopeEvaler    // determines if the policy's scope matches a request.
+	ast   *internalast.Policy
+}
+
+// scopeEvaler holds the evaler of a policy's scope, which is compiled on
+// first use since only ScopeMatches needs it.
+type scopeEvaler struct {
+	once   sync.Once
+	evaler eval.BoolEvaler
 }
 
 func newPolicy(astIn *internalast.Policy) *Policy {
-	return &Policy{eval: eval.Compile(astIn), ast: astIn}
+	return &Policy{eval: eval.Compile(astIn), scope: &scopeEvaler{}, ast: astIn}
 }
 
 // MarshalJSON encodes a single Policy statement in the JSON format specified by the [Cedar documentation].
@@ -86,6 +97,166 @@ func (p *Policy) Effect() Effect {
 	return Effect(p.ast.Effect)
 }
 
+// ScopeKind identifies the form of a policy scope constraint.
+type ScopeKind int
+
+const (
+	// ScopeAll is an unconstrained scope, e.g. `principal`.
+	ScopeAll ScopeKind = iota
+	// ScopeEq matches a single entity, e.g. `principal == User::"alice"`.
+	ScopeEq
+	// ScopeIn matches an entity or its descendants, e.g. `principal in Group::"admins"`.
+	ScopeIn
+	// ScopeInSet matches any of a set of entities, e.g. `action in [Action::"a", Action::"b"]`.
+	// It only occurs in action scopes.
+	ScopeInSet
+	// ScopeIs matches entities of a type, e.g. `principal is User`.
+	ScopeIs
+	// ScopeIsIn matches entities of a type that are in an entity, e.g.
+	// `principal is User in Group::"admins"`.
+	ScopeIsIn
+)
+
+// String returns the Cedar operator for the scope kind.
+func (k ScopeKind) String() string {
+	switch k {
+	case ScopeAll:
+		return "all"
+	case ScopeEq:
+		return "=="
+	case ScopeIn, ScopeInSet:
+		return "in"
+	case ScopeIs:
+		return "is"
+	case ScopeIsIn:
+		return "is in"
+	}
+	return "unknown"
+}
+
+// Scope describes the principal, action, or resource constraint of a policy.
+// Which fields are set depends on Kind:
+//   - ScopeAll: none
+//   - ScopeEq, ScopeIn: Entity
+//   - ScopeInSet: Entities
+//   - ScopeIs: Type
+//   - ScopeIsIn: Type and Entity
+type Scope struct {
+	Kind     ScopeKind
+	Type     EntityType
+	Entity   EntityUID
+	Entities []EntityUID
+}
+
+func newScope(s internalast.IsScopeNode) Scope {
+	switch s := s.(type) {
+	case internalast.ScopeTypeEq:
+		return Scope{Kind: ScopeEq, Entity: s.Entity}
+	case internalast.ScopeTypeIn:
+		return Scope{Kind: ScopeIn, Entity: s.Entity}
+	case internalast.ScopeTypeInSet:
+		return Scope{Kind: ScopeInSet, Entities: slices.Clone(s.Entities)}
+	case internalast.ScopeTypeIs:
+		return Scope{Kind: ScopeIs, Type: s.Type}
+	case internalast.ScopeTypeIsIn:
+		return Scope{Kind: ScopeIsIn, Type: s.Type, Entity: s.Entity}
+	}
+	return Scope{Kind: ScopeAll}
+}
+
+// PrincipalScope retrieves the principal scope constraint of this policy.
+func (p *Policy) PrincipalScope() Scope {
+	return newScope(p.ast.Principal)
+}
+
+// ActionScope retrieves the action scope constraint of this policy.
+func (p *Policy) ActionScope() Scope {
+	return newScope(p.ast.Action)
+}
+
+// ResourceScope retrieves the resource scope constraint of this policy.
+func (p *Policy) ResourceScope() Scope {
+	return newScope(p.ast.Resource)
+}
+
+// ScopeSignature is a comparable summary of a policy's scope, suitable as a
+// map key for grouping or indexing policies. Policies whose scopes are
+// structurally identical have equal signatures. The principal and resource
+// parts only record the entity type that the scope requires, so scopes that
+// differ only in the entity they name, or in the ancestor of an in, also
+// share a signature.
+type ScopeSignature struct {
+	PrincipalKind ScopeKind
+	// PrincipalType is the entity type a matching principal must have: the
+	// type of the entity for ==, or the type named by is. It is empty for all
+	// and in, which do not constrain the type.
+	PrincipalType EntityType
+
+	ActionKind ScopeKind
+	// Actions lists the actions named by the action scope in Cedar syntax,
+	// sorted, deduplicated and separated by ", ". It is empty for all.
+	Actions string
+
+	ResourceKind ScopeKind
+	// ResourceType is the entity type a matching resource must have, as for
+	// PrincipalType.
+	ResourceType EntityType
+}
+
+// ScopeSignature returns the signature of this policy's scope.
+func (p *Policy) ScopeSignature() ScopeSignature {
+	principal, action, resource := p.PrincipalScope(), p.ActionScope(), p.ResourceScope()
+	return ScopeSignature{
+		PrincipalKind: principal.Kind,
+		PrincipalType: scopeEntityType(p.ast.Principal),
+		ActionKind:    action.Kind,
+		Actions:       scopeActions(action),
+		ResourceKind:  resource.Kind,
+		ResourceType:  scopeEntityType(p.ast.Resource),
+	}
+}
+
+// scopeEntityType returns the entity type that a principal or resource scope
+// requires, or "" if it allows any type.
+func scopeEntityType(s internalast.IsScopeNode) EntityType {
+	switch s := s.(type) {
+	case internalast.ScopeTypeEq:
+		return s.Entity.Type
+	case internalast.ScopeTypeIs:
+		return s.Type
+	case internalast.ScopeTypeIsIn:
+		return s.Type
+	}
+	return ""
+}
+
+func scopeActions(s Scope) string {
+	var actions []string
+	switch s.Kind {
+	case ScopeEq, ScopeIn:
+		actions = append(actions, s.Entity.String())
+	case ScopeInSet:
+		for _, e := range s.Entities {
+			actions = append(actions, e.String())
+		}
+	}
+	slices.Sort(actions)
+	return strings.Join(slices.Compact(actions), ", ")
+}
+
+// ScopeMatches reports whether req satisfies the principal, action and
+// resource scope of the policy, without evaluating its conditions. Scope
+// constraints are checked exactly as Authorize checks them: `in` follows the
+// ancestors of the request's entities, including action entities for action
+// groups, as found in entities, and `is` compares entity types. A policy whose
+// scope does not match is never satisfied, so ScopeMatches can cheaply rule
+// out policies before full evaluation.
+func (p *Policy) ScopeMatches(entities EntityGetter, req Request) bool {
+	p.scope.once.Do(func() { p.scope.evaler = eval.CompileScope(p.ast) })
+	ok, err := p.scope.evaler.Eval(requestEnv(entities, req))
+	return err == nil && bool(ok)
+}
+
 // Position retrieves the position of this policy.
 func (p *Policy) Position() Position {
 	return Position(p.ast.Position)
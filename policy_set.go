@@ -218,6 +218,60 @@ func (p *PolicySet) All() iter.Seq2[PolicyID, *Policy] {
 	}
 }
 
+// ForEach calls fn for each policy in the PolicySet in lexicographical order by PolicyID. If fn returns an error,
+// ForEach stops and returns that error. Policies added or removed while ForEach runs are not observed.
+func (p *PolicySet) ForEach(fn func(PolicyID, *Policy) error) error {
+	s := p.loadSnapshot()
+	for _, id := range slices.Sorted(maps.Keys(s.policies)) {
+		if err := fn(id, s.policies[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReferencedUIDs returns, sorted by type and then ID, every entity and action
+// UID that appears literally in the scopes and conditions of the policies in
+// the PolicySet. Each UID is listed once. This is useful for checking that
+// the entities and actions a policy set refers to still exist, for example
+// to find grants to a deleted group.
+func (p *PolicySet) ReferencedUIDs() []types.EntityUID {
+	seen := map[types.EntityUID]struct{}{}
+	for _, policy := range p.loadSnapshot().policies {
+		collectPolicyUIDs(seen, policy.ast)
+	}
+	uids := slices.Collect(maps.Keys(seen))
+	slices.SortFunc(uids, types.EntityUID.Compare)
+	return uids
+}
+
+func collectPolicyUIDs(seen map[types.EntityUID]struct{}, p *ast.Policy) {
+	for _, scope := range []ast.IsScopeNode{p.Principal, p.Action, p.Resource} {
+		switch s := scope.(type) {
+		case ast.ScopeTypeEq:
+			seen[s.Entity] = struct{}{}
+		case ast.ScopeTypeIn:
+			seen[s.Entity] = struct{}{}
+		case ast.ScopeTypeInSet:
+			for _, e := range s.Entities {
+				seen[e] = struct{}{}
+			}
+		case ast.ScopeTypeIsIn:
+			seen[s.Entity] = struct{}{}
+		}
+	}
+	for _, cond := range p.Conditions {
+		ast.Inspect(ast.NewNode(cond.Body), func(n ast.IsNode) bool {
+			if v, ok := n.(ast.NodeValue); ok {
+				for uid := range types.EntityUIDsIn(v.Value) {
+					seen[uid] = struct{}{}
+				}
+			}
+			return true
+		})
+	}
+}
+
 // policyIndex provides fast policy lookup by action, principal type, and resource type.
 type policyIndex struct {
 	// Index by action EntityUID
@@ -253,8 +307,8 @@ func buildIndex(policies PolicyMap) *policyIndex {
 	for id, policy := range policies {
 		ast := policy.ast
 		indexAction(idx, id, ast.Action)
-		indexPrincipal(idx, id, ast.Principal)
-		indexResource(idx, id, ast.Resource)
+		indexType(idx.principalTypeIndex, idx.principalTypeWildcards, id, ast.Principal)
+		indexType(idx.resourceTypeIndex, idx.resourceTypeWildcards, id, ast.Resource)
 	}
 
 	return idx
@@ -413,60 +467,17 @@ func indexAction(idx *policyIndex, id PolicyID, scope ast.IsActionScopeNode) {
 	}
 }
 
-func indexPrincipal(idx *policyIndex, id PolicyID, scope ast.IsPrincipalScopeNode) {
-	switch s := scope.(type) {
-	case ast.ScopeTypeAll:
-		idx.principalTypeWildcards[id] = struct{}{}
-	case ast.ScopeTypeEq:
-		key := string(s.Entity.Type)
-		if idx.principalTypeIndex[key] == nil {
-			idx.principalTypeIndex[key] = make(map[PolicyID]struct{})
-		}
-		idx.principalTypeIndex[key][id] = struct{}{}
-	case ast.ScopeTypeIn:
-		idx.principalTypeWildcards[id] = struct{}{}
-	case ast.ScopeTypeIs:
-		key := string(s.Type)
-		if idx.principalTypeIndex[key] == nil {
-			idx.principalTypeIndex[key] = make(map[PolicyID]struct{})
-		}
-		idx.principalTypeIndex[key][id] = struct{}{}
-	case ast.ScopeTypeIsIn:
-		key := string(s.Type)
-		if idx.principalTypeIndex[key] == nil {
-			idx.principalTypeIndex[key] = make(map[PolicyID]struct{})
-		}
-		idx.principalTypeIndex[key][id] = struct{}{}
-	default:
-		idx.principalTypeWildcards[id] = struct{}{}
+// indexType indexes a principal or resource scope by the entity type it
+// requires, if any.
+func indexType(index map[string]map[PolicyID]struct{}, wildcards map[PolicyID]struct{}, id PolicyID, scope ast.IsScopeNode) {
+	et := scopeEntityType(scope)
+	if et == "" {
+		wildcards[id] = struct{}{}
+		return
 	}
-}
-
-func indexResource(idx *policyIndex, id PolicyID, scope ast.IsResourceScopeNode) {
-	switch s := scope.(type) {
-	case ast.ScopeTypeAll:
-		idx.resourceTypeWildcards[id] = struct{}{}
-	case ast.ScopeTypeEq:
-		key := string(s.Entity.Type)
-		if idx.resourceTypeIndex[key] == nil {
-			idx.resourceTypeIndex[key] = make(map[PolicyID]struct{})
-		}
-		idx.resourceTypeIndex[key][id] = struct{}{}
-	case ast.ScopeTypeIn:
-		idx.resourceTypeWildcards[id] = struct{}{}
-	case ast.ScopeTypeIs:
-		key := string(s.Type)
-		if idx.resourceTypeIndex[key] == nil {
-			idx.resourceTypeIndex[key] = make(map[PolicyID]struct{})
-		}
-		idx.resourceTypeIndex[key][id] = struct{}{}
-	case ast.ScopeTypeIsIn:
-		key := string(s.Type)
-		if idx.resourceTypeIndex[key] == nil {
-			idx.resourceTypeIndex[key] = make(map[PolicyID]struct{})
-		}
-		idx.resourceTypeIndex[key][id] = struct{}{}
-	default:
-		idx.resourceTypeWildcards[id] = struct{}{}
+	key := string(et)
+	if index[key] == nil {
+		index[key] = make(map[PolicyID]struct{})
 	}
+	index[key][id] = struct{}{}
 }
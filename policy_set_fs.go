@@ -0,0 +1,191 @@
+package cedar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// LoadPolicySetFS creates a PolicySet from the Cedar files in fsys whose paths
+// match glob, as interpreted by [fs.Glob]. This allows loading policies
+// embedded with go:embed.
+//
+// A policy annotated with @id("...") uses that value as its PolicyID. Other
+// policies are named after their file: the path without its .cedar extension,
+// such as "docs/read" for "docs/read.cedar", if the file holds a single
+// policy, or "<path>#<n>" for the n-th policy (counting from zero) if it holds
+// several. LoadPolicyDirFS names policies the same way. Each policy's
+// Position records its filename, and parse errors are prefixed with the
+// filename. Duplicate PolicyIDs are an error.
+func LoadPolicySetFS(fsys fs.FS, glob string) (*PolicySet, error) {
+	names, err := fs.Glob(fsys, glob)
+	if err != nil {
+		return nil, err
+	}
+	files := newPolicyFiles()
+	for _, name := range names {
+		if err := files.load(fsys, name, PolicyManifestEntry{}); err != nil {
+			return nil, err
+		}
+	}
+	return newPolicySet(files.policies), nil
+}
+
+// policyFiles collects the policies loaded from several files.
+type policyFiles struct {
+	policies PolicyMap
+	origins  map[PolicyID]string
+}
+
+func newPolicyFiles() *policyFiles {
+	return &policyFiles{policies: PolicyMap{}, origins: map[PolicyID]string{}}
+}
+
+// load parses the named file and adds its policies, with the annotations of
+// entry applied. A policy annotated with @id("...") uses that value as its
+// PolicyID. Other policies are named after entry.ID or, if it is empty, after
+// the file's path without its .cedar extension: the name itself if the file
+// holds a single policy, or "<name>#<n>" for the n-th policy (counting from
+// zero) if it holds several. An @id in the file that differs from the ID
+// that entry.ID gives is reported as a conflict, as are IDs already loaded
+// from another file, or earlier in this one.
+func (f *policyFiles) load(fsys fs.FS, name string, entry PolicyManifestEntry) error {
+	document, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	list, err := NewPolicyListFromBytes(name, document)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	base := entry.ID
+	if base == "" {
+		base = PolicyID(strings.TrimSuffix(name, ".cedar"))
+	}
+	var errs []error
+	for i, p := range list {
+		if annotated, ok := p.Annotations()["id"]; ok && entry.ID != "" {
+			if want := filePolicyID(base, i, len(list)); PolicyID(annotated) != want {
+				errs = append(errs, fmt.Errorf("%s: policy @id(%q) conflicts with ID %q from %s", name, annotated, want, ManifestFile))
+				continue
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(entry.Annotations)) {
+			p.ast.Annotate(types.Ident(k), types.String(entry.Annotations[k]))
+		}
+		id := filePolicyID(base, i, len(list))
+		if annotated, ok := p.Annotations()["id"]; ok {
+			id = PolicyID(annotated)
+		}
+		if prev, ok := f.origins[id]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicate policy ID %q, also used in %s", name, id, prev))
+			continue
+		}
+		f.origins[id] = name
+		f.policies[id] = p
+	}
+	return errors.Join(errs...)
+}
+
+// filePolicyID returns the PolicyID of the i-th of n policies loaded from a
+// file whose policies are named after base.
+func filePolicyID(base PolicyID, i, n int) PolicyID {
+	if n == 1 {
+		return base
+	}
+	return PolicyID(fmt.Sprintf("%s#%d", base, i))
+}
+
+// ManifestFile is the name of the optional manifest read by LoadPolicyDirFS.
+const ManifestFile = "manifest.json"
+
+// PolicyManifest is the format of a policy directory's manifest.json. Its
+// Policies map a file's path, relative to the directory, to the ID and
+// annotations of the policies in that file:
+//
+//	{
+//	    "policies": {
+//	        "docs/read.cedar": {"id": "allow-read", "annotations": {"owner": "docs-team"}}
+//	    }
+//	}
+type PolicyManifest struct {
+	Policies map[string]PolicyManifestEntry `json:"policies"`
+}
+
+// PolicyManifestEntry describes the policies of one file in a PolicyManifest.
+// An empty ID keeps the ID derived from the file name. Annotations are added
+// to each policy of the file, replacing annotations with the same key.
+type PolicyManifestEntry struct {
+	ID          PolicyID          `json:"id,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// LoadPolicyDir creates a PolicySet from the directory at dir, as described
+// in LoadPolicyDirFS.
+func LoadPolicyDir(dir string) (*PolicySet, error) {
+	return LoadPolicyDirFS(os.DirFS(dir))
+}
+
+// LoadPolicyDirFS creates a PolicySet from a directory that keeps its policies
+// in .cedar files, in any subdirectory. Policies are named as in
+// LoadPolicySetFS, except that a manifest.json at the root of fsys can give
+// the ID to use in place of a file's path. A policy in such a file may only
+// carry an @id("...") that matches the ID from the manifest.
+//
+// Every file is parsed before returning, and the parse errors of all files are
+// reported together, each prefixed with its filename. Duplicate policy IDs and
+// manifest entries without a matching file are reported the same way.
+func LoadPolicyDirFS(fsys fs.FS) (*PolicySet, error) {
+	manifest, err := readPolicyManifest(fsys)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && path.Ext(name) == ".cedar" {
+			names = append(names, name)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	files := newPolicyFiles()
+	for _, name := range names {
+		errs = append(errs, files.load(fsys, name, manifest.Policies[name]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(manifest.Policies)) {
+		if _, err := fs.Stat(fsys, name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ManifestFile, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return newPolicySet(files.policies), nil
+}
+
+func readPolicyManifest(fsys fs.FS) (PolicyManifest, error) {
+	var manifest PolicyManifest
+	b, err := fs.ReadFile(fsys, ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return manifest, fmt.Errorf("%s: %w", ManifestFile, err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,209 @@
+package cedar_test
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+)
+
+func TestLoadPolicySetFS(t *testing.T) {
+	t.Parallel()
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"policies/admin.cedar": {Data: []byte(`permit(principal in Group::"admins", action, resource);`)},
+			"policies/docs.cedar": {Data: []byte(`
+				permit(principal, action == Action::"view", resource);
+				@id("no-delete")
+				forbid(principal, action == Action::"delete", resource);
+				forbid(principal, action == Action::"purge", resource);
+			`)},
+			"policies/README.md": {Data: []byte(`not a policy`)},
+		}
+		ps, err := cedar.LoadPolicySetFS(fsys, "policies/*.cedar")
+		testutil.OK(t, err)
+		testutil.Equals(t, len(ps.Map()), 4)
+
+		admin := ps.Get("policies/admin")
+		testutil.FatalIf(t, admin == nil, "expected policy named after its file")
+		testutil.Equals(t, admin.Position().Filename, "policies/admin.cedar")
+		testutil.FatalIf(t, ps.Get("policies/docs#0") == nil, "expected first policy of docs.cedar")
+		testutil.FatalIf(t, ps.Get("no-delete") == nil, "expected policy named by @id")
+		testutil.FatalIf(t, ps.Get("policies/docs#2") == nil, "expected third policy of docs.cedar")
+	})
+
+	t.Run("parse-error-reports-filename", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar": {Data: []byte(`permit(principal, action, resource);`)},
+			"b.cedar": {Data: []byte(`permit(principal, action, resource`)},
+		}
+		_, err := cedar.LoadPolicySetFS(fsys, "*.cedar")
+		testutil.Error(t, err)
+		testutil.FatalIf(t, !strings.HasPrefix(err.Error(), "b.cedar: "), "expected filename in error: %v", err)
+	})
+
+	t.Run("duplicate-id", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar": {Data: []byte(`@id("p") permit(principal, action, resource);`)},
+			"b.cedar": {Data: []byte(`@id("p") forbid(principal, action, resource);`)},
+		}
+		_, err := cedar.LoadPolicySetFS(fsys, "*.cedar")
+		testutil.Error(t, err)
+		testutil.FatalIf(t, !strings.Contains(err.Error(), `duplicate policy ID "p"`), "unexpected error: %v", err)
+	})
+
+	t.Run("bad-glob", func(t *testing.T) {
+		t.Parallel()
+		_, err := cedar.LoadPolicySetFS(fstest.MapFS{}, "[")
+		testutil.Error(t, err)
+	})
+
+	t.Run("no-matches", func(t *testing.T) {
+		t.Parallel()
+		ps, err := cedar.LoadPolicySetFS(fstest.MapFS{}, "*.cedar")
+		testutil.OK(t, err)
+		testutil.Equals(t, len(ps.Map()), 0)
+	})
+}
+
+func TestLoadPolicyDirFS(t *testing.T) {
+	t.Parallel()
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"admin.cedar":     {Data: []byte(`permit(principal in Group::"admins", action, resource);`)},
+			"docs/read.cedar": {Data: []byte(`permit(principal, action == Action::"view", resource);`)},
+			"docs/write.cedar": {Data: []byte(`
+				permit(principal, action == Action::"edit", resource);
+				forbid(principal, action == Action::"delete", resource);
+			`)},
+			"README.md": {Data: []byte(`not a policy`)},
+			"manifest.json": {Data: []byte(`{"policies": {
+				"docs/read.cedar": {"id": "allow-read", "annotations": {"owner": "docs-team"}},
+				"docs/write.cedar": {"annotations": {"owner": "editors"}}
+			}}`)},
+		}
+		ps, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.OK(t, err)
+		testutil.Equals(t, len(ps.Map()), 4)
+
+		admin := ps.Get("admin")
+		testutil.FatalIf(t, admin == nil, "expected policy named after its file")
+		testutil.Equals(t, admin.Position().Filename, "admin.cedar")
+		testutil.Equals(t, len(admin.Annotations()), 0)
+
+		read := ps.Get("allow-read")
+		testutil.FatalIf(t, read == nil, "expected policy named by the manifest")
+		testutil.Equals(t, read.Annotations(), cedar.Annotations{"owner": "docs-team"})
+
+		for _, id := range []cedar.PolicyID{"docs/write#0", "docs/write#1"} {
+			p := ps.Get(id)
+			testutil.FatalIf(t, p == nil, "expected policy %s", id)
+			testutil.Equals(t, p.Annotations(), cedar.Annotations{"owner": "editors"})
+		}
+	})
+
+	t.Run("manifest-annotation-replaces-policy-annotation", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar":       {Data: []byte(`@owner("old") @reviewed("yes") permit(principal, action, resource);`)},
+			"manifest.json": {Data: []byte(`{"policies": {"a.cedar": {"annotations": {"owner": "new"}}}}`)},
+		}
+		ps, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.OK(t, err)
+		testutil.Equals(t, ps.Get("a").Annotations(), cedar.Annotations{"owner": "new", "reviewed": "yes"})
+	})
+
+	t.Run("same-ids-as-load-policy-set", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar": {Data: []byte(`permit(principal, action, resource);`)},
+			"b.cedar": {Data: []byte(`
+				permit(principal, action, resource);
+				@id("named")
+				forbid(principal, action, resource);
+			`)},
+		}
+		dir, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.OK(t, err)
+		set, err := cedar.LoadPolicySetFS(fsys, "*.cedar")
+		testutil.OK(t, err)
+		want := []cedar.PolicyID{"a", "b#0", "named"}
+		testutil.Equals(t, slices.Sorted(maps.Keys(dir.Map())), want)
+		testutil.Equals(t, slices.Sorted(maps.Keys(set.Map())), want)
+	})
+
+	t.Run("manifest-annotations-in-key-order", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar":       {Data: []byte(`permit(principal, action, resource);`)},
+			"manifest.json": {Data: []byte(`{"policies": {"a.cedar": {"annotations": {"c": "3", "a": "1", "b": "2"}}}}`)},
+		}
+		ps, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.OK(t, err)
+		got := string(ps.Get("a").MarshalCedar())
+		testutil.FatalIf(t, !strings.HasPrefix(got, "@a(\"1\")\n@b(\"2\")\n@c(\"3\")\n"), "annotations out of order: %s", got)
+	})
+
+	t.Run("aggregated-errors", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar":       {Data: []byte(`permit(principal, action, resource`)},
+			"b.cedar":       {Data: []byte(`permit(principal, action, resource);`)},
+			"c.cedar":       {Data: []byte(`forbid(principal, action`)},
+			"d.cedar":       {Data: []byte(`forbid(principal, action, resource);`)},
+			"manifest.json": {Data: []byte(`{"policies": {"d.cedar": {"id": "b"}, "missing.cedar": {"id": "m"}}}`)},
+		}
+		_, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.Error(t, err)
+		lines := strings.Split(err.Error(), "\n")
+		testutil.Equals(t, len(lines), 4)
+		testutil.FatalIf(t, !strings.HasPrefix(lines[0], "a.cedar: "), "expected a.cedar error: %v", lines[0])
+		testutil.FatalIf(t, !strings.HasPrefix(lines[1], "c.cedar: "), "expected c.cedar error: %v", lines[1])
+		testutil.Equals(t, lines[2], `d.cedar: duplicate policy ID "b", also used in b.cedar`)
+		testutil.FatalIf(t, !strings.HasPrefix(lines[3], "manifest.json: ") || !strings.Contains(lines[3], "missing.cedar"),
+			"expected missing manifest file error: %v", lines[3])
+	})
+
+	t.Run("manifest-id-conflicts-with-policy-id", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"a.cedar":       {Data: []byte(`@id("in-file") permit(principal, action, resource);`)},
+			"b.cedar":       {Data: []byte(`@id("from-manifest") permit(principal, action, resource);`)},
+			"manifest.json": {Data: []byte(`{"policies": {"a.cedar": {"id": "from-manifest"}, "b.cedar": {"id": "from-manifest"}}}`)},
+		}
+		_, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.Error(t, err)
+		testutil.Equals(t, err.Error(), `a.cedar: policy @id("in-file") conflicts with ID "from-manifest" from manifest.json`)
+
+		delete(fsys, "a.cedar")
+		fsys["manifest.json"] = &fstest.MapFile{Data: []byte(`{"policies": {"b.cedar": {"id": "from-manifest"}}}`)}
+		ps, err := cedar.LoadPolicyDirFS(fsys)
+		testutil.OK(t, err)
+		testutil.FatalIf(t, ps.Get("from-manifest") == nil, "expected a matching @id to be accepted")
+	})
+
+	t.Run("bad-manifest", func(t *testing.T) {
+		t.Parallel()
+		_, err := cedar.LoadPolicyDirFS(fstest.MapFS{"manifest.json": {Data: []byte(`{`)}})
+		testutil.Error(t, err)
+		testutil.FatalIf(t, !strings.HasPrefix(err.Error(), "manifest.json: "), "unexpected error: %v", err)
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		t.Parallel()
+		ps, err := cedar.LoadPolicyDir(t.TempDir())
+		testutil.OK(t, err)
+		testutil.Equals(t, len(ps.Map()), 0)
+
+		_, err = cedar.LoadPolicyDir("does-not-exist")
+		testutil.Error(t, err)
+	})
+}
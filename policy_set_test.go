@@ -1,6 +1,7 @@
 package cedar_test
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/cedar-policy/cedar-go"
 	"github.com/cedar-policy/cedar-go/ast"
 	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
 )
 
 func TestPolicyMap(t *testing.T) {
@@ -221,3 +223,71 @@ func TestAll(t *testing.T) {
 		}
 	})
 }
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	ps := cedar.NewPolicySet()
+	for _, id := range []cedar.PolicyID{"policy2", "policy0", "policy1"} {
+		ps.Add(id, cedar.NewPolicyFromAST(ast.Forbid()))
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		t.Parallel()
+		var got []cedar.PolicyID
+		err := ps.ForEach(func(id cedar.PolicyID, p *cedar.Policy) error {
+			testutil.Equals(t, p, ps.Get(id))
+			got = append(got, id)
+			return nil
+		})
+		testutil.OK(t, err)
+		testutil.Equals(t, got, []cedar.PolicyID{"policy0", "policy1", "policy2"})
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		t.Parallel()
+		wantErr := errors.New("lint failed")
+		var got []cedar.PolicyID
+		err := ps.ForEach(func(id cedar.PolicyID, _ *cedar.Policy) error {
+			got = append(got, id)
+			if id == "policy1" {
+				return wantErr
+			}
+			return nil
+		})
+		testutil.Equals(t, err, wantErr)
+		testutil.Equals(t, got, []cedar.PolicyID{"policy0", "policy1"})
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		err := cedar.NewPolicySet().ForEach(func(cedar.PolicyID, *cedar.Policy) error {
+			return errors.New("unexpected call")
+		})
+		testutil.OK(t, err)
+	})
+}
+
+func TestReferencedUIDs(t *testing.T) {
+	t.Parallel()
+	ps, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+		permit(principal in Group::"deleted-group", action in [Action::"view", Action::"edit"], resource);
+		permit(principal == App::User::"alice", action == App::Action::"view", resource is Doc in Folder::"shared")
+		when { resource.owner == App::User::"alice" || context.approvers.contains(App::User::"bob") }
+		unless { principal in [Group::"banned", Group::"deleted-group"] || context.meta == {by: App::User::"carol"} };
+		forbid(principal, action, resource is Doc);
+	`))
+	testutil.OK(t, err)
+	testutil.Equals(t, ps.ReferencedUIDs(), []types.EntityUID{
+		types.NewEntityUID("Action", "edit"),
+		types.NewEntityUID("Action", "view"),
+		types.NewEntityUID("App::Action", "view"),
+		types.NewEntityUID("App::User", "alice"),
+		types.NewEntityUID("App::User", "bob"),
+		types.NewEntityUID("App::User", "carol"),
+		types.NewEntityUID("Folder", "shared"),
+		types.NewEntityUID("Group", "banned"),
+		types.NewEntityUID("Group", "deleted-group"),
+	})
+	testutil.Equals(t, len(cedar.NewPolicySet().ReferencedUIDs()), 0)
+}
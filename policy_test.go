@@ -94,6 +94,163 @@ func TestPolicyAST(t *testing.T) {
 	_ = cedar.NewPolicyFromAST(astExample)
 }
 
+func TestPolicyScopes(t *testing.T) {
+	t.Parallel()
+
+	alice := cedar.NewEntityUID("User", "alice")
+	admins := cedar.NewEntityUID("Group", "admins")
+	view := cedar.NewEntityUID("Action", "view")
+	edit := cedar.NewEntityUID("Action", "edit")
+	photo := cedar.NewEntityUID("Photo", "vacation.jpg")
+
+	tests := []struct {
+		name      string
+		policy    string
+		effect    cedar.Effect
+		principal cedar.Scope
+		action    cedar.Scope
+		resource  cedar.Scope
+	}{
+		{
+			"all",
+			`permit(principal, action, resource);`,
+			cedar.Permit,
+			cedar.Scope{Kind: cedar.ScopeAll},
+			cedar.Scope{Kind: cedar.ScopeAll},
+			cedar.Scope{Kind: cedar.ScopeAll},
+		},
+		{
+			"eq",
+			`forbid(principal == User::"alice", action == Action::"view", resource == Photo::"vacation.jpg");`,
+			cedar.Forbid,
+			cedar.Scope{Kind: cedar.ScopeEq, Entity: alice},
+			cedar.Scope{Kind: cedar.ScopeEq, Entity: view},
+			cedar.Scope{Kind: cedar.ScopeEq, Entity: photo},
+		},
+		{
+			"in",
+			`permit(principal in Group::"admins", action in [Action::"view", Action::"edit"], resource in Photo::"vacation.jpg");`,
+			cedar.Permit,
+			cedar.Scope{Kind: cedar.ScopeIn, Entity: admins},
+			cedar.Scope{Kind: cedar.ScopeInSet, Entities: []cedar.EntityUID{view, edit}},
+			cedar.Scope{Kind: cedar.ScopeIn, Entity: photo},
+		},
+		{
+			"is",
+			`permit(principal is User, action in Action::"view", resource is Photo in Photo::"vacation.jpg");`,
+			cedar.Permit,
+			cedar.Scope{Kind: cedar.ScopeIs, Type: "User"},
+			cedar.Scope{Kind: cedar.ScopeIn, Entity: view},
+			cedar.Scope{Kind: cedar.ScopeIsIn, Type: "Photo", Entity: photo},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var p cedar.Policy
+			testutil.OK(t, p.UnmarshalCedar([]byte(tt.policy)))
+			testutil.Equals(t, p.Effect(), tt.effect)
+			testutil.Equals(t, p.PrincipalScope(), tt.principal)
+			testutil.Equals(t, p.ActionScope(), tt.action)
+			testutil.Equals(t, p.ResourceScope(), tt.resource)
+		})
+	}
+}
+
+func TestPolicyScopeSignature(t *testing.T) {
+	t.Parallel()
+
+	signature := func(t *testing.T, policy string) cedar.ScopeSignature {
+		t.Helper()
+		var p cedar.Policy
+		testutil.OK(t, p.UnmarshalCedar([]byte(policy)))
+		return p.ScopeSignature()
+	}
+
+	tests := []struct {
+		name   string
+		policy string
+		want   cedar.ScopeSignature
+	}{
+		{
+			"all",
+			`permit(principal, action, resource);`,
+			cedar.ScopeSignature{},
+		},
+		{
+			"eq",
+			`permit(principal == User::"alice", action == Action::"view", resource == Photo::"a.jpg");`,
+			cedar.ScopeSignature{
+				PrincipalKind: cedar.ScopeEq, PrincipalType: "User",
+				ActionKind: cedar.ScopeEq, Actions: `Action::"view"`,
+				ResourceKind: cedar.ScopeEq, ResourceType: "Photo",
+			},
+		},
+		{
+			"in",
+			`permit(principal in Group::"admins", action in [Action::"view", Action::"edit"], resource in Album::"trip");`,
+			cedar.ScopeSignature{
+				PrincipalKind: cedar.ScopeIn,
+				ActionKind:    cedar.ScopeInSet, Actions: `Action::"edit", Action::"view"`,
+				ResourceKind: cedar.ScopeIn,
+			},
+		},
+		{
+			"isAndIsIn",
+			`permit(principal is User, action in Action::"read", resource is Photo in Album::"trip");`,
+			cedar.ScopeSignature{
+				PrincipalKind: cedar.ScopeIs, PrincipalType: "User",
+				ActionKind: cedar.ScopeIn, Actions: `Action::"read"`,
+				ResourceKind: cedar.ScopeIsIn, ResourceType: "Photo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testutil.Equals(t, signature(t, tt.policy), tt.want)
+		})
+	}
+
+	t.Run("conditionsIgnored", func(t *testing.T) {
+		t.Parallel()
+		a := signature(t, `permit(principal is User, action == Action::"view", resource) when { principal.active };`)
+		b := signature(t, `forbid(principal is User, action == Action::"view", resource) unless { false };`)
+		testutil.Equals(t, a, b)
+	})
+
+	t.Run("actionOrderAndDuplicatesIgnored", func(t *testing.T) {
+		t.Parallel()
+		a := signature(t, `permit(principal, action in [Action::"b", Action::"a"], resource);`)
+		b := signature(t, `permit(principal, action in [Action::"a", Action::"b", Action::"a"], resource);`)
+		testutil.Equals(t, a, b)
+	})
+
+	t.Run("usableAsMapKey", func(t *testing.T) {
+		t.Parallel()
+		buckets := map[cedar.ScopeSignature]int{}
+		for _, p := range []string{
+			`permit(principal == User::"alice", action == Action::"view", resource);`,
+			`permit(principal == User::"bob", action == Action::"view", resource);`,
+			`permit(principal is User, action == Action::"view", resource);`,
+		} {
+			buckets[signature(t, p)]++
+		}
+		testutil.Equals(t, len(buckets), 2)
+	})
+}
+
+func TestScopeKindString(t *testing.T) {
+	t.Parallel()
+	testutil.Equals(t, cedar.ScopeAll.String(), "all")
+	testutil.Equals(t, cedar.ScopeEq.String(), "==")
+	testutil.Equals(t, cedar.ScopeIn.String(), "in")
+	testutil.Equals(t, cedar.ScopeInSet.String(), "in")
+	testutil.Equals(t, cedar.ScopeIs.String(), "is")
+	testutil.Equals(t, cedar.ScopeIsIn.String(), "is in")
+	testutil.Equals(t, cedar.ScopeKind(99).String(), "unknown")
+}
+
 func TestUnmarshalJSONPolicyErr(t *testing.T) {
 	t.Parallel()
 	var p cedar.Policy
@@ -125,3 +282,56 @@ func TestPositionJSON(t *testing.T) {
 
 	testutil.Equals(t, unmarshaled, p)
 }
+
+func TestPolicyScopeMatches(t *testing.T) {
+	t.Parallel()
+	alice := cedar.NewEntityUID("User", "alice")
+	bob := cedar.NewEntityUID("User", "bob")
+	admins := cedar.NewEntityUID("Group", "admins")
+	view := cedar.NewEntityUID("Action", "view")
+	edit := cedar.NewEntityUID("Action", "edit")
+	read := cedar.NewEntityUID("Action", "read")
+	photo := cedar.NewEntityUID("Photo", "a.jpg")
+	album := cedar.NewEntityUID("Album", "trip")
+	entities := cedar.EntityMap{
+		alice: {UID: alice, Parents: cedar.NewEntityUIDSet(admins)},
+		view:  {UID: view, Parents: cedar.NewEntityUIDSet(read)},
+		photo: {UID: photo, Parents: cedar.NewEntityUIDSet(album)},
+	}
+	aliceViews := cedar.Request{Principal: alice, Action: view, Resource: photo}
+	bobEdits := cedar.Request{Principal: bob, Action: edit, Resource: album}
+
+	tests := []struct {
+		name       string
+		policy     string
+		aliceViews bool
+		bobEdits   bool
+	}{
+		{"all", `permit(principal, action, resource);`, true, true},
+		{"eq", `permit(principal == User::"alice", action, resource);`, true, false},
+		{"in", `permit(principal in Group::"admins", action, resource);`, true, false},
+		{"actionGroup", `permit(principal, action in Action::"read", resource);`, true, false},
+		{"actionSet", `permit(principal, action in [Action::"read", Action::"edit"], resource);`, true, true},
+		{"is", `permit(principal, action, resource is Album);`, false, true},
+		{"isIn", `permit(principal, action, resource is Photo in Album::"trip");`, true, false},
+		{"conditionsIgnored", `forbid(principal, action, resource) when { false } unless { true };`, true, true},
+		{"conditionErrorIgnored", `permit(principal, action, resource) when { principal.missing };`, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var p cedar.Policy
+			testutil.OK(t, p.UnmarshalCedar([]byte(tt.policy)))
+			testutil.Equals(t, p.ScopeMatches(entities, aliceViews), tt.aliceViews)
+			testutil.Equals(t, p.ScopeMatches(entities, bobEdits), tt.bobEdits)
+		})
+	}
+
+	t.Run("nilEntities", func(t *testing.T) {
+		t.Parallel()
+		var p cedar.Policy
+		testutil.OK(t, p.UnmarshalCedar([]byte(`permit(principal in Group::"admins", action == Action::"view", resource);`)))
+		testutil.Equals(t, p.ScopeMatches(nil, aliceViews), false)
+		testutil.Equals(t, p.ScopeMatches(nil, cedar.Request{Principal: admins, Action: view}), true)
+	})
+}
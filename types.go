@@ -47,6 +47,7 @@ type EntityGetter = types.EntityGetter
 type Value = types.Value
 
 type Request = types.Request
+type ContextProvider = types.ContextProvider
 type Decision = types.Decision
 type Diagnostic = types.Diagnostic
 type DiagnosticReason = types.DiagnosticReason
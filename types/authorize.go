@@ -11,6 +11,14 @@ type Request struct {
 	Context   Record    `json:"context"`
 }
 
+// A ContextProvider supplies context attributes lazily, so that an expensive
+// attribute is only computed if a policy reads it. Get reports whether the
+// attribute exists; a non-nil error fails the policy that accessed it, as any
+// other evaluation error would.
+type ContextProvider interface {
+	Get(key string) (Value, bool, error)
+}
+
 // Equal comapres two requests for sameness
 func (r Request) Equal(other Request) bool {
 	return (r.Principal == other.Principal &&
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// CanonicalizeEntitiesJSON parses a JSON array of entities and re-encodes it
+// in a canonical form: entities sorted by UID, parents sorted, record keys
+// sorted and set elements sorted by their canonical encoding. Two inputs that
+// describe the same entities produce byte-identical output, which makes the
+// result suitable for storing, diffing and hashing entity snapshots.
+func CanonicalizeEntitiesJSON(b []byte) ([]byte, error) {
+	var entities EntityMap
+	if err := json.Unmarshal(b, &entities); err != nil {
+		return nil, err
+	}
+	list := slices.SortedFunc(maps.Values(entities), func(a, b Entity) int {
+		return a.UID.Compare(b.UID)
+	})
+	var w bytes.Buffer
+	w.WriteByte('[')
+	for i, e := range list {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		eb, err := canonicalEntityJSON(e)
+		if err != nil {
+			return nil, err
+		}
+		w.Write(eb)
+	}
+	w.WriteByte(']')
+	return w.Bytes(), nil
+}
+
+func canonicalEntityJSON(e Entity) ([]byte, error) {
+	attrs, err := canonicalValueJSON(e.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := canonicalValueJSON(e.Tags)
+	if err != nil {
+		return nil, err
+	}
+	parents := make([]ImplicitlyMarshaledEntityUID, 0, e.Parents.Len())
+	for p := range e.Parents.All() {
+		parents = append(parents, ImplicitlyMarshaledEntityUID(p))
+	}
+	slices.SortFunc(parents, func(a, b ImplicitlyMarshaledEntityUID) int {
+		return EntityUID(a).Compare(EntityUID(b))
+	})
+	return json.Marshal(struct {
+		UID        ImplicitlyMarshaledEntityUID   `json:"uid"`
+		Parents    []ImplicitlyMarshaledEntityUID `json:"parents"`
+		Attributes json.RawMessage                `json:"attrs"`
+		Tags       json.RawMessage                `json:"tags"`
+	}{ImplicitlyMarshaledEntityUID(e.UID), parents, attrs, tags})
+}
+
+// canonicalValueJSON encodes v like json.Marshal, except that the elements
+// of sets, including sets nested in records and other sets, are sorted by
+// their own canonical encoding rather than by hash.
+func canonicalValueJSON(v Value) ([]byte, error) {
+	switch v := v.(type) {
+	case Set:
+		elems := make([][]byte, 0, v.Len())
+		for elem := range v.All() {
+			eb, err := canonicalValueJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, eb)
+		}
+		slices.SortFunc(elems, bytes.Compare)
+		return append(append([]byte{'['}, bytes.Join(elems, []byte{','})...), ']'), nil
+	case Record:
+		var w bytes.Buffer
+		w.WriteByte('{')
+		for i, k := range slices.Sorted(v.Keys()) {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			kb, _ := json.Marshal(k) // json.Marshal cannot error on strings
+			w.Write(kb)
+			w.WriteByte(':')
+			vv, _ := v.Get(k)
+			vb, err := canonicalValueJSON(vv)
+			if err != nil {
+				return nil, err
+			}
+			w.Write(vb)
+		}
+		w.WriteByte('}')
+		return w.Bytes(), nil
+	}
+	return json.Marshal(v)
+}
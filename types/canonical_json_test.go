@@ -0,0 +1,81 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestCanonicalizeEntitiesJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SameEntitiesSameBytes", func(t *testing.T) {
+		t.Parallel()
+		a := []byte(`[
+			{"uid": {"type": "User", "id": "bob"}, "parents": [], "attrs": {}},
+			{
+				"uid": {"type": "User", "id": "alice"},
+				"parents": [{"type": "Group", "id": "staff"}, {"type": "Group", "id": "admins"}],
+				"attrs": {
+					"tags": ["zeta", "alpha", "mu"],
+					"age": 42,
+					"manager": {"__entity": {"type": "User", "id": "bob"}},
+					"prefs": {"z": [3, 1, 2], "a": true}
+				},
+				"tags": {"level": ["b", "a"]}
+			}
+		]`)
+		b := []byte(`[
+			{
+				"tags": {"level": ["a", "b"]},
+				"attrs": {
+					"prefs": {"a": true, "z": [2, 3, 1]},
+					"manager": {"__entity": {"id": "bob", "type": "User"}},
+					"age": 42,
+					"tags": ["mu", "zeta", "alpha"]
+				},
+				"parents": [{"type": "Group", "id": "admins"}, {"type": "Group", "id": "staff"}],
+				"uid": {"type": "User", "id": "alice"}
+			},
+			{"uid": {"type": "User", "id": "bob"}, "parents": []}
+		]`)
+		ca, err := types.CanonicalizeEntitiesJSON(a)
+		testutil.OK(t, err)
+		cb, err := types.CanonicalizeEntitiesJSON(b)
+		testutil.OK(t, err)
+		testutil.Equals(t, string(ca), string(cb))
+		testutil.Equals(t, string(ca), `[`+
+			`{"uid":{"type":"User","id":"alice"},`+
+			`"parents":[{"type":"Group","id":"admins"},{"type":"Group","id":"staff"}],`+
+			`"attrs":{"age":42,"manager":{"__entity":{"type":"User","id":"bob"}},"prefs":{"a":true,"z":[1,2,3]},"tags":["alpha","mu","zeta"]},`+
+			`"tags":{"level":["a","b"]}},`+
+			`{"uid":{"type":"User","id":"bob"},"parents":[],"attrs":{},"tags":{}}`+
+			`]`)
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		t.Parallel()
+		in := []byte(`[{"uid":{"type":"Doc","id":"d"},"parents":[],"attrs":{"ip":{"__extn":{"fn":"ip","arg":"10.0.0.1"}},"sets":[["b","a"],["c"]]}}]`)
+		once, err := types.CanonicalizeEntitiesJSON(in)
+		testutil.OK(t, err)
+		twice, err := types.CanonicalizeEntitiesJSON(once)
+		testutil.OK(t, err)
+		testutil.Equals(t, string(twice), string(once))
+		testutil.Equals(t, string(once), `[{"uid":{"type":"Doc","id":"d"},"parents":[],`+
+			`"attrs":{"ip":{"__extn":{"fn":"ip","arg":"10.0.0.1"}},"sets":[["a","b"],["c"]]},"tags":{}}]`)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		out, err := types.CanonicalizeEntitiesJSON([]byte(`[]`))
+		testutil.OK(t, err)
+		testutil.Equals(t, string(out), `[]`)
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		t.Parallel()
+		_, err := types.CanonicalizeEntitiesJSON([]byte(`{"not": "a list"}`))
+		testutil.Error(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -320,6 +321,19 @@ func (d Datetime) LessThanOrEqual(bi Value) (bool, error) {
 	return d.value <= b.value, nil
 }
 
+// Compare returns
+//
+//	-1 if d is before other,
+//	 0 if d equals other,
+//	+1 if d is after other.
+//
+// This is the ordering Cedar's <, <=, >, and >= operators use for datetimes.
+// Datetimes have millisecond precision, so two values created from times that
+// differ by less than a millisecond compare equal.
+func (d Datetime) Compare(other Datetime) int {
+	return cmp.Compare(d.value, other.value)
+}
+
 // MarshalCedar returns a []byte which, when parsed by the Cedar
 // Parser, returns an Equal Datetime value
 func (d Datetime) MarshalCedar() []byte {
@@ -3,6 +3,7 @@ package types_test
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -243,6 +244,41 @@ func TestDatetime(t *testing.T) {
 		}
 	})
 
+	t.Run("Compare", func(t *testing.T) {
+		t.Parallel()
+		minimum := types.NewDatetimeFromMillis(math.MinInt64)
+		neg := types.NewDatetimeFromMillis(-1)
+		zero := types.NewDatetimeFromMillis(0)
+		one := types.NewDatetimeFromMillis(1)
+		maximum := types.NewDatetimeFromMillis(math.MaxInt64)
+
+		tests := []struct {
+			l, r types.Datetime
+			want int
+		}{
+			{zero, zero, 0},
+			{zero, one, -1},
+			{one, zero, 1},
+			{neg, zero, -1},
+			{minimum, maximum, -1},
+			{maximum, minimum, 1},
+			{minimum, minimum, 0},
+			{maximum, neg, 1},
+		}
+
+		for ti, tt := range tests {
+			t.Run(fmt.Sprintf("Compare_%d_%v_%v", ti, tt.l, tt.r), func(t *testing.T) {
+				t.Parallel()
+				got := tt.l.Compare(tt.r)
+				testutil.Equals(t, got, tt.want)
+				lt, err := tt.l.LessThan(tt.r)
+				testutil.OK(t, err)
+				testutil.Equals(t, lt, got < 0)
+				testutil.Equals(t, tt.l.Equal(tt.r), got == 0)
+			})
+		}
+	})
+
 	t.Run("MarshalCedar", func(t *testing.T) {
 		t.Parallel()
 		tests := []struct {
@@ -2,6 +2,7 @@ package types
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -185,6 +186,18 @@ func (d Duration) LessThanOrEqual(bi Value) (bool, error) {
 	return d.value <= b.value, nil
 }
 
+// Compare returns
+//
+//	-1 if d is shorter than other,
+//	 0 if d equals other,
+//	+1 if d is longer than other.
+//
+// This is the ordering Cedar's <, <=, >, and >= operators use for durations.
+// Durations have millisecond precision and may be negative.
+func (d Duration) Compare(other Duration) int {
+	return cmp.Compare(d.value, other.value)
+}
+
 // MarshalCedar produces a valid MarshalCedar language representation of the Duration, e.g. `decimal("12.34")`.
 func (d Duration) MarshalCedar() []byte { return []byte(`duration("` + d.String() + `")`) }
 
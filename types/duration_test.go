@@ -189,6 +189,41 @@ func TestDuration(t *testing.T) {
 		testutil.Equals(t, dur.ToMilliseconds(), 95503017)
 	})
 
+	t.Run("Compare", func(t *testing.T) {
+		t.Parallel()
+		minimum := types.NewDurationFromMillis(math.MinInt64)
+		neg := types.NewDurationFromMillis(-1)
+		zero := types.NewDurationFromMillis(0)
+		one := types.NewDurationFromMillis(1)
+		maximum := types.NewDurationFromMillis(math.MaxInt64)
+
+		tests := []struct {
+			l, r types.Duration
+			want int
+		}{
+			{zero, zero, 0},
+			{zero, one, -1},
+			{one, zero, 1},
+			{neg, zero, -1},
+			{minimum, maximum, -1},
+			{maximum, minimum, 1},
+			{minimum, minimum, 0},
+			{maximum, neg, 1},
+		}
+
+		for ti, tt := range tests {
+			t.Run(fmt.Sprintf("Compare_%d_%v_%v", ti, tt.l, tt.r), func(t *testing.T) {
+				t.Parallel()
+				got := tt.l.Compare(tt.r)
+				testutil.Equals(t, got, tt.want)
+				lt, err := tt.l.LessThan(tt.r)
+				testutil.OK(t, err)
+				testutil.Equals(t, lt, got < 0)
+				testutil.Equals(t, tt.l.Equal(tt.r), got == 0)
+			})
+		}
+	})
+
 	t.Run("MarshalCedar", func(t *testing.T) {
 		t.Parallel()
 		testutil.Equals(t, string(types.NewDuration(42*time.Millisecond).MarshalCedar()), `duration("42ms")`)
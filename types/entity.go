@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"iter"
 	"slices"
 	"strings"
 )
@@ -43,6 +44,27 @@ func (e Entity) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// Tag returns the value of the entity's tag named key and whether it is set.
+func (e Entity) Tag(key string) (Value, bool) {
+	return e.Tags.Get(String(key))
+}
+
+// AllTags returns an iterator over the entity's tags. An entity without tags
+// yields nothing. Iteration order is non-deterministic.
+func (e Entity) AllTags() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for k, v := range e.Tags.All() {
+			if !yield(string(k), v) {
+				return
+			}
+		}
+	}
+}
+
+// Equal reports whether two entities have the same UID, parents, attributes
+// and tags. It compares entity data, which is useful when diffing entity
+// snapshots; to test whether two entities are the same Cedar entity, compare
+// their UIDs with [EntityUID.Equal].
 func (e Entity) Equal(other Entity) bool {
 	return e.UID.Equal(other.UID) &&
 		e.Parents.Equal(other.Parents) &&
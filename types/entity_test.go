@@ -1,6 +1,8 @@
 package types_test
 
 import (
+	"encoding/json"
+	"maps"
 	"testing"
 	"time"
 
@@ -191,3 +193,64 @@ func TestEntityEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestEntityTags(t *testing.T) {
+	t.Parallel()
+	e := types.Entity{
+		UID: types.NewEntityUID("FooType", "1"),
+		Tags: types.NewRecord(types.RecordMap{
+			"key":    types.String("value"),
+			"entity": types.NewEntityUID("BarType", "1"),
+		}),
+	}
+
+	t.Run("Tag", func(t *testing.T) {
+		t.Parallel()
+		v, ok := e.Tag("key")
+		testutil.Equals(t, ok, true)
+		testutil.Equals(t, v, types.Value(types.String("value")))
+		_, ok = e.Tag("missing")
+		testutil.Equals(t, ok, false)
+	})
+
+	t.Run("AllTags", func(t *testing.T) {
+		t.Parallel()
+		got := maps.Collect(e.AllTags())
+		testutil.Equals(t, got, map[string]types.Value{
+			"key":    types.String("value"),
+			"entity": types.NewEntityUID("BarType", "1"),
+		})
+	})
+
+	t.Run("AllTagsBreak", func(t *testing.T) {
+		t.Parallel()
+		n := 0
+		for range e.AllTags() {
+			n++
+			break
+		}
+		testutil.Equals(t, n, 1)
+	})
+
+	t.Run("NoTags", func(t *testing.T) {
+		t.Parallel()
+		var empty types.Entity
+		for range empty.AllTags() {
+			t.Fatal("expected no tags")
+		}
+		_, ok := empty.Tag("key")
+		testutil.Equals(t, ok, false)
+	})
+
+	t.Run("JSONRoundTrip", func(t *testing.T) {
+		t.Parallel()
+		b, err := json.Marshal(e)
+		testutil.OK(t, err)
+		var got types.Entity
+		testutil.OK(t, json.Unmarshal(b, &got))
+		testutil.Equals(t, maps.Collect(got.AllTags()), maps.Collect(e.AllTags()))
+		v, ok := got.Tag("entity")
+		testutil.Equals(t, ok, true)
+		testutil.Equals(t, v, types.Value(types.NewEntityUID("BarType", "1")))
+	})
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"hash/fnv"
@@ -30,11 +31,22 @@ func NewEntityUID(typ EntityType, id String) EntityUID {
 	}
 }
 
+// Compare orders entity UIDs by type and then by ID. It returns -1, 0 or +1
+// depending on whether e sorts before, with or after other.
+func (e EntityUID) Compare(other EntityUID) int {
+	return cmp.Or(strings.Compare(string(e.Type), string(other.Type)), strings.Compare(string(e.ID), string(other.ID)))
+}
+
 // IsZero returns true if the EntityUID has an empty Type and ID.
 func (e EntityUID) IsZero() bool {
 	return e.Type == "" && e.ID == ""
 }
 
+// Equal reports whether bi is an EntityUID with the same Type and ID. This is
+// the only notion of entity equality in Cedar: the `==` operator and entity
+// comparisons during evaluation look only at the UID, never at the attributes,
+// parents or tags an entity has in a particular EntityMap. Two entities from
+// different snapshots with the same UID are therefore the same entity.
 func (e EntityUID) Equal(bi Value) bool {
 	b, ok := bi.(EntityUID)
 	return ok && e == b
@@ -1,6 +1,7 @@
 package types_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go"
@@ -19,6 +20,7 @@ func TestEntity(t *testing.T) {
 		testutil.FatalIf(t, !twoElems.Equal(twoElems), "%v not Equal to %v", twoElems, twoElems)
 		testutil.FatalIf(t, !twoElems.Equal(twoElems2), "%v not Equal to %v", twoElems, twoElems2)
 		testutil.FatalIf(t, twoElems.Equal(differentValues), "%v Equal to %v", twoElems, differentValues)
+		testutil.FatalIf(t, twoElems.Equal(types.String(twoElems.String())), "%v Equal to its string form", twoElems)
 	})
 
 	t.Run("string", func(t *testing.T) {
@@ -27,6 +29,37 @@ func TestEntity(t *testing.T) {
 		testutil.Equals(t, types.EntityUID{Type: "namespace::type", ID: "id"}.String(), `namespace::type::"id"`)
 	})
 
+	t.Run("Compare", func(t *testing.T) {
+		t.Parallel()
+		a := types.NewEntityUID("A", "z")
+		b := types.NewEntityUID("B", "a")
+		testutil.Equals(t, a.Compare(b), -1)
+		testutil.Equals(t, b.Compare(a), 1)
+		testutil.Equals(t, a.Compare(a), 0)
+		testutil.Equals(t, a.Compare(types.NewEntityUID("A", "zz")), -1)
+	})
+
+	t.Run("EntityUIDsIn", func(t *testing.T) {
+		t.Parallel()
+		alice := types.NewEntityUID("User", "alice")
+		bob := types.NewEntityUID("User", "bob")
+		doc := types.NewEntityUID("Doc", "d")
+		v := types.NewRecord(types.RecordMap{
+			"owner":   alice,
+			"readers": types.NewSet(bob, types.NewRecord(types.RecordMap{"doc": doc})),
+			"name":    types.String("x"),
+		})
+		got := slices.SortedFunc(types.EntityUIDsIn(v), types.EntityUID.Compare)
+		testutil.Equals(t, got, []types.EntityUID{doc, alice, bob})
+
+		var first []types.EntityUID
+		for uid := range types.EntityUIDsIn(v) {
+			first = append(first, uid)
+			break
+		}
+		testutil.Equals(t, len(first), 1)
+	})
+
 	t.Run("Marshal EntityUID round trip", func(t *testing.T) {
 		t.Parallel()
 		testCases := []struct {
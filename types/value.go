@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"iter"
 )
 
 // Value defines the interface for all Cedar values (String, Long, Set, Record, Boolean, etc ...)
@@ -15,3 +16,32 @@ type Value interface {
 	Equal(Value) bool
 	hash() uint64
 }
+
+// EntityUIDsIn returns an iterator over the entity UIDs in v, including those
+// nested in sets and records. A UID is yielded each time it occurs, in no
+// particular order.
+func EntityUIDsIn(v Value) iter.Seq[EntityUID] {
+	return func(yield func(EntityUID) bool) {
+		yieldEntityUIDs(v, yield)
+	}
+}
+
+func yieldEntityUIDs(v Value, yield func(EntityUID) bool) bool {
+	switch v := v.(type) {
+	case EntityUID:
+		return yield(v)
+	case Set:
+		for e := range v.All() {
+			if !yieldEntityUIDs(e, yield) {
+				return false
+			}
+		}
+	case Record:
+		for e := range v.Values() {
+			if !yieldEntityUIDs(e, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
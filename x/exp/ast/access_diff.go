@@ -0,0 +1,171 @@
+package ast
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// AccessDiffResult lists what a new version of a policy reads that the old
+// version did not, and the reverse. Every list is sorted, so reordering or
+// repeating clauses does not produce spurious changes.
+type AccessDiffResult struct {
+	// AddedAttributes and RemovedAttributes hold attribute paths that are
+	// accessed or tested with has, such as "principal.manager.ssn", and tag
+	// lookups, such as `resource.getTag("owner")`. A path is rooted at a
+	// variable or an entity literal. Any other root, such as an if-then-else
+	// expression, and any tag key that is not a string literal are written
+	// as "*", as in "*.owner" or "resource.getTag(*)".
+	AddedAttributes, RemovedAttributes []string
+
+	// AddedEntityTypes and RemovedEntityTypes hold the entity types named in
+	// the scope, in entity literals and in is tests.
+	AddedEntityTypes, RemovedEntityTypes []types.EntityType
+
+	// AddedExtensions and RemovedExtensions hold the names of the extension
+	// functions and methods called, such as "ip" or "isInRange".
+	AddedExtensions, RemovedExtensions []string
+}
+
+// IsEmpty reports whether the two versions read the same data.
+func (d AccessDiffResult) IsEmpty() bool {
+	return len(d.AddedAttributes) == 0 && len(d.RemovedAttributes) == 0 &&
+		len(d.AddedEntityTypes) == 0 && len(d.RemovedEntityTypes) == 0 &&
+		len(d.AddedExtensions) == 0 && len(d.RemovedExtensions) == 0
+}
+
+// AccessDiff compares the attributes, entity types and extension functions
+// referenced by two versions of a policy, for example to flag in review that
+// a change makes the policy read principal.ssn. Each version is walked with
+// Inspect and its references are compared as sets.
+func AccessDiff(oldPolicy, newPolicy *Policy) AccessDiffResult {
+	before, after := collectAccess(oldPolicy), collectAccess(newPolicy)
+	var d AccessDiffResult
+	d.AddedAttributes, d.RemovedAttributes = setDiff(before.attributes, after.attributes)
+	d.AddedEntityTypes, d.RemovedEntityTypes = setDiff(before.entityTypes, after.entityTypes)
+	d.AddedExtensions, d.RemovedExtensions = setDiff(before.extensions, after.extensions)
+	return d
+}
+
+// policyAccess holds the references collected from one policy.
+type policyAccess struct {
+	attributes  map[string]bool
+	entityTypes map[types.EntityType]bool
+	extensions  map[string]bool
+}
+
+func collectAccess(p *Policy) policyAccess {
+	a := policyAccess{
+		attributes:  map[string]bool{},
+		entityTypes: map[types.EntityType]bool{},
+		extensions:  map[string]bool{},
+	}
+	if p == nil {
+		return a
+	}
+	for _, scope := range []any{p.Principal, p.Action, p.Resource} {
+		a.addScope(scope)
+	}
+	for _, c := range p.Conditions {
+		inspectNode(c.Body, func(n IsNode) bool {
+			a.addNode(n)
+			return true
+		})
+	}
+	return a
+}
+
+func (a policyAccess) addScope(scope any) {
+	switch s := scope.(type) {
+	case ScopeTypeEq:
+		a.entityTypes[s.Entity.Type] = true
+	case ScopeTypeIn:
+		a.entityTypes[s.Entity.Type] = true
+	case ScopeTypeInSet:
+		for _, e := range s.Entities {
+			a.entityTypes[e.Type] = true
+		}
+	case ScopeTypeIs:
+		a.entityTypes[s.Type] = true
+	case ScopeTypeIsIn:
+		a.entityTypes[s.Type] = true
+		a.entityTypes[s.Entity.Type] = true
+	}
+}
+
+func (a policyAccess) addNode(n IsNode) {
+	switch n := n.(type) {
+	case NodeTypeAccess:
+		a.attributes[accessPath(n.Arg, n.Value)] = true
+	case NodeTypeHas:
+		a.attributes[accessPath(n.Arg, n.Value)] = true
+	case NodeTypeGetTag:
+		a.attributes[tagPath(n.BinaryNode)] = true
+	case NodeTypeHasTag:
+		a.attributes[tagPath(n.BinaryNode)] = true
+	case NodeTypeIs:
+		a.entityTypes[n.EntityType] = true
+	case NodeTypeIsIn:
+		a.entityTypes[n.EntityType] = true
+	case NodeTypeExtensionCall:
+		a.extensions[string(n.Name)] = true
+	case NodeValue:
+		if uid, isUID := n.Value.(types.EntityUID); isUID {
+			a.entityTypes[uid.Type] = true
+		}
+	}
+}
+
+// unknownPath stands for a root or tag key that is not known statically.
+const unknownPath = "*"
+
+// accessPath returns the path of attr on object.
+func accessPath(object IsNode, attr types.String) string {
+	return rootPath(object) + "." + string(attr)
+}
+
+// tagPath returns the path of a tag lookup.
+func tagPath(n BinaryNode) string {
+	key := unknownPath
+	if v, ok := n.Right.(NodeValue); ok {
+		if s, ok := v.Value.(types.String); ok {
+			key = strconv.Quote(string(s))
+		}
+	}
+	return rootPath(n.Left) + ".getTag(" + key + ")"
+}
+
+// rootPath returns the path of n if it is a variable, an entity literal or
+// an attribute path, and unknownPath otherwise.
+func rootPath(n IsNode) string {
+	if p, ok := AttributePathOf(n); ok {
+		return p.String()
+	}
+	switch n := n.(type) {
+	case NodeValue:
+		if uid, ok := n.Value.(types.EntityUID); ok {
+			return uid.String()
+		}
+	case NodeTypeAccess:
+		return accessPath(n.Arg, n.Value)
+	}
+	return unknownPath
+}
+
+// setDiff returns, sorted, the keys only in after and the keys only in before.
+func setDiff[K ~string](before, after map[K]bool) (added, removed []K) {
+	for k := range after {
+		if !before[k] {
+			added = append(added, k)
+		}
+	}
+	for k := range before {
+		if !after[k] {
+			removed = append(removed, k)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
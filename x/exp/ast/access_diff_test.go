@@ -0,0 +1,69 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestAccessDiff(t *testing.T) {
+	t.Parallel()
+	parse := func(t *testing.T, src string) *ast.Policy {
+		t.Helper()
+		var p cedar.Policy
+		testutil.OK(t, p.UnmarshalCedar([]byte(src)))
+		return (*ast.Policy)(p.AST())
+	}
+
+	t.Run("added and removed", func(t *testing.T) {
+		t.Parallel()
+		before := parse(t, `permit(principal is User, action, resource)
+			when { principal.dept == "eng" && context.ip.isInRange(ip("10.0.0.0/8")) };`)
+		after := parse(t, `permit(principal is User, action, resource in Folder::"hr")
+			when { principal.dept == "eng" && principal has manager && principal.manager.ssn like "*" }
+			unless { resource.getTag("classification") == "secret" };`)
+		got := ast.AccessDiff(before, after)
+		testutil.Equals(t, got, ast.AccessDiffResult{
+			AddedAttributes:   []string{"principal.manager", "principal.manager.ssn", `resource.getTag("classification")`},
+			RemovedAttributes: []string{"context.ip"},
+			AddedEntityTypes:  []types.EntityType{"Folder"},
+			RemovedExtensions: []string{"ip", "isInRange"},
+		})
+		testutil.Equals(t, got.IsEmpty(), false)
+	})
+
+	t.Run("reordering is not a change", func(t *testing.T) {
+		t.Parallel()
+		before := parse(t, `permit(principal, action, resource)
+			when { principal.dept == "eng" } when { resource.owner == User::"alice" };`)
+		after := parse(t, `permit(principal, action, resource)
+			when { resource.owner == User::"alice" && principal.dept == "eng" && principal.dept != "" };`)
+		got := ast.AccessDiff(before, after)
+		testutil.Equals(t, got, ast.AccessDiffResult{})
+		testutil.Equals(t, got.IsEmpty(), true)
+	})
+
+	t.Run("entity literal roots", func(t *testing.T) {
+		t.Parallel()
+		got := ast.AccessDiff(nil, parse(t, `permit(principal, action, resource)
+			when { User::"alice".role == "admin" && principal.getTag(context.key) == "x" };`))
+		testutil.Equals(t, got.AddedAttributes, []string{`User::"alice".role`, "context.key", "principal.getTag(*)"})
+		testutil.Equals(t, got.AddedEntityTypes, []types.EntityType{"User"})
+	})
+
+	t.Run("unknown roots and tag keys", func(t *testing.T) {
+		t.Parallel()
+		before := parse(t, `permit(principal, action, resource)
+			when { resource.getTag("owner") == "alice" };`)
+		after := parse(t, `permit(principal, action, resource)
+			when { resource.getTag(context.tag) == "alice" && (if context.self then principal else resource).owner == "alice" };`)
+		got := ast.AccessDiff(before, after)
+		testutil.Equals(t, got, ast.AccessDiffResult{
+			AddedAttributes:   []string{"*.owner", "context.self", "context.tag", "resource.getTag(*)"},
+			RemovedAttributes: []string{`resource.getTag("owner")`},
+		})
+	})
+}
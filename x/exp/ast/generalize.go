@@ -0,0 +1,21 @@
+package ast
+
+import "slices"
+
+// GeneralizeScope returns a copy of p in which a principal or resource scope
+// of the form == Type::"id" is replaced by is Type, turning a grant for one
+// entity into one for every entity of its type. The action scope, in and
+// is ... in constraints and the conditions are kept as they are, since
+// membership in a group is meaningful. p is not modified.
+func GeneralizeScope(p *Policy) *Policy {
+	g := *p
+	g.Annotations = slices.Clone(p.Annotations)
+	g.Conditions = slices.Clone(p.Conditions)
+	if s, ok := p.Principal.(ScopeTypeEq); ok {
+		g.Principal = ScopeTypeIs{Type: s.Entity.Type}
+	}
+	if s, ok := p.Resource.(ScopeTypeEq); ok {
+		g.Resource = ScopeTypeIs{Type: s.Entity.Type}
+	}
+	return &g
+}
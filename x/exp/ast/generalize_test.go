@@ -0,0 +1,59 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestGeneralizeScope(t *testing.T) {
+	t.Parallel()
+	alice := types.NewEntityUID("User", "alice")
+	view := types.NewEntityUID("Action", "view")
+	doc := types.NewEntityUID("Document", "readme")
+	folder := types.NewEntityUID("Folder", "shared")
+	cond := ast.Context().Access("mfa")
+
+	tests := []struct {
+		name string
+		in   *ast.Policy
+		want *ast.Policy
+	}{
+		{
+			"eq becomes is",
+			ast.Permit().Annotate("id", "grant").PrincipalEq(alice).ActionEq(view).ResourceEq(doc).When(cond),
+			ast.Permit().Annotate("id", "grant").PrincipalIs("User").ActionEq(view).ResourceIs("Document").When(cond),
+		},
+		{
+			"in is kept",
+			ast.Forbid().PrincipalIn(alice).ResourceIn(folder),
+			ast.Forbid().PrincipalIn(alice).ResourceIn(folder),
+		},
+		{
+			"is in is kept",
+			ast.Permit().PrincipalEq(alice).ResourceIsIn("Document", folder),
+			ast.Permit().PrincipalIs("User").ResourceIsIn("Document", folder),
+		},
+		{
+			"unconstrained",
+			ast.Permit(),
+			ast.Permit(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testutil.Equals(t, ast.GeneralizeScope(tt.in), tt.want)
+		})
+	}
+
+	t.Run("does not mutate", func(t *testing.T) {
+		t.Parallel()
+		p := ast.Permit().Annotate("id", "grant").PrincipalEq(alice).When(cond)
+		g := ast.GeneralizeScope(p)
+		g.Annotate("note", "template").Unless(cond)
+		testutil.Equals(t, p, ast.Permit().Annotate("id", "grant").PrincipalEq(alice).When(cond))
+	})
+}
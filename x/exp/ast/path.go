@@ -0,0 +1,58 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// AttributePath is an attribute access chain rooted at a variable, such as
+// principal.manager.name, which has the Variable "principal" and the
+// Attributes ["manager", "name"].
+type AttributePath struct {
+	Variable   types.String
+	Attributes []types.String
+}
+
+// AttributePathOf returns the attribute path read by n if n is a variable or
+// a chain of attribute accesses rooted at one. A has test yields the path of
+// the attribute it tests, so `principal has manager` yields
+// principal.manager. It returns false for any other node.
+func AttributePathOf(n IsNode) (AttributePath, bool) {
+	if has, ok := n.(NodeTypeHas); ok {
+		return appendAttribute(has.Arg, has.Value)
+	}
+	return accessChain(n)
+}
+
+// accessChain returns the path of a variable or of a chain of attribute
+// accesses rooted at one.
+func accessChain(n IsNode) (AttributePath, bool) {
+	switch n := n.(type) {
+	case NodeTypeVariable:
+		return AttributePath{Variable: n.Name}, true
+	case NodeTypeAccess:
+		return appendAttribute(n.Arg, n.Value)
+	}
+	return AttributePath{}, false
+}
+
+func appendAttribute(object IsNode, attr types.String) (AttributePath, bool) {
+	p, ok := accessChain(object)
+	if !ok {
+		return AttributePath{}, false
+	}
+	p.Attributes = append(p.Attributes, attr)
+	return p, true
+}
+
+// String returns the path in Cedar syntax, e.g. "principal.manager.name".
+func (p AttributePath) String() string {
+	var sb strings.Builder
+	sb.WriteString(string(p.Variable))
+	for _, a := range p.Attributes {
+		sb.WriteByte('.')
+		sb.WriteString(string(a))
+	}
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestAttributePathOf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		node Node
+		want string
+		ok   bool
+	}{
+		{"variable", Principal(), "principal", true},
+		{"access", Principal().Access("manager").Access("name"), "principal.manager.name", true},
+		{"has", Context().Access("device").Has("trusted"), "context.device.trusted", true},
+		{"entityRoot", EntityUID("User", "alice").Access("name"), "", false},
+		{"otherRoot", IfThenElse(True(), Principal(), Principal()).Access("name"), "", false},
+		{"accessOnHas", Principal().Has("manager").Access("name"), "", false},
+		{"literal", Long(1), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := AttributePathOf(tt.node.AsIsNode())
+			testutil.Equals(t, ok, tt.ok)
+			if ok {
+				testutil.Equals(t, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributePathString(t *testing.T) {
+	t.Parallel()
+	p := AttributePath{Variable: "context", Attributes: []types.String{"device", "trusted"}}
+	testutil.Equals(t, p.String(), "context.device.trusted")
+	testutil.Equals(t, AttributePath{Variable: "resource"}.String(), "resource")
+}
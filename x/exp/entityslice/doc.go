@@ -43,6 +43,12 @@
 //	// Authorize with the smaller slice (same result, less data)
 //	decision, _ := cedar.Authorize(policies, slice, request)
 //
+// To shrink the slice further, WithAttributeProjection strips the attributes
+// and tags that the policies never read from each entity in it, keeping
+// parents and any attribute tested with `has`:
+//
+//	slice := manifest.SliceEntities(allEntities, request, entityslice.WithAttributeProjection())
+//
 // # Benefits
 //
 //   - Reduced memory usage: Only load entities that matter
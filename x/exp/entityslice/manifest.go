@@ -27,6 +27,16 @@ type EntityManifest struct {
 
 	// EntityLiterals contains entity UIDs that are explicitly referenced in policies.
 	EntityLiterals map[types.EntityUID]bool
+
+	// ReadAttributes holds the names of the attributes that policies access or
+	// test with `has`, on any entity or record. If nil, ProjectEntity keeps
+	// all attributes.
+	ReadAttributes map[types.String]bool
+
+	// ReadTags holds the tag keys that policies access or test with `hasTag`.
+	// If nil, ProjectEntity keeps all tags; this is also the case when a
+	// policy looks up a tag with a key that is not a string literal.
+	ReadTags map[types.String]bool
 }
 
 // ComputeManifest analyzes policies against a schema to determine what entity
@@ -40,6 +50,8 @@ func ComputeManifest(v *validator.Validator, policies *cedar.PolicySet) (*Entity
 		RequiredAttributes:    make(map[types.EntityType]map[types.Ident]bool),
 		RequiredAncestorTypes: make(map[types.EntityType]map[types.EntityType]bool),
 		EntityLiterals:        make(map[types.EntityUID]bool),
+		ReadAttributes:        make(map[types.String]bool),
+		ReadTags:              make(map[types.String]bool),
 	}
 
 	// Analyze each policy
@@ -102,9 +114,16 @@ func analyzeNode(manifest *EntityManifest, node ast.IsNode, depth int) {
 
 	switch n := node.(type) {
 	case ast.NodeTypeAccess:
+		recordAttribute(manifest, n.Value)
 		analyzeNode(manifest, n.Arg, depth+1)
+	case ast.NodeTypeHas:
+		recordAttribute(manifest, n.Value)
+		analyzeNode(manifest, n.Arg, depth)
 	case ast.NodeTypeGetTag:
 		analyzeGetTagNode(manifest, n, depth)
+	case ast.NodeTypeHasTag:
+		recordTag(manifest, n.Right)
+		analyzeNodeChildren(manifest, node, depth)
 	case ast.NodeValue:
 		analyzeValueNode(manifest, n)
 	case ast.NodeTypeVariable:
@@ -116,10 +135,33 @@ func analyzeNode(manifest *EntityManifest, node ast.IsNode, depth int) {
 
 // analyzeGetTagNode handles tag node analysis with depth tracking.
 func analyzeGetTagNode(manifest *EntityManifest, n ast.NodeTypeGetTag, depth int) {
+	recordTag(manifest, n.Right)
 	analyzeNode(manifest, n.Left, depth+1)
 	analyzeNode(manifest, n.Right, depth)
 }
 
+// recordAttribute notes that policies read the named attribute.
+func recordAttribute(manifest *EntityManifest, name types.String) {
+	if manifest.ReadAttributes != nil {
+		manifest.ReadAttributes[name] = true
+	}
+}
+
+// recordTag notes that policies read the tag with the given key. A key that
+// is not a string literal could name any tag, so all tags are then kept.
+func recordTag(manifest *EntityManifest, key ast.IsNode) {
+	if manifest.ReadTags == nil {
+		return
+	}
+	if v, ok := key.(ast.NodeValue); ok {
+		if name, ok := v.Value.(types.String); ok {
+			manifest.ReadTags[name] = true
+			return
+		}
+	}
+	manifest.ReadTags = nil
+}
+
 // analyzeValueNode handles value node analysis for entity literals.
 func analyzeValueNode(manifest *EntityManifest, n ast.NodeValue) {
 	if uid, ok := n.Value.(types.EntityUID); ok {
@@ -217,15 +259,56 @@ func getBinaryChildren(n ast.IsNode) []ast.IsNode {
 	return nil
 }
 
+// ProjectEntity returns a copy of entity without the attributes and tags that
+// the policies never read. Its UID and parents are kept. An attribute that is
+// only tested with `has` is kept, so authorization on projected entities
+// gives the same decision as on the originals.
+func (m *EntityManifest) ProjectEntity(entity types.Entity) types.Entity {
+	if m.ReadAttributes != nil {
+		entity.Attributes = projectRecord(entity.Attributes, m.ReadAttributes)
+	}
+	if m.ReadTags != nil {
+		entity.Tags = projectRecord(entity.Tags, m.ReadTags)
+	}
+	return entity
+}
+
+// projectRecord returns the fields of r whose names are in keep.
+func projectRecord(r types.Record, keep map[types.String]bool) types.Record {
+	fields := types.RecordMap{}
+	for k, v := range r.All() {
+		if keep[k] {
+			fields[k] = v
+		}
+	}
+	return types.NewRecord(fields)
+}
+
+// SliceOption configures SliceEntities.
+type SliceOption func(*sliceContext)
+
+// WithAttributeProjection makes SliceEntities apply [EntityManifest.ProjectEntity]
+// to every entity in the slice, which also stops it following entity
+// references in attributes that the policies never read.
+func WithAttributeProjection() SliceOption {
+	return func(ctx *sliceContext) {
+		ctx.project = true
+	}
+}
+
 // SliceEntities returns a subset of entities relevant to the given request,
 // based on the manifest's analysis of policy requirements.
-func (m *EntityManifest) SliceEntities(entities types.EntityMap, req cedar.Request) types.EntityMap {
+func (m *EntityManifest) SliceEntities(entities types.EntityMap, req cedar.Request, opts ...SliceOption) types.EntityMap {
 	ctx := &sliceContext{
+		manifest: m,
 		entities: entities,
 		slice:    make(types.EntityMap),
 		visited:  make(map[types.EntityUID]bool),
 		maxLevel: m.MaxLevel,
 	}
+	for _, opt := range opts {
+		opt(ctx)
+	}
 
 	workSet := m.buildInitialWorkSet(req)
 	ctx.collectEntitiesBFS(workSet)
@@ -235,6 +318,8 @@ func (m *EntityManifest) SliceEntities(entities types.EntityMap, req cedar.Reque
 
 // sliceContext holds state during entity slicing.
 type sliceContext struct {
+	manifest *EntityManifest
+	project  bool
 	entities types.EntityMap
 	slice    types.EntityMap
 	visited  map[types.EntityUID]bool
@@ -278,6 +363,9 @@ func (ctx *sliceContext) processEntity(uid types.EntityUID, level int, nextWork
 	if !exists {
 		return
 	}
+	if ctx.project {
+		entity = ctx.manifest.ProjectEntity(entity)
+	}
 
 	ctx.slice[uid] = entity
 	ctx.collectParents(entity, nextWork)
@@ -1,6 +1,7 @@
 package entityslice
 
 import (
+	"maps"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go"
@@ -791,3 +792,101 @@ func TestGetBinaryChildrenFallback(t *testing.T) {
 		t.Error("Expected nil for non-binary node")
 	}
 }
+
+func TestComputeManifestReadAttributesAndTags(t *testing.T) {
+	manifest := parseAndComputeManifest(t, `permit(principal, action, resource) when {
+		principal.dept == "eng" && resource has owner &&
+		principal.getTag("role") == "admin" && principal.hasTag("level")
+	};`)
+	want := map[types.String]bool{"dept": true, "owner": true}
+	if !maps.Equal(manifest.ReadAttributes, want) {
+		t.Errorf("Expected ReadAttributes %v, got %v", want, manifest.ReadAttributes)
+	}
+	want = map[types.String]bool{"role": true, "level": true}
+	if !maps.Equal(manifest.ReadTags, want) {
+		t.Errorf("Expected ReadTags %v, got %v", want, manifest.ReadTags)
+	}
+
+	manifest = parseAndComputeManifest(t, `permit(principal, action, resource) when {
+		principal.hasTag("level") && principal.getTag(context.tag) == "admin" && principal.hasTag("role")
+	};`)
+	if manifest.ReadTags != nil {
+		t.Errorf("Expected all tags to be read with a non-literal key, got %v", manifest.ReadTags)
+	}
+}
+
+func TestProjectEntity(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	admins := types.NewEntityUID("Group", "admins")
+	entity := types.Entity{
+		UID:     alice,
+		Parents: types.NewEntityUIDSet(admins),
+		Attributes: types.NewRecord(types.RecordMap{
+			"dept":   types.String("eng"),
+			"secret": types.String("hunter2"),
+		}),
+		Tags: types.NewRecord(types.RecordMap{
+			"role":  types.String("admin"),
+			"other": types.String("x"),
+		}),
+	}
+
+	manifest := parseAndComputeManifest(t, `permit(principal, action, resource) when {
+		principal has dept && principal.hasTag("role")
+	};`)
+	got := manifest.ProjectEntity(entity)
+	want := types.Entity{
+		UID:        alice,
+		Parents:    types.NewEntityUIDSet(admins),
+		Attributes: types.NewRecord(types.RecordMap{"dept": types.String("eng")}),
+		Tags:       types.NewRecord(types.RecordMap{"role": types.String("admin")}),
+	}
+	if !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got := (&EntityManifest{}).ProjectEntity(entity); !got.Equal(entity) {
+		t.Errorf("Expected a manifest without read sets to keep everything, got %v", got)
+	}
+}
+
+func TestSliceEntitiesWithAttributeProjection(t *testing.T) {
+	policies, err := cedar.NewPolicySetFromBytes("test.cedar", []byte(`
+		permit(principal, action, resource) when { resource has owner && resource.owner == principal };
+		forbid(principal, action, resource) when { principal has suspended };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	manifest, err := ComputeManifest(nil, policies)
+	if err != nil {
+		t.Fatalf("ComputeManifest failed: %v", err)
+	}
+
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	mallory := types.NewEntityUID("User", "mallory")
+	doc := types.NewEntityUID("Doc", "readme")
+	entities := types.EntityMap{
+		alice:   {UID: alice, Attributes: types.NewRecord(types.RecordMap{"friend": mallory})},
+		bob:     {UID: bob, Attributes: types.NewRecord(types.RecordMap{"suspended": types.False})},
+		mallory: {UID: mallory},
+		doc:     {UID: doc, Attributes: types.NewRecord(types.RecordMap{"owner": alice, "title": types.String("README")})},
+	}
+
+	for _, principal := range []types.EntityUID{alice, bob} {
+		req := cedar.Request{Principal: principal, Action: types.NewEntityUID("Action", "view"), Resource: doc}
+		slice := manifest.SliceEntities(entities, req, WithAttributeProjection())
+		fullDecision, _ := cedar.Authorize(policies, entities, req)
+		sliceDecision, _ := cedar.Authorize(policies, slice, req)
+		if fullDecision != sliceDecision {
+			t.Errorf("%v: decisions differ - full=%v, slice=%v", principal, fullDecision, sliceDecision)
+		}
+		if _, ok := slice[doc].Attributes.Get("title"); ok {
+			t.Errorf("%v: expected title to be projected away", principal)
+		}
+		if _, ok := slice[mallory]; ok {
+			t.Errorf("%v: expected mallory, only referenced by an unread attribute, to be left out", principal)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/internal/parser"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// GrantClause is a rule under which principals are granted access, as
+// reported by AccessSummary.
+type GrantClause struct {
+	// Principal is the principal scope of the granting policies, or nil if
+	// they apply to every principal.
+	Principal *QueryConstraint
+
+	// Conditions are the when and unless clauses that still depend on the
+	// principal once the action, resource and context are known. It is empty
+	// if the scope alone grants access.
+	Conditions []ast.ConditionType
+
+	// PolicyIDs lists, sorted, the permit policies that reduce to this
+	// clause.
+	PolicyIDs []types.PolicyID
+}
+
+// String renders c in Cedar syntax, such as
+// `principal in Group::"editors" when { principal.level > 3 }`.
+func (c GrantClause) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("principal")
+	if c.Principal != nil {
+		writeConstraint(&buf, *c.Principal)
+	}
+	for _, cond := range c.Conditions {
+		buf.WriteRune(' ')
+		parser.MarshalCondition(cond, &buf)
+	}
+	return buf.String()
+}
+
+func writeConstraint(buf *bytes.Buffer, c QueryConstraint) {
+	switch c.Kind {
+	case ConstraintEq:
+		buf.WriteString(" == ")
+		buf.Write(c.Entity.MarshalCedar())
+	case ConstraintIn:
+		buf.WriteString(" in ")
+		buf.Write(c.Entity.MarshalCedar())
+	case ConstraintIs:
+		buf.WriteString(" is " + string(c.EntityType))
+	case ConstraintIsIn:
+		buf.WriteString(" is " + string(c.EntityType) + " in ")
+		buf.Write(c.Entity.MarshalCedar())
+	}
+}
+
+// AccessSummary describes who can perform action on resource, as the rules
+// that grant it rather than the principals that satisfy them, for views such
+// as a resource's sharing settings. It is like QueryPrincipals, evaluating
+// the policies partially with the principal unknown, but reports each
+// permit that may apply as a clause made of its principal scope and the
+// residual of its conditions. Permits that reduce to the same clause are
+// grouped, and clauses are sorted by their Cedar rendering.
+//
+// Forbid policies that depend on the principal are not reflected, so a clause
+// grants access unless one of them applies. If a forbid applies to every
+// principal, or no permit may apply, AccessSummary returns nil. Permits that
+// fail to evaluate grant nothing and are left out.
+//
+// Example:
+//
+//	for _, clause := range eval.AccessSummary(policies, entities,
+//	    types.NewEntityUID("Action", "edit"),
+//	    types.NewEntityUID("Document", "report.pdf"),
+//	    types.Record{}) {
+//	    fmt.Println(clause) // principal in Group::"editors"
+//	}
+func AccessSummary(
+	policies map[types.PolicyID]*ast.Policy,
+	entities types.EntityMap,
+	action types.EntityUID,
+	resource types.EntityUID,
+	context types.Record,
+) []GrantClause {
+	env := Env{
+		Principal: Variable("principal"),
+		Action:    action,
+		Resource:  resource,
+		Context:   context,
+		Entities:  entities,
+	}
+	residuals := PartialPolicySet(env, policies)
+	if hasDefiniteForbid(residuals) {
+		return nil
+	}
+
+	var clauses []GrantClause
+	index := map[string]int{}
+	for _, p := range residuals.Permits {
+		if p.Kind != ResidualTrue && p.Kind != ResidualVariable {
+			continue
+		}
+		clause := grantClause(p.Policy)
+		key := clause.String()
+		if i, ok := index[key]; ok {
+			clauses[i].PolicyIDs = append(clauses[i].PolicyIDs, p.PolicyID)
+			continue
+		}
+		index[key] = len(clauses)
+		clause.PolicyIDs = []types.PolicyID{p.PolicyID}
+		clauses = append(clauses, clause)
+	}
+	for _, c := range clauses {
+		slices.Sort(c.PolicyIDs)
+	}
+	slices.SortFunc(clauses, func(a, b GrantClause) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return clauses
+}
+
+// grantClause returns the clause of a residual permit, leaving out the
+// conditions that evaluated to true.
+func grantClause(p *ast.Policy) GrantClause {
+	var clause GrantClause
+	if constraints := extractScopeConstraints(p.Principal); len(constraints) > 0 {
+		clause.Principal = &constraints[0]
+	}
+	for _, cond := range p.Conditions {
+		if classifyCondition(cond) != ResidualTrue {
+			clause.Conditions = append(clause.Conditions, cond)
+		}
+	}
+	return clause
+}
@@ -0,0 +1,107 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestAccessSummary(t *testing.T) {
+	t.Parallel()
+	edit := types.NewEntityUID("Action", "edit")
+	view := types.NewEntityUID("Action", "view")
+	doc := types.NewEntityUID("Document", "report")
+	editors := types.NewEntityUID("Group", "editors")
+	alice := types.NewEntityUID("User", "alice")
+	entities := types.EntityMap{
+		doc: {UID: doc, Attributes: types.NewRecord(types.RecordMap{"owner": alice})},
+	}
+
+	policies := map[types.PolicyID]*ast.Policy{
+		"editors":      ast.Permit().PrincipalIn(editors).ActionEq(edit),
+		"editorsAgain": ast.Permit().PrincipalIn(editors).ActionInSet(edit, view),
+		"owner": ast.Permit().ActionEq(edit).
+			When(ast.Resource().Access("owner").Equal(ast.Principal())),
+		"seniors": ast.Permit().PrincipalIs("User").ActionEq(edit).
+			When(ast.Principal().Access("level").GreaterThan(ast.Long(3))).
+			When(ast.Context().Access("mfa")),
+		"viewers":    ast.Permit().ActionEq(view),
+		"otherDoc":   ast.Permit().ActionEq(edit).ResourceEq(types.NewEntityUID("Document", "other")),
+		"noBanned":   ast.Forbid().When(ast.Principal().Access("banned")),
+		"badContext": ast.Permit().ActionEq(edit).When(ast.Context().Access("missing")),
+	}
+
+	t.Run("clauses", func(t *testing.T) {
+		t.Parallel()
+		ctx := types.NewRecord(types.RecordMap{"mfa": types.True})
+		got := AccessSummary(policies, entities, edit, doc, ctx)
+		var rendered []string
+		for _, c := range got {
+			rendered = append(rendered, c.String())
+		}
+		testutil.Equals(t, rendered, []string{
+			`principal in Group::"editors"`,
+			`principal is User when { principal.level > 3 }`,
+			`principal when { User::"alice" == principal }`,
+		})
+		testutil.Equals(t, got[0].PolicyIDs, []types.PolicyID{"editors", "editorsAgain"})
+		testutil.Equals(t, got[0].Principal, &QueryConstraint{Kind: ConstraintIn, Entity: editors})
+		testutil.Equals(t, got[1].PolicyIDs, []types.PolicyID{"seniors"})
+		testutil.Equals(t, len(got[1].Conditions), 1)
+		testutil.Equals(t, got[2].Principal, (*QueryConstraint)(nil))
+	})
+
+	t.Run("falseConditionsDropClause", func(t *testing.T) {
+		t.Parallel()
+		ctx := types.NewRecord(types.RecordMap{"mfa": types.False})
+		got := AccessSummary(policies, entities, edit, doc, ctx)
+		testutil.Equals(t, len(got), 2)
+	})
+
+	t.Run("everyone", func(t *testing.T) {
+		t.Parallel()
+		got := AccessSummary(policies, entities, view, doc, types.Record{})
+		testutil.Equals(t, len(got), 2)
+		testutil.Equals(t, got[0].String(), "principal")
+		testutil.Equals(t, got[0].PolicyIDs, []types.PolicyID{"viewers"})
+	})
+
+	t.Run("definiteForbid", func(t *testing.T) {
+		t.Parallel()
+		withForbid := map[types.PolicyID]*ast.Policy{
+			"editors":  ast.Permit().PrincipalIn(editors),
+			"lockdown": ast.Forbid().ResourceEq(doc),
+		}
+		testutil.Equals(t, AccessSummary(withForbid, entities, edit, doc, types.Record{}), []GrantClause(nil))
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			clause GrantClause
+			want   string
+		}{
+			{GrantClause{Principal: &QueryConstraint{Kind: ConstraintEq, Entity: alice}}, `principal == User::"alice"`},
+			{GrantClause{Principal: &QueryConstraint{Kind: ConstraintIsIn, EntityType: "User", Entity: editors}}, `principal is User in Group::"editors"`},
+			{GrantClause{Conditions: []ast.ConditionType{{Condition: ast.ConditionUnless, Body: ast.Principal().Access("banned").AsIsNode()}}}, `principal unless { principal.banned }`},
+		} {
+			testutil.Equals(t, tt.clause.String(), tt.want)
+		}
+	})
+}
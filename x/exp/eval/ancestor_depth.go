@@ -0,0 +1,61 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import "github.com/cedar-policy/cedar-go/internal/eval"
+
+// ErrAncestorDepthExceeded is the error of an `in` check that WithMaxAncestorDepth
+// kept from finding an ancestor, when WithAncestorDepthErrors is in effect.
+var ErrAncestorDepthExceeded = eval.ErrAncestorDepthExceeded
+
+// WithMaxAncestorDepth makes `in` checks follow at most n parent hops from the
+// entity being checked, which bounds their cost on deep or maliciously
+// constructed entity hierarchies. Ancestors further away are treated as
+// unreachable, so `in` is false for them; with WithAncestorDepthErrors it
+// fails instead. A value of n below 1 means no limit, which is the default.
+//
+// This is a safety limit, not cycle detection, which `in` always performs.
+// A limit lower than the depth of a legitimate hierarchy misses real
+// memberships and therefore changes decisions, so only set it deliberately.
+// It counts hops, unlike the MaxEntityGraphDepth of Env.Limits, which counts
+// the entities examined and fails when exceeded. Both apply when set, and the
+// first one reached ends the walk.
+func WithMaxAncestorDepth(n int) Option {
+	return func(c *evalConfig) {
+		limits(&c.env).MaxAncestorDepth = n
+	}
+}
+
+// WithAncestorDepthErrors makes an `in` check whose ancestor may lie beyond
+// the limit set by WithMaxAncestorDepth fail with ErrAncestorDepthExceeded
+// instead of evaluating to false, so that a truncated walk is not mistaken
+// for a non-membership.
+func WithAncestorDepthErrors() Option {
+	return func(c *evalConfig) {
+		limits(&c.env).ErrorOnAncestorDepth = true
+	}
+}
+
+// limits returns a copy of env's limits that env now points to, so that
+// options can change it without affecting other environments that share the
+// original.
+func limits(env *Env) *eval.Limits {
+	l := &eval.Limits{}
+	if env.Limits != nil {
+		*l = *env.Limits
+	}
+	env.Limits = l
+	return l
+}
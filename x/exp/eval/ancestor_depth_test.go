@@ -0,0 +1,108 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestWithMaxAncestorDepth(t *testing.T) {
+	t.Parallel()
+	// alice -> g1 -> g2 -> g3, so g3 is three hops away from alice.
+	alice := types.NewEntityUID("User", "alice")
+	g1 := types.NewEntityUID("Group", "g1")
+	g2 := types.NewEntityUID("Group", "g2")
+	g3 := types.NewEntityUID("Group", "g3")
+	entities := types.EntityMap{
+		alice: {UID: alice, Parents: types.NewEntityUIDSet(g1)},
+		g1:    {UID: g1, Parents: types.NewEntityUIDSet(g2)},
+		g2:    {UID: g2, Parents: types.NewEntityUIDSet(g3)},
+		g3:    {UID: g3},
+	}
+	inG3 := ast.Principal().In(ast.Value(g3)).AsIsNode()
+	inSet := ast.Principal().In(ast.Set(ast.Value(g3))).AsIsNode()
+	inOther := ast.Principal().In(ast.EntityUID("Group", "other")).AsIsNode()
+
+	tests := []struct {
+		name     string
+		node     ast.IsNode
+		entities types.EntityGetter
+		opts     []Option
+		want     types.Value
+		wantErr  error
+	}{
+		{"unlimited", inG3, entities, nil, types.True, nil},
+		{"withinLimit", inG3, entities, []Option{WithMaxAncestorDepth(3)}, types.True, nil},
+		{"beyondLimit", inG3, entities, []Option{WithMaxAncestorDepth(2)}, types.False, nil},
+		{"beyondLimitSet", inSet, entities, []Option{WithMaxAncestorDepth(2)}, types.False, nil},
+		{"zeroIsUnlimited", inG3, entities, []Option{WithMaxAncestorDepth(0)}, types.True, nil},
+		{"beyondLimitErrors", inG3, entities, []Option{WithMaxAncestorDepth(2), WithAncestorDepthErrors()}, nil, ErrAncestorDepthExceeded},
+		{"truncatedNonMemberErrors", inOther, entities, []Option{WithMaxAncestorDepth(2), WithAncestorDepthErrors()}, nil, ErrAncestorDepthExceeded},
+		{"completeWalkDoesNotError", inOther, entities, []Option{WithMaxAncestorDepth(3), WithAncestorDepthErrors()}, types.False, nil},
+		{"cachedWithinLimit", inG3, types.NewCachedEntityGetter(entities), []Option{WithMaxAncestorDepth(3)}, types.True, nil},
+		{"cachedBeyondLimit", inG3, types.NewCachedEntityGetter(entities), []Option{WithMaxAncestorDepth(2)}, types.False, nil},
+		{"cachedBeyondLimitSet", inSet, types.NewCachedEntityGetter(entities), []Option{WithMaxAncestorDepth(2)}, types.False, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Eval(tt.node, Env{Entities: tt.entities, Principal: alice}, tt.opts...)
+			testutil.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				testutil.Equals(t, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("sharedLimitsUnchanged", func(t *testing.T) {
+		t.Parallel()
+		shared := &eval.Limits{MaxEntityGraphDepth: 100}
+		got, err := Eval(inG3, Env{Entities: entities, Principal: alice, Limits: shared}, WithMaxAncestorDepth(1))
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.False))
+		testutil.Equals(t, *shared, eval.Limits{MaxEntityGraphDepth: 100})
+	})
+
+	t.Run("graphDepthLimitWins", func(t *testing.T) {
+		t.Parallel()
+		limits := &eval.Limits{MaxEntityGraphDepth: 2}
+		_, err := Eval(inG3, Env{Entities: entities, Principal: alice, Limits: limits}, WithMaxAncestorDepth(2), WithAncestorDepthErrors())
+		testutil.ErrorIs(t, err, eval.ErrEntityDepthExceeded)
+		limits = &eval.Limits{MaxEntityGraphDepth: 3}
+		_, err = Eval(inG3, Env{Entities: entities, Principal: alice, Limits: limits}, WithMaxAncestorDepth(2), WithAncestorDepthErrors())
+		testutil.ErrorIs(t, err, ErrAncestorDepthExceeded)
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		t.Parallel()
+		read := types.NewEntityUID("Action", "read")
+		doc := types.NewEntityUID("Doc", "d")
+		policies := map[types.PolicyID]*ast.Policy{"p": ast.Permit().When(ast.Principal().In(ast.Value(g3)))}
+
+		got := QueryDecision(policies, entities, alice, read, doc, types.Record{})
+		testutil.Equals(t, got.Decision, types.Allow)
+		got = QueryDecision(policies, entities, alice, read, doc, types.Record{}, WithEvalOptions(WithMaxAncestorDepth(2)))
+		testutil.Equals(t, got.Decision, types.Deny)
+
+		env := Env{Entities: entities, Principal: alice, Action: read, Resource: doc, Context: types.Record{}}
+		residuals := PartialPolicySet(env, policies, WithMaxAncestorDepth(2))
+		testutil.Equals(t, residuals.Permits[0].Kind, ResidualFalse)
+	})
+}
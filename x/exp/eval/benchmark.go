@@ -0,0 +1,85 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// BenchmarkResult holds the cost of authorizing a workload, normalized to a
+// single authorization so that results for workloads of different sizes can be
+// compared.
+type BenchmarkResult struct {
+	// Ops is the number of authorizations performed: iterations times the
+	// number of requests.
+	Ops int `json:"ops"`
+
+	// Duration is the total wall-clock time spent authorizing.
+	Duration time.Duration `json:"duration"`
+
+	// NsPerOp, AllocsPerOp and BytesPerOp are the time, heap allocations and
+	// allocated bytes per authorization.
+	NsPerOp     int64 `json:"nsPerOp"`
+	AllocsPerOp int64 `json:"allocsPerOp"`
+	BytesPerOp  int64 `json:"bytesPerOp"`
+}
+
+// String formats the result like the output of go test -bench.
+func (r BenchmarkResult) String() string {
+	return fmt.Sprintf("%d\t%d ns/op\t%d B/op\t%d allocs/op", r.Ops, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+}
+
+// Benchmark authorizes every request against policies iterations times and
+// reports the average cost of one authorization. It gives CI performance
+// tracking a standard set of numbers without a testing.B; within a Go
+// benchmark, report the same figures with b.ReportMetric.
+//
+// A first, unmeasured pass warms up caches and the garbage collector runs
+// before measuring. Allocation counts cover the whole process, so other
+// goroutines should be idle while Benchmark runs. To profile the workload,
+// wrap the call with runtime/pprof.
+func Benchmark(policies cedar.PolicyIterator, entities types.EntityGetter, requests []types.Request, iterations int) BenchmarkResult {
+	authorizeAll(policies, entities, requests)
+	runtime.GC()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range iterations {
+		authorizeAll(policies, entities, requests)
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	r := BenchmarkResult{Ops: iterations * len(requests), Duration: elapsed}
+	if r.Ops > 0 {
+		ops := int64(r.Ops)
+		r.NsPerOp = elapsed.Nanoseconds() / ops
+		r.AllocsPerOp = int64(after.Mallocs-before.Mallocs) / ops
+		r.BytesPerOp = int64(after.TotalAlloc-before.TotalAlloc) / ops
+	}
+	return r
+}
+
+func authorizeAll(policies cedar.PolicyIterator, entities types.EntityGetter, requests []types.Request) {
+	for _, req := range requests {
+		cedar.Authorize(policies, entities, req)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestBenchmark(t *testing.T) {
+	t.Parallel()
+	policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+		permit (principal, action, resource) when { principal.level > 2 };
+	`))
+	testutil.OK(t, err)
+	alice := types.NewEntityUID("User", "alice")
+	entities := types.EntityMap{
+		alice: {UID: alice, Attributes: types.NewRecord(types.RecordMap{"level": types.Long(3)})},
+	}
+	requests := []types.Request{
+		{Principal: alice, Action: types.NewEntityUID("Action", "view"), Resource: types.NewEntityUID("Doc", "a")},
+		{Principal: alice, Action: types.NewEntityUID("Action", "edit"), Resource: types.NewEntityUID("Doc", "b")},
+	}
+
+	r := Benchmark(policies, entities, requests, 5)
+	testutil.Equals(t, r.Ops, 10)
+	testutil.Equals(t, r.Duration > 0, true)
+	testutil.Equals(t, r.NsPerOp, r.Duration.Nanoseconds()/10)
+	testutil.Equals(t, strings.HasSuffix(r.String(), " allocs/op"), true)
+
+	empty := Benchmark(policies, entities, nil, 5)
+	testutil.Equals(t, empty, BenchmarkResult{Duration: empty.Duration})
+}
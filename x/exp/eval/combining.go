@@ -0,0 +1,116 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// CombiningAlgorithm determines how QueryDecision combines the permit and
+// forbid policies that match a request.
+type CombiningAlgorithm int
+
+const (
+	// DenyOverrides is the Cedar combining algorithm and the default: any
+	// matching forbid denies the request, otherwise any matching permit allows
+	// it.
+	DenyOverrides CombiningAlgorithm = iota
+
+	// PermitOverrides is an experimental, non-standard algorithm: any matching
+	// permit allows the request, even if a forbid also matches. It exists only
+	// for what-if analysis, such as measuring the impact of a policy set's
+	// forbids, and does not reflect how any Cedar authorizer decides.
+	PermitOverrides
+)
+
+// DecisionOption configures QueryDecision and the QueryPrincipals,
+// QueryResources, and QueryActions functions.
+type DecisionOption func(*decisionConfig)
+
+type decisionConfig struct {
+	algorithm CombiningAlgorithm
+	schema    *schema.Schema
+	evalOpts  []Option
+}
+
+func newDecisionConfig(opts []DecisionOption) decisionConfig {
+	var cfg decisionConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithCombiningAlgorithm makes QueryDecision combine matching policies using
+// alg. It has no effect on the other query functions. Only DenyOverrides, the
+// default, matches the Cedar specification. Under PermitOverrides the
+// determining policies are the matching permits when the request is allowed
+// and the matching forbids when it is denied.
+func WithCombiningAlgorithm(alg CombiningAlgorithm) DecisionOption {
+	return func(c *decisionConfig) {
+		c.algorithm = alg
+	}
+}
+
+// WithEvalOptions makes the query functions evaluate policies with opts, as
+// Eval does, so that options such as WithNowContext, WithEntityPatches and
+// WithMaxAncestorDepth also apply to queries.
+func WithEvalOptions(opts ...Option) DecisionOption {
+	return func(c *decisionConfig) {
+		c.evalOpts = append(c.evalOpts, opts...)
+	}
+}
+
+// evalEnv returns env with the options given to WithEvalOptions applied.
+func (c decisionConfig) evalEnv(env Env) Env {
+	return newEvalConfig(env, c.evalOpts).env
+}
+
+// permitOverrides decides a request from its residuals under the
+// PermitOverrides algorithm.
+func permitOverrides(residuals *ResidualSet) *QueryDecisionResult {
+	result := &QueryDecisionResult{
+		Decision: types.Deny,
+	}
+	var permits, forbids []types.PolicyID
+	for _, p := range residuals.Permits {
+		switch p.Kind {
+		case ResidualTrue:
+			permits = append(permits, p.PolicyID)
+		case ResidualError:
+			result.ErroringPolicies = append(result.ErroringPolicies, p.PolicyID)
+		}
+	}
+	for _, f := range residuals.Forbids {
+		switch f.Kind {
+		case ResidualTrue:
+			forbids = append(forbids, f.PolicyID)
+		case ResidualError:
+			result.ErroringPolicies = append(result.ErroringPolicies, f.PolicyID)
+		}
+	}
+	if len(permits) > 0 {
+		result.Decision = types.Allow
+		result.DeterminingPolicies = permits
+	} else {
+		result.DeterminingPolicies = forbids
+	}
+	slices.Sort(result.DeterminingPolicies)
+	slices.Sort(result.ErroringPolicies)
+	return result
+}
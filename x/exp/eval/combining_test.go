@@ -0,0 +1,105 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestQueryDecisionCombiningAlgorithm(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	read := types.NewEntityUID("Action", "read")
+	doc := types.NewEntityUID("Document", "doc1")
+	erroring := ast.Long(1).GreaterThan(ast.String("not a number"))
+
+	tests := []struct {
+		name            string
+		policies        map[types.PolicyID]*ast.Policy
+		alg             CombiningAlgorithm
+		wantDecision    types.Decision
+		wantDetermining []types.PolicyID
+		wantErroring    []types.PolicyID
+	}{
+		{
+			name: "deny overrides",
+			policies: map[types.PolicyID]*ast.Policy{
+				"permit1": ast.Permit(),
+				"forbid1": ast.Forbid(),
+			},
+			alg:             DenyOverrides,
+			wantDecision:    types.Deny,
+			wantDetermining: []types.PolicyID{"forbid1"},
+		},
+		{
+			name: "permit overrides forbid",
+			policies: map[types.PolicyID]*ast.Policy{
+				"permit1": ast.Permit(),
+				"permit2": ast.Permit().PrincipalEq(alice),
+				"forbid1": ast.Forbid(),
+			},
+			alg:             PermitOverrides,
+			wantDecision:    types.Allow,
+			wantDetermining: []types.PolicyID{"permit1", "permit2"},
+		},
+		{
+			name: "permit overrides falls back to forbids",
+			policies: map[types.PolicyID]*ast.Policy{
+				"permit1": ast.Permit().When(ast.False()),
+				"forbid1": ast.Forbid(),
+				"forbid2": ast.Forbid().ResourceEq(doc),
+			},
+			alg:             PermitOverrides,
+			wantDecision:    types.Deny,
+			wantDetermining: []types.PolicyID{"forbid1", "forbid2"},
+		},
+		{
+			name:         "permit overrides with no matches",
+			policies:     map[types.PolicyID]*ast.Policy{},
+			alg:          PermitOverrides,
+			wantDecision: types.Deny,
+		},
+		{
+			name: "permit overrides reports errors",
+			policies: map[types.PolicyID]*ast.Policy{
+				"permit1":      ast.Permit(),
+				"error_permit": ast.Permit().When(erroring),
+				"error_forbid": ast.Forbid().When(erroring),
+			},
+			alg:             PermitOverrides,
+			wantDecision:    types.Allow,
+			wantDetermining: []types.PolicyID{"permit1"},
+			wantErroring:    []types.PolicyID{"error_forbid", "error_permit"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := QueryDecision(tc.policies, nil, alice, read, doc, types.Record{}, WithCombiningAlgorithm(tc.alg))
+			if result.Decision != tc.wantDecision {
+				t.Errorf("Decision = %v, want %v", result.Decision, tc.wantDecision)
+			}
+			if !slices.Equal(result.DeterminingPolicies, tc.wantDetermining) {
+				t.Errorf("DeterminingPolicies = %v, want %v", result.DeterminingPolicies, tc.wantDetermining)
+			}
+			if !slices.Equal(result.ErroringPolicies, tc.wantErroring) {
+				t.Errorf("ErroringPolicies = %v, want %v", result.ErroringPolicies, tc.wantErroring)
+			}
+		})
+	}
+}
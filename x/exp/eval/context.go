@@ -0,0 +1,152 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// ContextRef identifies a context attribute read by a policy. Path holds the
+// attribute names following `context`, so `context.device.trusted` has the
+// path ["device", "trusted"]. An empty path means the policy uses the context
+// record as a whole.
+type ContextRef struct {
+	Path []types.String
+}
+
+// String returns the reference in Cedar syntax, e.g. "context.device.trusted".
+func (r ContextRef) String() string {
+	return ast.AttributePath{Variable: "context", Attributes: r.Path}.String()
+}
+
+// RequiredContext reports, for each action declared in the schema, the context
+// attributes read by the policies that can apply to that action. A policy
+// applies to an action if its action scope is unconstrained, equals the
+// action, or names the action or one of its action-group ancestors via `in`.
+//
+// Only the deepest attribute access is reported, so a policy reading
+// `context.device.trusted` yields that path rather than `context.device`.
+// Attributes tested with `has` are included since their presence affects the
+// decision. The references for each action are deduplicated and sorted.
+// Actions whose applicable policies read no context are mapped to an empty
+// slice.
+func RequiredContext(policies map[types.PolicyID]*ast.Policy, s *schema.Schema) map[types.EntityUID][]ContextRef {
+	actionEntities := s.ActionEntities()
+	result := make(map[types.EntityUID][]ContextRef)
+	seen := make(map[types.EntityUID]map[string]bool)
+	for action := range s.Actions() {
+		result[action] = []ContextRef{}
+		seen[action] = make(map[string]bool)
+	}
+
+	for _, p := range policies {
+		var refs []ContextRef
+		for _, cond := range p.Conditions {
+			collectContextRefs(&refs, cond.Body)
+		}
+		if len(refs) == 0 {
+			continue
+		}
+		for action := range result {
+			if !actionScopeMatches(actionEntities, p.Action, action) {
+				continue
+			}
+			for _, ref := range refs {
+				key := ref.String()
+				if seen[action][key] {
+					continue
+				}
+				seen[action][key] = true
+				result[action] = append(result[action], ref)
+			}
+		}
+	}
+
+	for action, refs := range result {
+		slices.SortFunc(refs, func(a, b ContextRef) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		result[action] = refs
+	}
+	return result
+}
+
+// actionScopeMatches reports whether a policy's action scope admits action.
+func actionScopeMatches(actionEntities types.EntityMap, scope ast.IsActionScopeNode, action types.EntityUID) bool {
+	switch sc := scope.(type) {
+	case ast.ScopeTypeAll:
+		return true
+	case ast.ScopeTypeEq:
+		return sc.Entity == action
+	case ast.ScopeTypeIn:
+		return actionIn(actionEntities, action, sc.Entity)
+	case ast.ScopeTypeInSet:
+		return slices.ContainsFunc(sc.Entities, func(target types.EntityUID) bool {
+			return actionIn(actionEntities, action, target)
+		})
+	}
+	return false
+}
+
+// actionIn reports whether action is target or a transitive member of it
+// according to the schema's action hierarchy.
+func actionIn(actionEntities types.EntityMap, action, target types.EntityUID) bool {
+	visited := map[types.EntityUID]bool{}
+	queue := []types.EntityUID{action}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == target {
+			return true
+		}
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		if e, ok := actionEntities[cur]; ok {
+			queue = append(queue, slices.Collect(e.Parents.All())...)
+		}
+	}
+	return false
+}
+
+// collectContextRefs appends the context attribute paths read within n.
+func collectContextRefs(refs *[]ContextRef, n ast.IsNode) {
+	if n == nil {
+		return
+	}
+	if path, ok := contextPath(n); ok {
+		*refs = append(*refs, ContextRef{Path: path})
+		return
+	}
+	for _, child := range getNodeChildren(n) {
+		collectContextRefs(refs, child)
+	}
+}
+
+// contextPath returns the attribute path of an access or has chain rooted at
+// the context variable.
+func contextPath(n ast.IsNode) ([]types.String, bool) {
+	p, ok := ast.AttributePathOf(n)
+	if !ok || p.Variable != "context" {
+		return nil, false
+	}
+	return p.Attributes, true
+}
@@ -0,0 +1,36 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// ContextProvider is an alias for [types.ContextProvider].
+type ContextProvider = types.ContextProvider
+
+// WithContextProvider makes Eval fetch context attributes that Env.Context
+// does not hold from p on first access by name, with . or has. Each attribute
+// is fetched at most once per evaluation. Using the whole context as a value
+// sees only the attributes of Env.Context.
+//
+// To authorize a request with a ContextProvider, use
+// [cedar.WithContextProvider].
+func WithContextProvider(p ContextProvider) Option {
+	return func(c *evalConfig) {
+		c.env.ContextProvider = eval.MemoizeContextProvider(p)
+	}
+}
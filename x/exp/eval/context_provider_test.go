@@ -0,0 +1,51 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+type recordingContextProvider struct {
+	values types.RecordMap
+	calls  []string
+}
+
+func (p *recordingContextProvider) Get(key string) (types.Value, bool, error) {
+	p.calls = append(p.calls, key)
+	if key == "broken" {
+		return nil, false, errors.New("lookup failed")
+	}
+	v, ok := p.values[types.String(key)]
+	return v, ok, nil
+}
+
+func TestEvalWithContextProvider(t *testing.T) {
+	p := &recordingContextProvider{values: types.RecordMap{"country": types.String("NZ")}}
+	node := ast.Context().Access("country").Equal(ast.String("NZ")).And(ast.Context().Has("country"))
+	v, err := Eval(node.AsIsNode(), Env{}, WithContextProvider(p))
+	testutil.OK(t, err)
+	testutil.Equals(t, v, types.Value(types.True))
+	testutil.Equals(t, p.calls, []string{"country"})
+
+	v, err = Eval(ast.Context().AsIsNode(), Env{Context: types.Record{}}, WithContextProvider(p))
+	testutil.OK(t, err)
+	testutil.Equals(t, v, types.Value(types.Record{}))
+}
@@ -0,0 +1,89 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func refStrings(refs []ContextRef) []string {
+	out := make([]string, len(refs))
+	for i, r := range refs {
+		out[i] = r.String()
+	}
+	return out
+}
+
+func TestRequiredContext(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		action readOnly;
+		action view in [readOnly] appliesTo { principal: User, resource: Document };
+		action download in [readOnly] appliesTo { principal: User, resource: Document };
+		action edit appliesTo { principal: User, resource: Document };
+		action delete appliesTo { principal: User, resource: Document };
+	`))
+	testutil.OK(t, err)
+
+	view := types.NewEntityUID("Action", "view")
+	download := types.NewEntityUID("Action", "download")
+	edit := types.NewEntityUID("Action", "edit")
+	del := types.NewEntityUID("Action", "delete")
+
+	policies := map[types.PolicyID]*ast.Policy{
+		"all": ast.Permit().When(ast.Context().Access("authenticated")),
+		"eq": ast.Permit().ActionEq(edit).When(
+			ast.Context().Has("device").And(ast.Context().Access("device").Access("trusted")),
+		),
+		"group": ast.Permit().ActionIn(types.NewEntityUID("Action", "readOnly")).When(
+			ast.Context().Access("ip").NotEqual(ast.String("")),
+		),
+		"set": ast.Forbid().ActionInSet(download, edit).Unless(
+			ast.Context().Access("mfa"),
+		),
+		"noContext": ast.Permit().ActionEq(del).When(ast.Principal().Has("role")),
+	}
+
+	got := RequiredContext(policies, s)
+	testutil.Equals(t, len(got), 4)
+	testutil.Equals(t, refStrings(got[view]), []string{"context.authenticated", "context.ip"})
+	testutil.Equals(t, refStrings(got[download]), []string{"context.authenticated", "context.ip", "context.mfa"})
+	testutil.Equals(t, refStrings(got[edit]), []string{"context.authenticated", "context.device", "context.device.trusted", "context.mfa"})
+	testutil.Equals(t, refStrings(got[del]), []string{"context.authenticated"})
+}
+
+func TestRequiredContextNoPolicies(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		action view appliesTo { principal: User, resource: User };
+	`))
+	testutil.OK(t, err)
+
+	got := RequiredContext(nil, s)
+	testutil.Equals(t, got, map[types.EntityUID][]ContextRef{
+		types.NewEntityUID("Action", "view"): {},
+	})
+}
+
+func TestContextRefString(t *testing.T) {
+	testutil.Equals(t, ContextRef{}.String(), "context")
+	testutil.Equals(t, ContextRef{Path: []types.String{"a", "b"}}.String(), "context.a.b")
+}
@@ -0,0 +1,104 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// CoverageReport records which policies determined the decisions for a set of
+// requests. It marshals to JSON so that test suites can persist it or enforce
+// a minimum coverage.
+type CoverageReport struct {
+	// Requests is the number of requests evaluated.
+	Requests int `json:"requests"`
+
+	// Hits maps each policy to the number of requests it was determining for.
+	// Every policy has an entry, including those that were never determining.
+	Hits map[types.PolicyID]int `json:"hits"`
+
+	// Uncovered lists, in order, the policies that were never determining.
+	Uncovered []types.PolicyID `json:"uncovered"`
+}
+
+// Ratio returns the fraction of policies that were determining for at least
+// one request. An empty policy set is fully covered.
+func (r CoverageReport) Ratio() float64 {
+	if len(r.Hits) == 0 {
+		return 1
+	}
+	return float64(len(r.Hits)-len(r.Uncovered)) / float64(len(r.Hits))
+}
+
+// Coverage evaluates each request against policies and counts, per policy, the
+// requests it was determining for. As in Cedar's authorization diagnostics,
+// the determining policies of a denied request are all satisfied forbids and
+// those of an allowed request are all satisfied permits; a request denied
+// because no policy is satisfied has none.
+//
+// Unlike analyses of production decision logs, Coverage is meant for test
+// suites: it reports how often each policy was exercised, much like code
+// coverage.
+func Coverage(policies map[types.PolicyID]*ast.Policy, entities types.EntityMap, requests []types.Request) CoverageReport {
+	report := CoverageReport{
+		Requests:  len(requests),
+		Hits:      make(map[types.PolicyID]int, len(policies)),
+		Uncovered: []types.PolicyID{},
+	}
+	for id := range policies {
+		report.Hits[id] = 0
+	}
+	for _, req := range requests {
+		env := Env{
+			Principal: req.Principal,
+			Action:    req.Action,
+			Resource:  req.Resource,
+			Context:   req.Context,
+			Entities:  entities,
+		}
+		for _, id := range determiningPolicies(PartialPolicySet(env, policies)) {
+			report.Hits[id]++
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(report.Hits)) {
+		if report.Hits[id] == 0 {
+			report.Uncovered = append(report.Uncovered, id)
+		}
+	}
+	return report
+}
+
+// determiningPolicies returns the satisfied forbids if there are any, and the
+// satisfied permits otherwise.
+func determiningPolicies(residuals *ResidualSet) []types.PolicyID {
+	if forbids := satisfiedPolicies(residuals.Forbids); len(forbids) > 0 {
+		return forbids
+	}
+	return satisfiedPolicies(residuals.Permits)
+}
+
+func satisfiedPolicies(residuals []ResidualPolicy) []types.PolicyID {
+	var ids []types.PolicyID
+	for _, r := range residuals {
+		if r.Kind == ResidualTrue {
+			ids = append(ids, r.PolicyID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,69 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestCoverage(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	view := types.NewEntityUID("Action", "view")
+	edit := types.NewEntityUID("Action", "edit")
+	doc := types.NewEntityUID("Document", "readme")
+
+	entities := types.EntityMap{
+		bob: {UID: bob, Attributes: types.NewRecord(types.RecordMap{"suspended": types.True})},
+	}
+	policies := map[types.PolicyID]*ast.Policy{
+		"view":      ast.Permit().ActionEq(view),
+		"alice":     ast.Permit().PrincipalEq(alice),
+		"suspended": ast.Forbid().When(ast.Principal().Has("suspended")),
+		"delete":    ast.Permit().ActionEq(types.NewEntityUID("Action", "delete")),
+	}
+	requests := []types.Request{
+		{Principal: alice, Action: view, Resource: doc},
+		{Principal: alice, Action: edit, Resource: doc},
+		{Principal: bob, Action: view, Resource: doc},
+		{Principal: bob, Action: edit, Resource: doc},
+	}
+
+	report := Coverage(policies, entities, requests)
+	testutil.Equals(t, report.Requests, 4)
+	testutil.Equals(t, report.Hits, map[types.PolicyID]int{
+		"view":      1,
+		"alice":     2,
+		"suspended": 2,
+		"delete":    0,
+	})
+	testutil.Equals(t, report.Uncovered, []types.PolicyID{"delete"})
+	testutil.Equals(t, report.Ratio(), 0.75)
+
+	b, err := json.Marshal(report)
+	testutil.OK(t, err)
+	testutil.Equals(t, string(b), `{"requests":4,"hits":{"alice":2,"delete":0,"suspended":2,"view":1},"uncovered":["delete"]}`)
+}
+
+func TestCoverageEmpty(t *testing.T) {
+	report := Coverage(nil, nil, nil)
+	testutil.Equals(t, report.Ratio(), 1.0)
+	testutil.Equals(t, report.Uncovered, []types.PolicyID{})
+}
@@ -0,0 +1,70 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// Discover returns the decision for every action that s declares as applying
+// to the resource's type, for answering "what can principal do with
+// resource?" in one call. Unlike QueryActions, which lists the actions that
+// are allowed, the result also holds the actions that are denied, and it only
+// covers actions that the schema allows for the resource.
+//
+// Each action is decided as by QueryDecision, with the same options. The
+// action hierarchy of s is used for action entities that entities does not
+// hold, so that policies scoped to action groups apply.
+//
+// Example:
+//
+//	decisions := eval.Discover(policies, s, entities,
+//	    types.NewEntityUID("User", "alice"),
+//	    types.NewEntityUID("Document", "report.pdf"),
+//	    types.Record{})
+//	for action, decision := range decisions {
+//	    // decision is Allow or Deny for each applicable action
+//	}
+func Discover(
+	policies map[types.PolicyID]*ast.Policy,
+	s *schema.Schema,
+	entities types.EntityMap,
+	principal types.EntityUID,
+	resource types.EntityUID,
+	context types.Record,
+	opts ...DecisionOption,
+) map[types.EntityUID]types.Decision {
+	withActions := maps.Clone(s.ActionEntities())
+	if withActions == nil {
+		withActions = types.EntityMap{}
+	}
+	maps.Copy(withActions, entities)
+
+	decisions := map[types.EntityUID]types.Decision{}
+	for action := range s.Actions() {
+		info, _ := s.ActionInfo(action)
+		if !slices.Contains(info.ResourceTypes, resource.Type) {
+			continue
+		}
+		result := QueryDecision(policies, withActions, principal, action, resource, context, opts...)
+		decisions[action] = result.Decision
+	}
+	return decisions
+}
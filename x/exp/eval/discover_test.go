@@ -0,0 +1,102 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		entity Group;
+		action read;
+		action view, comment in [read] appliesTo { principal: User, resource: Document };
+		action delete appliesTo { principal: User, resource: Document };
+		action join appliesTo { principal: User, resource: Group };
+	`))
+	testutil.OK(t, err)
+
+	alice := types.NewEntityUID("User", "alice")
+	doc := types.NewEntityUID("Document", "readme")
+	view := types.NewEntityUID("Action", "view")
+	comment := types.NewEntityUID("Action", "comment")
+	del := types.NewEntityUID("Action", "delete")
+	read := types.NewEntityUID("Action", "read")
+
+	policies := map[types.PolicyID]*ast.Policy{
+		"readers": ast.Permit().ActionIn(read),
+		"delete":  ast.Permit().ActionEq(del),
+		"noComments": ast.Forbid().ActionEq(comment).
+			When(ast.Context().Has("locked")),
+	}
+
+	t.Run("allActionsForResourceType", func(t *testing.T) {
+		t.Parallel()
+		got := Discover(policies, s, nil, alice, doc, types.Record{})
+		testutil.Equals(t, got, map[types.EntityUID]types.Decision{
+			view:    types.Allow,
+			comment: types.Allow,
+			del:     types.Allow,
+		})
+	})
+
+	t.Run("explicitDeny", func(t *testing.T) {
+		t.Parallel()
+		ctx := types.NewRecord(types.RecordMap{"locked": types.True})
+		got := Discover(policies, s, nil, alice, doc, ctx)
+		testutil.Equals(t, got[comment], types.Deny)
+		testutil.Equals(t, got[view], types.Allow)
+	})
+
+	t.Run("noPolicyMeansDeny", func(t *testing.T) {
+		t.Parallel()
+		got := Discover(map[types.PolicyID]*ast.Policy{}, s, nil, alice, doc, types.Record{})
+		testutil.Equals(t, got, map[types.EntityUID]types.Decision{
+			view:    types.Deny,
+			comment: types.Deny,
+			del:     types.Deny,
+		})
+	})
+
+	t.Run("otherResourceType", func(t *testing.T) {
+		t.Parallel()
+		group := types.NewEntityUID("Group", "admins")
+		got := Discover(map[types.PolicyID]*ast.Policy{"all": ast.Permit()}, s, nil, alice, group, types.Record{})
+		testutil.Equals(t, got, map[types.EntityUID]types.Decision{
+			types.NewEntityUID("Action", "join"): types.Allow,
+		})
+	})
+
+	t.Run("undeclaredResourceType", func(t *testing.T) {
+		t.Parallel()
+		got := Discover(policies, s, nil, alice, types.NewEntityUID("Photo", "p"), types.Record{})
+		testutil.Equals(t, len(got), 0)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+		ctx := types.NewRecord(types.RecordMap{"locked": types.True})
+		got := Discover(policies, s, nil, alice, doc, ctx, WithCombiningAlgorithm(PermitOverrides))
+		testutil.Equals(t, got[comment], types.Allow)
+	})
+}
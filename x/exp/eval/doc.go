@@ -137,6 +137,69 @@
 //	    fmt.Printf("Denied. Determining policies: %v\n", result.DeterminingPolicies)
 //	}
 //
+// QueryDecisionBatch decides many requests in order. When its context
+// expires it returns the results computed so far, the number of completed
+// requests and the context's error, so that a service can answer with partial
+// results under load.
+//
+// For what-if analysis, WithCombiningAlgorithm(PermitOverrides) decides as if
+// any matching permit overrode matching forbids. This is not how Cedar
+// authorizes requests; DenyOverrides is the default and the only algorithm
+// that matches the specification.
+//
+// By default the query functions evaluate whatever request they are given.
+// WithSchemaValidation checks the request, including its context, against a
+// schema first, so that a context of the wrong shape is reported in the
+// result's Err instead of silently affecting the decision:
+//
+//	result := eval.QueryDecision(policies, entities, principal, action, resource, ctx,
+//	    eval.WithSchemaValidation(s))
+//	if result.Err != nil {
+//	    return result.Err
+//	}
+//
+// Discover decides every action that a schema declares for the resource's
+// type, including the denied ones, which suits APIs that list what a
+// principal can do with a resource:
+//
+//	for action, decision := range eval.Discover(policies, s, entities, principal, resource, ctx) {
+//	    fmt.Printf("%s: %s\n", action, decision)
+//	}
+//
+// AccessSummary answers "who can do this?" with the rules rather than the
+// principals: each GrantClause is the principal scope and residual conditions
+// of the permits that may grant the access, such as
+// `principal in Group::"editors"` or, with the resource's owner filled in,
+// `principal when { User::"alice" == principal }`.
+//
+// MembershipChangeImpact previews the permissions that a principal gains and
+// loses when it joins or leaves groups. It checks each action and resource type
+// of the schema with the resource and context unknown, so it over-approximates
+// what the principal can do:
+//
+//	delta := eval.MembershipChangeImpact(policies, s, entities, alice, []types.EntityUID{admins}, nil)
+//	for _, p := range delta.Gained {
+//	    fmt.Printf("grants %s on %s\n", p.Action, p.ResourceType)
+//	}
+//
+// Coverage reports, for a test suite of requests, how many requests each policy
+// was determining for and which policies never were. The report marshals to
+// JSON, which makes it easy to enforce a coverage threshold in CI:
+//
+//	report := eval.Coverage(policies, entities, requests)
+//	if report.Ratio() < 0.9 {
+//	    log.Fatalf("policies never exercised: %v", report.Uncovered)
+//	}
+//
+// TruthTable helps review a policy with several boolean context flags by
+// evaluating it for every combination of their values, with the rest of the
+// request held fixed:
+//
+//	rows, err := eval.TruthTable(policy, s, entities, req, []string{"mfa", "internal"})
+//	for _, row := range rows {
+//	    fmt.Println(row.Values, row.Matches) // e.g. map[internal:true mfa:false] true
+//	}
+//
 // # Understanding Query Results
 //
 // QueryResult contains several fields to help understand the query outcome:
@@ -146,6 +209,8 @@
 //   - SatisfyingValues: Specific EntityUIDs that satisfy the query
 //   - Definite: True if the result is conclusive (no residual policies)
 //   - Constraints: Residual constraints that couldn't be fully resolved
+//   - ExcludedBy: The forbid policies that removed values a permit names, such
+//     as report.pdf excluded by forbid-confidential
 //
 // When Definite is false, it means there are residual policies that depend on
 // runtime information not available during the query. The Constraints field
@@ -175,4 +240,116 @@
 //
 // The package also provides EntityLoader for dynamic entity loading during
 // evaluation, which is useful when you don't want to load all entities upfront.
+// CompositeEntityLoader federates several loaders, for example one per backing
+// service, and NewPrefixEntityLoader restricts a loader to the entity types it
+// owns. A loader may report missing entities by returning ErrEntityNotFound, in
+// which case the composite falls through to the next loader:
+//
+//	loader := eval.NewCompositeEntityLoader(
+//	    eval.NewPrefixEntityLoader(users, "User"),
+//	    eval.NewPrefixEntityLoader(docs, "Docs::"),
+//	)
+//
+// By default, reading an attribute of an entity that is not in the entity
+// store is an error. The WithMissingEntitiesAsEmpty option instead treats such
+// entities as present with no attributes, tags, or parents. This departs from
+// the Cedar specification and is meant for tolerating incomplete entity data:
+//
+//	v, err := eval.Eval(n, env, eval.WithMissingEntitiesAsEmpty())
+//
+// WithEntityPatches answers what-if questions by adding or removing parents
+// and attributes of some entities for a single evaluation, leaving the
+// entities themselves untouched:
+//
+//	v, err := eval.Eval(n, env, eval.WithEntityPatches(map[types.EntityUID]eval.EntityPatch{
+//	    alice: {AddParents: []types.EntityUID{admins}},
+//	}))
+//
+// # Required Context
+//
+// RequiredContext reports which context attributes each schema action's
+// policies read. This is useful for building request forms that only ask for
+// the context fields that can affect a decision:
+//
+//	refs := eval.RequiredContext(policies, s)
+//	for _, ref := range refs[types.NewEntityUID("Action", "view")] {
+//	    fmt.Println(ref) // e.g. "context.device.trusted"
+//	}
+//
+// CollectReadValues resolves the attribute reads performed by the policies that
+// apply to a request, which is useful for recording the inputs of a decision:
+//
+//	values, errs := eval.CollectReadValues(policies, entities, req)
+//	fmt.Println(values["principal.department"]) // e.g. Department::"eng"
+//
+// # Preparing Context
+//
+// PrepareContext checks a raw context record, such as one decoded from a
+// request body, against the context type the schema declares for an action.
+// Strings given for extension-typed attributes are parsed into the extension
+// value, absent optional attributes take the value of their @default
+// annotation, and attributes a closed context does not declare are rejected.
+// Every problem found is reported in the returned error:
+//
+//	ctx, err := eval.PrepareContext(s, types.NewEntityUID("Action", "view"), raw)
+//	if errors.Is(err, eval.ErrInvalidContext) {
+//	    return err // lists each offending attribute, e.g. "context.clientIp"
+//	}
+//
+// # Lazy Context
+//
+// WithContextProvider makes Eval fetch context attributes from a
+// ContextProvider the first time an expression reads them, so costly
+// attributes such as a geo-IP lookup are only computed when needed.
+// Attributes in Env.Context are used as is, and each provided attribute is
+// fetched at most once per evaluation. Only attributes read by name, with .
+// or has, are fetched; using the whole context as a value sees only
+// Env.Context:
+//
+//	v, err := eval.Eval(expr, env, eval.WithContextProvider(geoIP))
+//
+// cedar.WithContextProvider does the same for cedar.Authorize.
+//
+// # Current Time
+//
+// WithNowContext adds the current time to the context as a datetime, unless
+// the context already holds the key, so that time-based policies do not need
+// every caller to supply it. WithClock replaces time.Now, which makes such
+// policies testable:
+//
+//	v, err := eval.Eval(expr, env, eval.WithClock(fixedClock), eval.WithNowContext("now"))
+//
+// # Ancestor Depth
+//
+// WithMaxAncestorDepth bounds the cost of `in` checks on deep or maliciously
+// constructed entity hierarchies by following at most n parent hops. Deeper
+// ancestors are treated as unreachable, or, with WithAncestorDepthErrors, make
+// the check fail with ErrAncestorDepthExceeded. There is no limit by default,
+// and a limit that is too low misses legitimate memberships and so changes
+// decisions:
+//
+//	v, err := eval.Eval(n, env, eval.WithMaxAncestorDepth(32), eval.WithAncestorDepthErrors())
+//
+// # Benchmarking
+//
+// Benchmark authorizes a workload repeatedly and reports the average time,
+// allocations and allocated bytes per authorization, so that CI can track the
+// same numbers across releases:
+//
+//	r := eval.Benchmark(policies, entities, requests, 1000)
+//	fmt.Println(r) // e.g. 2000	1520 ns/op	2048 B/op	31 allocs/op
+//
+// # Standalone Expressions
+//
+// EvalExpr evaluates a single Cedar expression outside of any policy, which
+// is useful for rules such as feature flags. Every identifier in the
+// expression must be bound:
+//
+//	v, err := eval.EvalExpr(`context.plan == "pro" && rollout < 50`,
+//	    map[string]types.Value{
+//	        "context": types.NewRecord(types.RecordMap{"plan": types.String("pro")}),
+//	        "rollout": types.Long(25),
+//	    },
+//	    entities,
+//	)
 package eval
@@ -16,7 +16,9 @@ package eval
 
 import (
 	"context"
+	"errors"
 	"maps"
+	"strings"
 
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/ast"
@@ -46,6 +48,12 @@ type EntityLoader interface {
 	Load(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error)
 }
 
+// ErrEntityNotFound may be returned, possibly wrapped, by an EntityLoader
+// that reports missing entities as an error rather than by omitting them
+// from its result. CompositeEntityLoader treats it as "none of the requested
+// entities exist" and tries the next loader.
+var ErrEntityNotFound = errors.New("entity not found")
+
 // EntityLoaderFunc is an adapter to allow using ordinary functions
 // as EntityLoaders.
 type EntityLoaderFunc func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error)
@@ -181,6 +189,113 @@ func (c *CachingEntityLoader) ClearCache() {
 	c.notFound = make(map[types.EntityUID]struct{})
 }
 
+// EntityTypeFilter is implemented by EntityLoaders that only serve some
+// entity types. CompositeEntityLoader does not ask such a loader for
+// entities of other types.
+type EntityTypeFilter interface {
+	ServesEntityType(entityType types.EntityType) bool
+}
+
+// PrefixEntityLoader wraps an EntityLoader so that it only serves entity
+// types matching one of a set of prefixes. A prefix such as "User" matches
+// that entity type and the types in a namespace of that name, such as
+// "User::Profile", but not "UserGroup". A prefix ending in "::", such as
+// "Docs::", matches only the types in that namespace.
+type PrefixEntityLoader struct {
+	loader   EntityLoader
+	prefixes []string
+}
+
+// NewPrefixEntityLoader creates an EntityLoader that serves only entity types
+// matching one of the given prefixes.
+func NewPrefixEntityLoader(loader EntityLoader, prefixes ...string) *PrefixEntityLoader {
+	return &PrefixEntityLoader{loader: loader, prefixes: prefixes}
+}
+
+// ServesEntityType implements EntityTypeFilter.
+func (p *PrefixEntityLoader) ServesEntityType(entityType types.EntityType) bool {
+	et := string(entityType)
+	for _, prefix := range p.prefixes {
+		if !strings.HasSuffix(prefix, "::") && et == prefix {
+			return true
+		}
+		if strings.HasPrefix(et, strings.TrimSuffix(prefix, "::")+"::") {
+			return true
+		}
+	}
+	return false
+}
+
+// Load implements EntityLoader. UIDs of types the loader does not serve are
+// treated as missing.
+func (p *PrefixEntityLoader) Load(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+	served := servedUIDs(p, uids)
+	if len(served) == 0 {
+		return types.EntityMap{}, nil
+	}
+	return p.loader.Load(ctx, served)
+}
+
+// CompositeEntityLoader federates several EntityLoaders. Each UID is served
+// by the first loader, in order, that returns it.
+type CompositeEntityLoader struct {
+	loaders []EntityLoader
+}
+
+// NewCompositeEntityLoader creates an EntityLoader that tries each of the
+// given loaders in order.
+func NewCompositeEntityLoader(loaders ...EntityLoader) *CompositeEntityLoader {
+	return &CompositeEntityLoader{loaders: loaders}
+}
+
+// Load implements EntityLoader. Each loader receives at most one batch: the
+// UIDs still missing that it serves, according to EntityTypeFilter if it
+// implements it. A loader that returns ErrEntityNotFound is skipped; any
+// other error aborts the load.
+func (c *CompositeEntityLoader) Load(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+	result := make(types.EntityMap, len(uids))
+	pending := uids
+	for _, loader := range c.loaders {
+		if len(pending) == 0 {
+			break
+		}
+		batch := pending
+		if f, ok := loader.(EntityTypeFilter); ok {
+			batch = servedUIDs(f, pending)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		loaded, err := loader.Load(ctx, batch)
+		if errors.Is(err, ErrEntityNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var missing []types.EntityUID
+		for _, uid := range pending {
+			if entity, ok := loaded[uid]; ok {
+				result[uid] = entity
+			} else {
+				missing = append(missing, uid)
+			}
+		}
+		pending = missing
+	}
+	return result, nil
+}
+
+func servedUIDs(f EntityTypeFilter, uids []types.EntityUID) []types.EntityUID {
+	var served []types.EntityUID
+	for _, uid := range uids {
+		if f.ServesEntityType(uid.Type) {
+			served = append(served, uid)
+		}
+	}
+	return served
+}
+
 // LoadingEntityGetter adapts an EntityLoader to implement types.EntityGetter.
 // This allows using an EntityLoader where an EntityGetter is expected.
 //
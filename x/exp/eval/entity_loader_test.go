@@ -17,6 +17,7 @@ package eval
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"testing"
 
@@ -222,6 +223,137 @@ func TestTrackingEntityLoaderError(t *testing.T) {
 	}
 }
 
+func TestCompositeEntityLoader(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	doc := types.NewEntityUID("Docs::File", "readme")
+	charlie := types.NewEntityUID("User", "charlie")
+
+	var userCalls, docCalls, fallbackCalls [][]types.EntityUID
+	users := NewPrefixEntityLoader(EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		userCalls = append(userCalls, uids)
+		return types.EntityMap{alice: {UID: alice}}, nil
+	}), "User")
+	docs := NewPrefixEntityLoader(EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		docCalls = append(docCalls, uids)
+		return types.EntityMap{doc: {UID: doc}}, nil
+	}), "Docs::")
+	fallback := EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		fallbackCalls = append(fallbackCalls, uids)
+		return types.EntityMap{bob: {UID: bob}}, nil
+	})
+
+	loader := NewCompositeEntityLoader(users, docs, fallback)
+	result, err := loader.Load(context.Background(), []types.EntityUID{alice, doc, bob, charlie})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected 3 entities, got %d", len(result))
+	}
+	for _, uid := range []types.EntityUID{alice, bob, doc} {
+		if _, ok := result[uid]; !ok {
+			t.Errorf("expected %v in result", uid)
+		}
+	}
+
+	// Each loader is called once with only the UIDs it serves and that are still missing.
+	if want := [][]types.EntityUID{{alice, bob, charlie}}; !slices.EqualFunc(userCalls, want, slices.Equal) {
+		t.Errorf("user loader calls = %v, want %v", userCalls, want)
+	}
+	if want := [][]types.EntityUID{{doc}}; !slices.EqualFunc(docCalls, want, slices.Equal) {
+		t.Errorf("docs loader calls = %v, want %v", docCalls, want)
+	}
+	if want := [][]types.EntityUID{{bob, charlie}}; !slices.EqualFunc(fallbackCalls, want, slices.Equal) {
+		t.Errorf("fallback loader calls = %v, want %v", fallbackCalls, want)
+	}
+}
+
+func TestCompositeEntityLoaderSkipsUnservedLoaders(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	called := false
+	docs := NewPrefixEntityLoader(EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		called = true
+		return nil, nil
+	}), "Docs::")
+
+	result, err := NewCompositeEntityLoader(docs).Load(context.Background(), []types.EntityUID{alice})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected 0 entities, got %d", len(result))
+	}
+	if called {
+		t.Error("expected loader not serving User to be skipped")
+	}
+
+	result, err = docs.Load(context.Background(), []types.EntityUID{alice})
+	if err != nil || len(result) != 0 || called {
+		t.Errorf("expected direct load of unserved type to be empty, got %v, %v", result, err)
+	}
+}
+
+func TestPrefixEntityLoaderServesEntityType(t *testing.T) {
+	loader := NewPrefixEntityLoader(EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		return nil, nil
+	}), "User", "Docs::")
+	tests := []struct {
+		entityType types.EntityType
+		want       bool
+	}{
+		{"User", true},
+		{"User::Profile", true},
+		{"UserGroup", false},
+		{"Docs::File", true},
+		{"Docs::Folder::File", true},
+		{"Docs", false},
+		{"DocsArchive::File", false},
+	}
+	for _, tt := range tests {
+		if got := loader.ServesEntityType(tt.entityType); got != tt.want {
+			t.Errorf("ServesEntityType(%q) = %v, want %v", tt.entityType, got, tt.want)
+		}
+	}
+}
+
+func TestCompositeEntityLoaderNotFound(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	notFound := EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		return nil, fmt.Errorf("user service: %w", ErrEntityNotFound)
+	})
+	found := NewMapEntityLoader(types.EntityMap{alice: {UID: alice}})
+
+	result, err := NewCompositeEntityLoader(notFound, found).Load(context.Background(), []types.EntityUID{alice})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result[alice]; !ok {
+		t.Error("expected alice in result")
+	}
+}
+
+func TestCompositeEntityLoaderError(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	expectedErr := errors.New("load failed")
+	failing := EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		return nil, expectedErr
+	})
+	called := false
+	next := EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
+		called = true
+		return nil, nil
+	})
+
+	_, err := NewCompositeEntityLoader(failing, next).Load(context.Background(), []types.EntityUID{alice})
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("expected error %v, got %v", expectedErr, err)
+	}
+	if called {
+		t.Error("expected load to abort before the next loader")
+	}
+}
+
 func TestLoadingEntityGetterError(t *testing.T) {
 	expectedErr := errors.New("load failed")
 	loader := EntityLoaderFunc(func(ctx context.Context, uids []types.EntityUID) (types.EntityMap, error) {
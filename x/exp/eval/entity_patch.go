@@ -0,0 +1,92 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// EntityPatch describes changes to one entity that apply to a single
+// evaluation, such as adding a parent to ask "if alice were in the admins
+// group, could she...?". Removals are applied before additions, so a parent
+// or attribute that is both removed and added is present afterwards.
+type EntityPatch struct {
+	AddParents    []types.EntityUID
+	RemoveParents []types.EntityUID
+	// SetAttributes adds attributes or replaces their values.
+	SetAttributes    types.RecordMap
+	RemoveAttributes []types.String
+}
+
+// apply returns a copy of e with the patch applied.
+func (p EntityPatch) apply(e types.Entity) types.Entity {
+	parents := slices.DeleteFunc(e.Parents.Slice(), func(uid types.EntityUID) bool {
+		return slices.Contains(p.RemoveParents, uid)
+	})
+	e.Parents = types.NewEntityUIDSet(append(parents, p.AddParents...)...)
+
+	attrs := e.Attributes.Map()
+	if attrs == nil {
+		attrs = types.RecordMap{}
+	}
+	for _, k := range p.RemoveAttributes {
+		delete(attrs, k)
+	}
+	maps.Copy(attrs, p.SetAttributes)
+	e.Attributes = types.NewRecord(attrs)
+	return e
+}
+
+// WithEntityPatches makes Eval see the entities of Env.Entities with patches
+// applied, without modifying or copying the underlying entities. A patch for
+// an entity that does not exist creates it. Because parents are patched
+// before ancestors are resolved, adding or removing a parent changes the
+// result of in for the patched entity and its descendants.
+func WithEntityPatches(patches map[types.EntityUID]EntityPatch) Option {
+	return func(c *evalConfig) {
+		env := &c.env
+		patched := make(types.EntityMap, len(patches))
+		for uid, p := range patches {
+			var e types.Entity
+			if env.Entities != nil {
+				e, _ = env.Entities.Get(uid)
+			}
+			e.UID = uid
+			patched[uid] = p.apply(e)
+		}
+		env.Entities = patchedEntities{entities: env.Entities, patched: patched}
+	}
+}
+
+// patchedEntities is an EntityGetter that serves patched entities in place of
+// those of its underlying getter. It deliberately does not expose an
+// ancestry cache, so that ancestors are resolved from the patched parents.
+type patchedEntities struct {
+	entities types.EntityGetter
+	patched  types.EntityMap
+}
+
+func (p patchedEntities) Get(uid types.EntityUID) (types.Entity, bool) {
+	if e, ok := p.patched[uid]; ok {
+		return e, true
+	}
+	if p.entities == nil {
+		return types.Entity{}, false
+	}
+	return p.entities.Get(uid)
+}
@@ -0,0 +1,144 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestWithEntityPatches(t *testing.T) {
+	t.Parallel()
+	alice := types.NewEntityUID("User", "alice")
+	staff := types.NewEntityUID("Group", "staff")
+	admins := types.NewEntityUID("Group", "admins")
+	root := types.NewEntityUID("Group", "root")
+	newcomer := types.NewEntityUID("User", "newcomer")
+
+	newEntities := func() types.EntityMap {
+		return types.EntityMap{
+			alice: {
+				UID:        alice,
+				Parents:    types.NewEntityUIDSet(staff),
+				Attributes: types.NewRecord(types.RecordMap{"level": types.Long(1), "name": types.String("Alice")}),
+			},
+			staff:  {UID: staff},
+			admins: {UID: admins, Parents: types.NewEntityUIDSet(root)},
+			root:   {UID: root},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		expr    ast.Node
+		patches map[types.EntityUID]EntityPatch
+		want    types.Value
+	}{
+		{
+			"unpatched",
+			ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "admins")),
+			nil,
+			types.False,
+		},
+		{
+			"addParent",
+			ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "admins")),
+			map[types.EntityUID]EntityPatch{alice: {AddParents: []types.EntityUID{admins}}},
+			types.True,
+		},
+		{
+			"addParentChangesTransitiveAncestors",
+			ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "root")),
+			map[types.EntityUID]EntityPatch{alice: {AddParents: []types.EntityUID{admins}}},
+			types.True,
+		},
+		{
+			"patchedAncestor",
+			ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "root")),
+			map[types.EntityUID]EntityPatch{staff: {AddParents: []types.EntityUID{root}}},
+			types.True,
+		},
+		{
+			"removeParent",
+			ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "staff")),
+			map[types.EntityUID]EntityPatch{alice: {RemoveParents: []types.EntityUID{staff}}},
+			types.False,
+		},
+		{
+			"setAttribute",
+			ast.EntityUID("User", "alice").Access("level"),
+			map[types.EntityUID]EntityPatch{alice: {SetAttributes: types.RecordMap{"level": types.Long(5)}}},
+			types.Long(5),
+		},
+		{
+			"otherAttributesKept",
+			ast.EntityUID("User", "alice").Access("name"),
+			map[types.EntityUID]EntityPatch{alice: {SetAttributes: types.RecordMap{"level": types.Long(5)}}},
+			types.String("Alice"),
+		},
+		{
+			"removeAttribute",
+			ast.EntityUID("User", "alice").Has("name"),
+			map[types.EntityUID]EntityPatch{alice: {RemoveAttributes: []types.String{"name"}}},
+			types.False,
+		},
+		{
+			"newEntity",
+			ast.EntityUID("User", "newcomer").In(ast.EntityUID("Group", "root")).And(
+				ast.EntityUID("User", "newcomer").Access("level").Equal(ast.Long(2))),
+			map[types.EntityUID]EntityPatch{newcomer: {
+				AddParents:    []types.EntityUID{admins},
+				SetAttributes: types.RecordMap{"level": types.Long(2)},
+			}},
+			types.True,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			entities := newEntities()
+			got, err := Eval(tt.expr.AsIsNode(), Env{Entities: entities}, WithEntityPatches(tt.patches))
+			testutil.OK(t, err)
+			testutil.Equals(t, got, tt.want)
+			testutil.Equals(t, entities, newEntities())
+		})
+	}
+
+	t.Run("cachedEntitiesUsePatchedParents", func(t *testing.T) {
+		t.Parallel()
+		cached := types.NewCachedEntityGetter(newEntities())
+		expr := ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "admins"))
+		got, err := Eval(expr.AsIsNode(), Env{Entities: cached},
+			WithEntityPatches(map[types.EntityUID]EntityPatch{alice: {AddParents: []types.EntityUID{admins}}}))
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.True))
+	})
+
+	t.Run("nilEntities", func(t *testing.T) {
+		t.Parallel()
+		expr := ast.EntityUID("User", "alice").Access("level")
+		got, err := Eval(expr.AsIsNode(), Env{},
+			WithEntityPatches(map[types.EntityUID]EntityPatch{alice: {SetAttributes: types.RecordMap{"level": types.Long(3)}}}))
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.Long(3)))
+
+		_, err = Eval(ast.EntityUID("User", "bob").Access("level").AsIsNode(), Env{},
+			WithEntityPatches(map[types.EntityUID]EntityPatch{alice: {}}))
+		testutil.Error(t, err)
+	})
+}
@@ -2,6 +2,8 @@
 package eval
 
 import (
+	"time"
+
 	"github.com/cedar-policy/cedar-go/internal/eval"
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/ast"
@@ -10,10 +12,32 @@ import (
 // Env is the environment for evaluating a policy.
 type Env = eval.Env
 
+// Option configures evaluation by Eval and PartialPolicySet. The query
+// functions take options through WithEvalOptions.
+type Option func(*evalConfig)
+
+// evalConfig holds the environment that options change, along with settings
+// that are only resolved once every option has been applied.
+type evalConfig struct {
+	env     Env
+	clock   func() time.Time
+	nowKeys []string
+}
+
+func newEvalConfig(env Env, opts []Option) evalConfig {
+	cfg := evalConfig{env: env}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.injectNow()
+	return cfg
+}
+
 // Eval evaluates a policy node in the given environment.
-func Eval(n ast.IsNode, env Env) (types.Value, error) {
+func Eval(n ast.IsNode, env Env, opts ...Option) (types.Value, error) {
+	cfg := newEvalConfig(env, opts)
 	evaler := eval.ToEval(n)
-	return evaler.Eval(env)
+	return evaler.Eval(cfg.env)
 }
 
 // PartialPolicy returns a partially evaluated version of the policy and a boolean indicating if the policy should be kept.
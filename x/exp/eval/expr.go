@@ -0,0 +1,45 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"fmt"
+
+	"github.com/cedar-policy/cedar-go/internal/parser"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// EvalExpr parses and evaluates a standalone Cedar expression, such as
+// `context.plan == "pro" && user.age >= 18`. Each bare identifier in the
+// expression, including principal, action, resource and context, takes its
+// value from bindings; referencing an unbound name is a parse error. Entity
+// lookups, such as `in` and attribute access on entities, are resolved
+// against entities.
+//
+// Only the expression grammar is accepted, so input containing a policy or
+// any trailing tokens is rejected. Evaluation errors follow Cedar semantics,
+// the same as for a policy condition.
+func EvalExpr(expr string, bindings map[string]types.Value, entities types.EntityMap) (types.Value, error) {
+	for name := range bindings {
+		if parser.IsReservedKeyword(name) {
+			return nil, fmt.Errorf("cannot bind reserved keyword %q", name)
+		}
+	}
+	n, err := parser.ParseExpression([]byte(expr), bindings)
+	if err != nil {
+		return nil, err
+	}
+	return Eval(n.AsIsNode(), Env{Entities: entities})
+}
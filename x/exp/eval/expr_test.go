@@ -0,0 +1,86 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+func TestEvalExpr(t *testing.T) {
+	t.Parallel()
+	alice := types.NewEntityUID("User", "alice")
+	admins := types.NewEntityUID("Group", "admins")
+	entities := types.EntityMap{
+		alice: types.Entity{
+			UID:        alice,
+			Parents:    types.NewEntityUIDSet(admins),
+			Attributes: types.NewRecord(types.RecordMap{"age": types.Long(30)}),
+		},
+	}
+	bindings := map[string]types.Value{
+		"principal": alice,
+		"context":   types.NewRecord(types.RecordMap{"plan": types.String("pro")}),
+		"rollout":   types.Long(25),
+		"region":    types.String("eu-west-1"),
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want types.Value
+	}{
+		{"literal", `1 + 2`, types.Long(3)},
+		{"context", `context.plan == "pro"`, types.True},
+		{"customNames", `rollout < 50 && region like "eu-*"`, types.True},
+		{"entityAttribute", `principal.age >= 18`, types.True},
+		{"hierarchy", `principal in Group::"admins"`, types.True},
+		{"nonBoolean", `if rollout > 10 then "on" else "off"`, types.String("on")},
+		{"extension", `ip("10.0.0.1").isInRange(ip("10.0.0.0/8"))`, types.True},
+		{"shortCircuit", `false && principal.missing`, types.False},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := EvalExpr(tt.expr, bindings, entities)
+			testutil.OK(t, err)
+			testutil.Equals(t, got, tt.want)
+		})
+	}
+
+	errTests := []struct {
+		name     string
+		expr     string
+		bindings map[string]types.Value
+	}{
+		{"unboundVariable", `resource == User::"bob"`, bindings},
+		{"unboundCustom", `flag`, nil},
+		{"policy", `permit(principal, action, resource);`, bindings},
+		{"trailingTokens", `true; permit(principal, action, resource);`, bindings},
+		{"typeError", `rollout + "x"`, bindings},
+		{"missingAttribute", `principal.missing`, bindings},
+		{"overflow", `9223372036854775807 + rollout`, bindings},
+		{"reservedBinding", `true`, map[string]types.Value{"if": types.True}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := EvalExpr(tt.expr, tt.bindings, entities)
+			testutil.Error(t, err)
+		})
+	}
+}
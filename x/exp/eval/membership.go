@@ -0,0 +1,136 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// Permission is the ability to perform an action on some resource of a type.
+type Permission struct {
+	Action       types.EntityUID
+	ResourceType types.EntityType
+}
+
+// PermissionDelta lists the permissions that a change grants and revokes,
+// each sorted by action and then resource type.
+type PermissionDelta struct {
+	Gained []Permission
+	Lost   []Permission
+}
+
+// MembershipChangeImpact previews the effect of adding principal to the
+// groups in added and removing it from those in removed, for example to show
+// that "adding alice to admins grants: delete on Document". It returns the
+// permissions that principal holds after the change but not before, and the
+// reverse. The entities are not modified: the change is applied with
+// WithEntityPatches.
+//
+// A permission is held if, for some resource of the type and some context, a
+// permit policy may apply and no forbid policy certainly applies. Every action
+// that s declares for principal's type is checked against each of its
+// resource types, evaluating policies partially with the resource and context
+// as variables. Conditions on the resource or context are therefore assumed
+// to be satisfiable and forbids that depend on them are assumed not to apply,
+// so the result over-approximates: a listed permission may hold for no actual
+// resource. Policies that fail to evaluate grant nothing.
+func MembershipChangeImpact(
+	policies map[types.PolicyID]*ast.Policy,
+	s *schema.Schema,
+	entities types.EntityMap,
+	principal types.EntityUID,
+	added, removed []types.EntityUID,
+) PermissionDelta {
+	withActions := maps.Clone(s.ActionEntities())
+	if withActions == nil {
+		withActions = types.EntityMap{}
+	}
+	maps.Copy(withActions, entities)
+	patch := WithEntityPatches(map[types.EntityUID]EntityPatch{
+		principal: {AddParents: added, RemoveParents: removed},
+	})
+
+	var delta PermissionDelta
+	for _, shape := range s.AllRequestShapes() {
+		if shape.PrincipalType != principal.Type {
+			continue
+		}
+		env := Env{
+			Principal: principal,
+			Action:    shape.Action,
+			Resource:  Variable("resource"),
+			Context:   Variable("context"),
+			Entities:  withActions,
+		}
+		before := permissionReachable(policies, env, shape.ResourceType)
+		after := permissionReachable(policies, newEvalConfig(env, []Option{patch}).env, shape.ResourceType)
+		perm := Permission{Action: shape.Action, ResourceType: shape.ResourceType}
+		switch {
+		case after && !before:
+			delta.Gained = append(delta.Gained, perm)
+		case before && !after:
+			delta.Lost = append(delta.Lost, perm)
+		}
+	}
+	slices.SortFunc(delta.Gained, comparePermissions)
+	slices.SortFunc(delta.Lost, comparePermissions)
+	return delta
+}
+
+// permissionReachable reports whether a permit may apply to a resource of
+// resourceType in env, whose resource and context are variables, and no
+// forbid certainly applies.
+func permissionReachable(policies map[types.PolicyID]*ast.Policy, env Env, resourceType types.EntityType) bool {
+	residuals := PartialPolicySet(env, policies)
+	if hasDefiniteForbid(residuals) {
+		return false
+	}
+	for _, p := range residuals.Permits {
+		if (p.Kind == ResidualTrue || p.Kind == ResidualVariable) &&
+			resourceScopeAdmits(policies[p.PolicyID].Resource, resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceScopeAdmits reports whether a resource of type t may satisfy scope.
+// Only the entity type named by the scope is checked, since the resource is
+// unknown.
+func resourceScopeAdmits(scope ast.IsResourceScopeNode, t types.EntityType) bool {
+	switch s := scope.(type) {
+	case ast.ScopeTypeEq:
+		return s.Entity.Type == t
+	case ast.ScopeTypeIs:
+		return s.Type == t
+	case ast.ScopeTypeIsIn:
+		return s.Type == t
+	default:
+		return true
+	}
+}
+
+func comparePermissions(a, b Permission) int {
+	return cmp.Or(
+		a.Action.Compare(b.Action),
+		cmp.Compare(a.ResourceType, b.ResourceType),
+	)
+}
@@ -0,0 +1,100 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestMembershipChangeImpact(t *testing.T) {
+	t.Parallel()
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Group;
+		entity User in [Group];
+		entity Document, Folder;
+		action view, delete appliesTo { principal: User, resource: [Document, Folder] };
+		action archive appliesTo { principal: User, resource: Folder };
+		action join appliesTo { principal: Group, resource: Group };
+	`))
+	testutil.OK(t, err)
+
+	alice := types.NewEntityUID("User", "alice")
+	admins := types.NewEntityUID("Group", "admins")
+	staff := types.NewEntityUID("Group", "staff")
+	interns := types.NewEntityUID("Group", "interns")
+	view := types.NewEntityUID("Action", "view")
+	del := types.NewEntityUID("Action", "delete")
+	archive := types.NewEntityUID("Action", "archive")
+
+	policies := map[types.PolicyID]*ast.Policy{
+		"staffView":    ast.Permit().PrincipalIn(staff).ActionEq(view),
+		"adminsDelete": ast.Permit().PrincipalIn(admins).ActionEq(del).ResourceIs("Document"),
+		"adminsArchive": ast.Permit().PrincipalIn(admins).ActionEq(archive).
+			When(ast.Resource().Access("public")),
+		"noInterns": ast.Forbid().PrincipalIn(interns),
+		"join":      ast.Permit().ActionEq(types.NewEntityUID("Action", "join")),
+	}
+	entities := types.EntityMap{
+		alice: {UID: alice, Parents: types.NewEntityUIDSet(staff)},
+	}
+
+	t.Run("gained", func(t *testing.T) {
+		t.Parallel()
+		got := MembershipChangeImpact(policies, s, entities, alice, []types.EntityUID{admins}, nil)
+		testutil.Equals(t, got, PermissionDelta{Gained: []Permission{
+			{Action: archive, ResourceType: "Folder"},
+			{Action: del, ResourceType: "Document"},
+		}})
+	})
+
+	t.Run("lost", func(t *testing.T) {
+		t.Parallel()
+		got := MembershipChangeImpact(policies, s, entities, alice, nil, []types.EntityUID{staff})
+		testutil.Equals(t, got, PermissionDelta{Lost: []Permission{
+			{Action: view, ResourceType: "Document"},
+			{Action: view, ResourceType: "Folder"},
+		}})
+	})
+
+	t.Run("forbidRevokesEverything", func(t *testing.T) {
+		t.Parallel()
+		got := MembershipChangeImpact(policies, s, entities, alice, []types.EntityUID{interns}, nil)
+		testutil.Equals(t, got, PermissionDelta{Lost: []Permission{
+			{Action: view, ResourceType: "Document"},
+			{Action: view, ResourceType: "Folder"},
+		}})
+	})
+
+	t.Run("noChange", func(t *testing.T) {
+		t.Parallel()
+		got := MembershipChangeImpact(policies, s, entities, alice, []types.EntityUID{staff}, nil)
+		testutil.Equals(t, got, PermissionDelta{})
+	})
+
+	t.Run("principalNotInEntities", func(t *testing.T) {
+		t.Parallel()
+		bob := types.NewEntityUID("User", "bob")
+		got := MembershipChangeImpact(policies, s, nil, bob, []types.EntityUID{staff}, nil)
+		testutil.Equals(t, got, PermissionDelta{Gained: []Permission{
+			{Action: view, ResourceType: "Document"},
+			{Action: view, ResourceType: "Folder"},
+		}})
+	})
+}
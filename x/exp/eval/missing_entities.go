@@ -0,0 +1,52 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// WithMissingEntitiesAsEmpty makes Eval treat an entity that is referenced
+// during evaluation but absent from Env.Entities as an entity with no
+// attributes, no tags and no parents.
+//
+// This intentionally departs from the Cedar specification, under which
+// reading an attribute of a missing entity is an error. It is meant for
+// graceful degradation, for example when the entity store is eventually
+// consistent. With this option, accessing an attribute or tag of a missing
+// entity fails in the same way as for an entity that lacks the attribute or
+// tag, `has` and `hasTag` return false, and `in` is true only for the entity
+// itself. Because results may differ from those of a conforming Cedar
+// implementation, only use this option where that is acceptable.
+func WithMissingEntitiesAsEmpty() Option {
+	return func(c *evalConfig) {
+		c.env.Entities = missingAsEmpty{c.env.Entities}
+	}
+}
+
+// missingAsEmpty is an EntityGetter that synthesizes an empty entity for any
+// UID its underlying getter does not hold.
+type missingAsEmpty struct {
+	entities types.EntityGetter
+}
+
+func (m missingAsEmpty) Get(uid types.EntityUID) (types.Entity, bool) {
+	if m.entities != nil {
+		if e, ok := m.entities.Get(uid); ok {
+			return e, true
+		}
+	}
+	return types.Entity{UID: uid}, true
+}
@@ -0,0 +1,85 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestWithMissingEntitiesAsEmpty(t *testing.T) {
+	t.Parallel()
+	alice := types.NewEntityUID("User", "alice")
+	admins := types.NewEntityUID("Group", "admins")
+	entities := types.EntityMap{
+		alice: types.Entity{
+			UID:        alice,
+			Parents:    types.NewEntityUIDSet(admins),
+			Attributes: types.NewRecord(types.RecordMap{"name": types.String("Alice")}),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		in      ast.Node
+		out     types.Value
+		wantErr bool
+	}{
+		{"present attribute", ast.EntityUID("User", "alice").Access("name"), types.String("Alice"), false},
+		{"has on missing", ast.EntityUID("User", "ghost").Has("name"), types.False, false},
+		{"hasTag on missing", ast.EntityUID("User", "ghost").HasTag(ast.String("t")), types.False, false},
+		{"in on missing", ast.EntityUID("User", "ghost").In(ast.EntityUID("Group", "admins")), types.False, false},
+		{"in self on missing", ast.EntityUID("User", "ghost").In(ast.EntityUID("User", "ghost")), types.True, false},
+		{"in on present", ast.EntityUID("User", "alice").In(ast.EntityUID("Group", "admins")), types.True, false},
+		{"guarded access", ast.EntityUID("User", "ghost").Has("name").And(ast.EntityUID("User", "ghost").Access("name").Equal(ast.String("x"))), types.False, false},
+		{"attribute on missing", ast.EntityUID("User", "ghost").Access("name"), nil, true},
+		{"tag on missing", ast.EntityUID("User", "ghost").GetTag(ast.String("t")), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Eval(tt.in.AsIsNode(), Env{Entities: entities}, WithMissingEntitiesAsEmpty())
+			if tt.wantErr {
+				testutil.Error(t, err)
+				testutil.FatalIf(t, errorsContain(err, "does not exist"), "missing entity should behave as present but empty: %v", err)
+				return
+			}
+			testutil.OK(t, err)
+			testutil.Equals(t, got, tt.out)
+		})
+	}
+
+	t.Run("strict by default", func(t *testing.T) {
+		t.Parallel()
+		_, err := Eval(ast.EntityUID("User", "ghost").Access("name").AsIsNode(), Env{Entities: entities})
+		testutil.Error(t, err)
+		testutil.FatalIf(t, !errorsContain(err, "does not exist"), "unexpected error: %v", err)
+	})
+
+	t.Run("nil entities", func(t *testing.T) {
+		t.Parallel()
+		got, err := Eval(ast.EntityUID("User", "ghost").Has("name").AsIsNode(), Env{}, WithMissingEntitiesAsEmpty())
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.False))
+	})
+}
+
+func errorsContain(err error, substr string) bool {
+	return err != nil && strings.Contains(err.Error(), substr)
+}
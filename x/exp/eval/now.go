@@ -0,0 +1,72 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"maps"
+	"time"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// WithClock makes WithNowContext read the current time from clock instead of
+// time.Now, so that time-based policies can be tested against a fixed
+// instant. It applies wherever it appears among the options.
+func WithClock(clock func() time.Time) Option {
+	return func(c *evalConfig) {
+		c.clock = clock
+	}
+}
+
+// WithNowContext adds the current time, as a datetime, to the context under
+// key, so that policies can compare context[key] against other timestamps
+// without every request having to supply it. The time is read once, after
+// all options have been applied, from the clock set by WithClock or from
+// time.Now. A value for key that the context already holds is kept.
+//
+// The context is left unchanged if it is neither a record nor absent, for
+// example when it is a Variable during partial evaluation.
+func WithNowContext(key string) Option {
+	return func(c *evalConfig) {
+		c.nowKeys = append(c.nowKeys, key)
+	}
+}
+
+// injectNow adds the current time to the context under each key given to
+// WithNowContext.
+func (c *evalConfig) injectNow() {
+	if len(c.nowKeys) == 0 {
+		return
+	}
+	attrs := types.RecordMap{}
+	switch ctx := c.env.Context.(type) {
+	case nil:
+	case types.Record:
+		maps.Copy(attrs, ctx.Map())
+	default:
+		return
+	}
+	now := time.Now
+	if c.clock != nil {
+		now = c.clock
+	}
+	t := types.NewDatetime(now())
+	for _, key := range c.nowKeys {
+		if _, ok := attrs[types.String(key)]; !ok {
+			attrs[types.String(key)] = t
+		}
+	}
+	c.env.Context = types.NewRecord(attrs)
+}
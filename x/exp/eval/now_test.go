@@ -0,0 +1,93 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestWithNowContext(t *testing.T) {
+	t.Parallel()
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return fixed }
+	explicit := types.NewDatetime(fixed.Add(-time.Hour))
+
+	tests := []struct {
+		name    string
+		context types.Value
+		opts    []Option
+		want    types.Value
+	}{
+		{"nilContext", nil, []Option{WithClock(clock), WithNowContext("now")}, types.NewDatetime(fixed)},
+		{"emptyRecord", types.Record{}, []Option{WithClock(clock), WithNowContext("now")}, types.NewDatetime(fixed)},
+		{"explicitValueWins", types.NewRecord(types.RecordMap{"now": explicit}), []Option{WithClock(clock), WithNowContext("now")}, explicit},
+		{"clockAfterNowContext", nil, []Option{WithNowContext("now"), WithClock(clock)}, types.NewDatetime(fixed)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Eval(ast.Context().Access("now").AsIsNode(), Env{Context: tt.context}, tt.opts...)
+			testutil.OK(t, err)
+			testutil.Equals(t, got, tt.want)
+		})
+	}
+
+	t.Run("otherAttributesKept", func(t *testing.T) {
+		t.Parallel()
+		ctx := types.NewRecord(types.RecordMap{"ip": types.String("10.0.0.1")})
+		got, err := Eval(ast.Context().Access("ip").AsIsNode(), Env{Context: ctx}, WithClock(clock), WithNowContext("now"))
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.String("10.0.0.1")))
+	})
+
+	t.Run("comparesWithEntityTimestamp", func(t *testing.T) {
+		t.Parallel()
+		doc := types.NewEntityUID("Doc", "d")
+		entities := types.EntityMap{doc: types.Entity{
+			UID:        doc,
+			Attributes: types.NewRecord(types.RecordMap{"expires": types.NewDatetime(fixed.Add(time.Minute))}),
+		}}
+		expr := ast.Context().Access("now").LessThan(ast.EntityUID("Doc", "d").Access("expires"))
+		got, err := Eval(expr.AsIsNode(), Env{Entities: entities}, WithClock(clock), WithNowContext("now"))
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.True))
+
+		later := func() time.Time { return fixed.Add(time.Hour) }
+		got, err = Eval(expr.AsIsNode(), Env{Entities: entities}, WithClock(later), WithNowContext("now"))
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.Value(types.False))
+	})
+
+	t.Run("defaultsToTimeNow", func(t *testing.T) {
+		t.Parallel()
+		before := types.NewDatetime(time.Now())
+		got, err := Eval(ast.Context().Access("now").AsIsNode(), Env{}, WithNowContext("now"))
+		testutil.OK(t, err)
+		now, ok := got.(types.Datetime)
+		testutil.Equals(t, ok, true)
+		testutil.Equals(t, now.Compare(before) >= 0, true)
+	})
+
+	t.Run("nonRecordContextUnchanged", func(t *testing.T) {
+		t.Parallel()
+		cfg := newEvalConfig(Env{Context: Variable("context")}, []Option{WithNowContext("now")})
+		testutil.Equals(t, cfg.env.Context, Variable("context"))
+	})
+}
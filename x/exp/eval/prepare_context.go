@@ -0,0 +1,127 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+	"github.com/cedar-policy/cedar-go/x/exp/validator"
+)
+
+// ErrInvalidContext is wrapped by each of the problems that PrepareContext
+// reports.
+var ErrInvalidContext = errors.New("invalid context")
+
+// PrepareContext checks raw, an untrusted context, against the context type
+// that s declares for action and returns it normalized for authorization:
+//
+//   - Strings given for attributes of an extension type are parsed as that
+//     type, so "10.0.0.1" becomes an ipaddr where the schema declares one.
+//   - Optional attributes that raw leaves out and that the schema annotates
+//     with @default, as described by [schema.DefaultAnnotation], take the
+//     annotation's value. Other optional attributes stay absent, since
+//     policies can tell absence apart with `has`.
+//
+// These rules apply to nested records and sets too. The context is checked
+// as by [validator.CheckValue]: it is an error for an attribute to have the
+// wrong type, for a required attribute to be missing and for a closed record
+// to have undeclared attributes. PrepareContext reports every problem at
+// once, each wrapping ErrInvalidContext, so that the caller can reject the
+// request with a complete explanation.
+func PrepareContext(s *schema.Schema, action types.EntityUID, raw types.Record) (types.Record, error) {
+	info, ok := s.ActionInfo(action)
+	if !ok {
+		return types.Record{}, fmt.Errorf("%w: action %s is not declared", ErrInvalidContext, action)
+	}
+	checker := validator.ValueChecker{Convert: convertExtension, Default: defaultValue}
+	ctx, problems := checker.Check("context", raw, info.Context)
+	if len(problems) > 0 {
+		errs := make([]error, len(problems))
+		for i, p := range problems {
+			errs[i] = fmt.Errorf("%w: %w", ErrInvalidContext, p)
+		}
+		return types.Record{}, errors.Join(errs...)
+	}
+	return ctx.(types.Record), nil
+}
+
+// convertExtension parses val as a value of t if t is an extension type and
+// val is a string.
+func convertExtension(val types.Value, t schema.CedarType) (types.Value, error) {
+	ext, ok := t.(schema.ExtensionType)
+	if !ok {
+		return val, nil
+	}
+	if s, ok := val.(types.String); ok {
+		return parseExtension(ext.Name, string(s))
+	}
+	return val, nil
+}
+
+// defaultValue returns the value that the @default annotation of attr gives,
+// or false if it has none.
+func defaultValue(attr schema.AttributeType) (types.Value, bool, error) {
+	text, ok := attr.Annotations[schema.DefaultAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+	v, err := parseDefault(text, attr.Type)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid @%s: %w", schema.DefaultAnnotation, err)
+	}
+	return v, true, nil
+}
+
+// parseDefault parses the text of a @default annotation as a value of the
+// type t, which must be a Bool, Long, String or extension type.
+func parseDefault(text string, t schema.CedarType) (types.Value, error) {
+	switch t := t.(type) {
+	case schema.BoolType:
+		switch text {
+		case "true":
+			return types.True, nil
+		case "false":
+			return types.False, nil
+		}
+		return nil, fmt.Errorf("%q is not a Bool", text)
+	case schema.LongType:
+		n, err := strconv.ParseInt(text, 10, 64)
+		return types.Long(n), err
+	case schema.StringType:
+		return types.String(text), nil
+	case schema.ExtensionType:
+		return parseExtension(t.Name, text)
+	}
+	return nil, fmt.Errorf("defaults are not supported for type %s", t)
+}
+
+// parseExtension parses s as a value of the extension type name.
+func parseExtension(name, s string) (types.Value, error) {
+	switch name {
+	case "decimal":
+		return types.ParseDecimal(s)
+	case "ipaddr":
+		return types.ParseIPAddr(s)
+	case "datetime":
+		return types.ParseDatetime(s)
+	case "duration":
+		return types.ParseDuration(s)
+	}
+	return nil, fmt.Errorf("unknown extension type %s", name)
+}
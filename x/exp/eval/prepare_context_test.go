@@ -0,0 +1,140 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestPrepareContext(t *testing.T) {
+	t.Parallel()
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		action view appliesTo {
+			principal: User,
+			resource: Document,
+			context: {
+				clientIp: ipaddr,
+				@default("false") trusted?: Bool,
+				@default("3") retries?: Long,
+				@default("10.0.0.0/8") network?: ipaddr,
+				reason?: String,
+				ranges?: Set<ipaddr>,
+				window?: { start: datetime, @default("1h") ttl?: duration },
+			},
+		};
+		action open appliesTo { principal: User, resource: Document };
+		action broken appliesTo {
+			principal: User,
+			resource: Document,
+			context: { @default("yes") flag?: Bool, @default("x") tags?: Set<String> },
+		};
+	`))
+	testutil.OK(t, err)
+	view := types.NewEntityUID("Action", "view")
+	mustIP := func(s string) types.IPAddr {
+		ip, err := types.ParseIPAddr(s)
+		testutil.OK(t, err)
+		return ip
+	}
+
+	t.Run("normalizes", func(t *testing.T) {
+		t.Parallel()
+		start, err := types.ParseDatetime("2026-01-01T00:00:00Z")
+		testutil.OK(t, err)
+		hour, err := types.ParseDuration("1h")
+		testutil.OK(t, err)
+		raw := types.NewRecord(types.RecordMap{
+			"clientIp": types.String("10.1.2.3"),
+			"retries":  types.Long(1),
+			"ranges":   types.NewSet(types.String("192.168.0.0/16"), mustIP("10.0.0.0/8")),
+			"window":   types.NewRecord(types.RecordMap{"start": types.String("2026-01-01T00:00:00Z")}),
+		})
+		got, err := PrepareContext(s, view, raw)
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.NewRecord(types.RecordMap{
+			"clientIp": mustIP("10.1.2.3"),
+			"trusted":  types.False,
+			"retries":  types.Long(1),
+			"network":  mustIP("10.0.0.0/8"),
+			"ranges":   types.NewSet(mustIP("192.168.0.0/16"), mustIP("10.0.0.0/8")),
+			"window":   types.NewRecord(types.RecordMap{"start": start, "ttl": hour}),
+		}))
+	})
+
+	t.Run("extensionValuesKept", func(t *testing.T) {
+		t.Parallel()
+		raw := types.NewRecord(types.RecordMap{"clientIp": mustIP("::1"), "trusted": types.True})
+		got, err := PrepareContext(s, view, raw)
+		testutil.OK(t, err)
+		v, _ := got.Get("clientIp")
+		testutil.Equals(t, v, types.Value(mustIP("::1")))
+		v, _ = got.Get("trusted")
+		testutil.Equals(t, v, types.Value(types.True))
+	})
+
+	t.Run("reportsAllProblems", func(t *testing.T) {
+		t.Parallel()
+		raw := types.NewRecord(types.RecordMap{
+			"retries": types.String("3"),
+			"ranges":  types.NewSet(types.String("not an ip")),
+			"window":  types.NewRecord(types.RecordMap{"start": types.Long(0)}),
+			"extra":   types.True,
+		})
+		_, err := PrepareContext(s, view, raw)
+		testutil.ErrorIs(t, err, ErrInvalidContext)
+		for _, want := range []string{
+			"context.clientIp: required attribute is missing",
+			"context.retries: expected Long, got string",
+			"context.ranges.element: ",
+			"context.window.start: expected datetime, got long",
+			"context.extra: attribute is not declared",
+		} {
+			testutil.FatalIf(t, !strings.Contains(err.Error(), want), "error %q should contain %q", err, want)
+		}
+	})
+
+	t.Run("emptyContext", func(t *testing.T) {
+		t.Parallel()
+		got, err := PrepareContext(s, types.NewEntityUID("Action", "open"), types.Record{})
+		testutil.OK(t, err)
+		testutil.Equals(t, got, types.NewRecord(types.RecordMap{}))
+
+		_, err = PrepareContext(s, types.NewEntityUID("Action", "open"), types.NewRecord(types.RecordMap{"x": types.True}))
+		testutil.ErrorIs(t, err, ErrInvalidContext)
+	})
+
+	t.Run("invalidDefaults", func(t *testing.T) {
+		t.Parallel()
+		_, err := PrepareContext(s, types.NewEntityUID("Action", "broken"), types.Record{})
+		testutil.ErrorIs(t, err, ErrInvalidContext)
+		testutil.FatalIf(t, !strings.Contains(err.Error(), `context.flag: invalid @default: "yes" is not a Bool`), "unexpected error %q", err)
+		testutil.FatalIf(t, !strings.Contains(err.Error(), "context.tags: invalid @default"), "unexpected error %q", err)
+	})
+
+	t.Run("undeclaredAction", func(t *testing.T) {
+		t.Parallel()
+		_, err := PrepareContext(s, types.NewEntityUID("Action", "edit"), types.Record{})
+		testutil.ErrorIs(t, err, ErrInvalidContext)
+		testutil.FatalIf(t, errors.Unwrap(err) == nil, "error should wrap ErrInvalidContext")
+	})
+}
@@ -15,6 +15,8 @@
 package eval
 
 import (
+	"slices"
+
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/ast"
 )
@@ -45,6 +47,17 @@ type QueryResult struct {
 	// Constraints contains residual constraints that couldn't be fully resolved.
 	// These describe conditions that must be met for additional values to satisfy.
 	Constraints []QueryConstraint
+
+	// ExcludedBy explains values missing from SatisfyingValues because of
+	// forbid policies. It maps each value that a permit's scope names, but
+	// to which a forbid applies, to the IDs of the applying forbids in
+	// order. As forbids override permits, such values are never in
+	// SatisfyingValues. It is nil if no value was excluded.
+	ExcludedBy map[types.EntityUID][]types.PolicyID
+
+	// Err is set when WithSchemaValidation rejected the request, in which
+	// case the decision is Deny and no policy was evaluated.
+	Err error
 }
 
 // QueryConstraint represents a constraint extracted from residual policies.
@@ -108,17 +121,24 @@ func QueryPrincipals(
 	action types.EntityUID,
 	resource types.EntityUID,
 	context types.Record,
+	opts ...DecisionOption,
 ) *QueryResult {
-	env := Env{
+	cfg := newDecisionConfig(opts)
+	env := cfg.evalEnv(Env{
 		Principal: Variable("principal"),
 		Action:    action,
 		Resource:  resource,
 		Context:   context,
 		Entities:  entities,
-	}
+	})
 
+	if err := cfg.checkRequest(env); err != nil {
+		return &QueryResult{Decision: types.Deny, Definite: true, Err: err}
+	}
 	residuals := PartialPolicySet(env, policies)
-	return analyzeQueryResult(residuals, "principal")
+	result := analyzeQueryResult(residuals, "principal")
+	excludeForbidden(result, policies, residuals, env, "principal")
+	return result
 }
 
 // QueryResources finds which resources the given principal can access
@@ -139,17 +159,24 @@ func QueryResources(
 	principal types.EntityUID,
 	action types.EntityUID,
 	context types.Record,
+	opts ...DecisionOption,
 ) *QueryResult {
-	env := Env{
+	cfg := newDecisionConfig(opts)
+	env := cfg.evalEnv(Env{
 		Principal: principal,
 		Action:    action,
 		Resource:  Variable("resource"),
 		Context:   context,
 		Entities:  entities,
-	}
+	})
 
+	if err := cfg.checkRequest(env); err != nil {
+		return &QueryResult{Decision: types.Deny, Definite: true, Err: err}
+	}
 	residuals := PartialPolicySet(env, policies)
-	return analyzeQueryResult(residuals, "resource")
+	result := analyzeQueryResult(residuals, "resource")
+	excludeForbidden(result, policies, residuals, env, "resource")
+	return result
 }
 
 // QueryActions finds which actions the given principal can perform
@@ -170,17 +197,24 @@ func QueryActions(
 	principal types.EntityUID,
 	resource types.EntityUID,
 	context types.Record,
+	opts ...DecisionOption,
 ) *QueryResult {
-	env := Env{
+	cfg := newDecisionConfig(opts)
+	env := cfg.evalEnv(Env{
 		Principal: principal,
 		Action:    Variable("action"),
 		Resource:  resource,
 		Context:   context,
 		Entities:  entities,
-	}
+	})
 
+	if err := cfg.checkRequest(env); err != nil {
+		return &QueryResult{Decision: types.Deny, Definite: true, Err: err}
+	}
 	residuals := PartialPolicySet(env, policies)
-	return analyzeQueryResult(residuals, "action")
+	result := analyzeQueryResult(residuals, "action")
+	excludeForbidden(result, policies, residuals, env, "action")
+	return result
 }
 
 // analyzeQueryResult analyzes residual policies to determine query results.
@@ -276,6 +310,71 @@ func buildFinalResult(result *QueryResult, satisfying map[types.EntityUID]bool,
 	}
 }
 
+// excludeForbidden removes from result the values that a permit's scope names
+// for varName but that a forbid applies to, and records the applying forbids
+// in result.ExcludedBy. Whether a forbid applies to a value is decided by
+// evaluating it with the value in place of the variable; forbids that fail to
+// evaluate do not apply. If no value is left, the decision becomes Deny.
+func excludeForbidden(result *QueryResult, policies map[types.PolicyID]*ast.Policy, residuals *ResidualSet, env Env, varName string) {
+	candidates := map[types.EntityUID]bool{}
+	for _, p := range residuals.Permits {
+		if p.Kind == ResidualVariable {
+			for _, v := range extractScopeValues(p.Policy, varName) {
+				candidates[v] = true
+			}
+		}
+	}
+	for v := range candidates {
+		forbids := applyingForbids(policies, residuals, withVariable(env, varName, v))
+		if len(forbids) == 0 {
+			continue
+		}
+		if result.ExcludedBy == nil {
+			result.ExcludedBy = map[types.EntityUID][]types.PolicyID{}
+		}
+		result.ExcludedBy[v] = forbids
+		result.SatisfyingValues = slices.DeleteFunc(result.SatisfyingValues, func(s types.EntityUID) bool {
+			return s == v
+		})
+	}
+	if len(result.SatisfyingValues) == 0 {
+		result.SatisfyingValues = nil
+		if !result.All {
+			result.Decision = types.Deny
+		}
+	}
+}
+
+// applyingForbids returns, in order, the IDs of the forbids that may apply
+// according to residuals and that evaluate to true in the concrete env.
+func applyingForbids(policies map[types.PolicyID]*ast.Policy, residuals *ResidualSet, env Env) []types.PolicyID {
+	var ids []types.PolicyID
+	for _, f := range residuals.Forbids {
+		if f.Kind != ResidualTrue && f.Kind != ResidualVariable {
+			continue
+		}
+		v, err := Eval(PolicyToNode(policies[f.PolicyID]).AsIsNode(), env)
+		if err == nil && v == types.True {
+			ids = append(ids, f.PolicyID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// withVariable returns env with the request variable varName set to v.
+func withVariable(env Env, varName string, v types.EntityUID) Env {
+	switch varName {
+	case "principal":
+		env.Principal = v
+	case "action":
+		env.Action = v
+	case "resource":
+		env.Resource = v
+	}
+	return env
+}
+
 // extractPolicyConstraints extracts constraints from a policy for a variable.
 func extractPolicyConstraints(p *ast.Policy, varName string) []QueryConstraint {
 	if p == nil {
@@ -388,7 +487,10 @@ func extractScopeValues(p *ast.Policy, varName string) []types.EntityUID {
 // contributed to the decision.
 //
 // This is similar to a standard IsAuthorized but uses partial evaluation
-// to provide more detailed analysis of the decision path.
+// to provide more detailed analysis of the decision path. Matching policies
+// are combined with DenyOverrides unless WithCombiningAlgorithm selects
+// another algorithm. WithSchemaValidation checks the request against a schema
+// first.
 func QueryDecision(
 	policies map[types.PolicyID]*ast.Policy,
 	entities types.EntityMap,
@@ -396,16 +498,25 @@ func QueryDecision(
 	action types.EntityUID,
 	resource types.EntityUID,
 	context types.Record,
+	opts ...DecisionOption,
 ) *QueryDecisionResult {
-	env := Env{
+	cfg := newDecisionConfig(opts)
+	env := cfg.evalEnv(Env{
 		Principal: principal,
 		Action:    action,
 		Resource:  resource,
 		Context:   context,
 		Entities:  entities,
+	})
+
+	if err := cfg.checkRequest(env); err != nil {
+		return &QueryDecisionResult{Decision: types.Deny, Err: err}
 	}
 
 	residuals := PartialPolicySet(env, policies)
+	if cfg.algorithm == PermitOverrides {
+		return permitOverrides(residuals)
+	}
 
 	result := &QueryDecisionResult{
 		Decision: types.Deny,
@@ -447,4 +558,8 @@ type QueryDecisionResult struct {
 
 	// ErroringPolicies lists policies that encountered errors during evaluation.
 	ErroringPolicies []types.PolicyID
+
+	// Err is set when WithSchemaValidation rejected the request, in which
+	// case the decision is Deny and no policy was evaluated.
+	Err error
 }
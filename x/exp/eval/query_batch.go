@@ -0,0 +1,55 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// QueryDecisionBatch runs QueryDecision, with the same options, for each
+// request in order. results[i] is the result for requests[i].
+//
+// If ctx is canceled or its deadline passes, QueryDecisionBatch stops before
+// the next request and returns the results computed so far together with
+// ctx.Err(). completed is the number of requests that were evaluated: the
+// first completed results are set, and the rest are nil. A request is never
+// interrupted, so every result that is set is exact. This lets a service
+// return best-effort answers under load:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+//	defer cancel()
+//	results, completed, err := eval.QueryDecisionBatch(ctx, policies, entities, requests)
+//	if err != nil {
+//	    // requests[completed:] were not evaluated
+//	}
+func QueryDecisionBatch(
+	ctx context.Context,
+	policies map[types.PolicyID]*ast.Policy,
+	entities types.EntityMap,
+	requests []types.Request,
+	opts ...DecisionOption,
+) (results []*QueryDecisionResult, completed int, err error) {
+	results = make([]*QueryDecisionResult, len(requests))
+	for i, req := range requests {
+		if err := ctx.Err(); err != nil {
+			return results, i, err
+		}
+		results[i] = QueryDecision(policies, entities, req.Principal, req.Action, req.Resource, req.Context, opts...)
+	}
+	return results, len(requests), nil
+}
@@ -0,0 +1,100 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// expiringContext reports DeadlineExceeded once Err has been called more than
+// checks times, so that tests can expire a batch at a known request.
+type expiringContext struct {
+	context.Context
+	checks int
+}
+
+func (c *expiringContext) Err() error {
+	if c.checks == 0 {
+		return context.DeadlineExceeded
+	}
+	c.checks--
+	return nil
+}
+
+func TestQueryDecisionBatch(t *testing.T) {
+	t.Parallel()
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	view := types.NewEntityUID("Action", "view")
+	doc := types.NewEntityUID("Document", "readme")
+	policies := map[types.PolicyID]*ast.Policy{
+		"alice": ast.Permit().PrincipalEq(alice),
+	}
+	requests := []types.Request{
+		{Principal: alice, Action: view, Resource: doc},
+		{Principal: bob, Action: view, Resource: doc},
+		{Principal: alice, Action: view, Resource: doc},
+	}
+
+	t.Run("complete", func(t *testing.T) {
+		t.Parallel()
+		results, completed, err := QueryDecisionBatch(context.Background(), policies, nil, requests)
+		testutil.OK(t, err)
+		testutil.Equals(t, completed, 3)
+		testutil.Equals(t, len(results), 3)
+		testutil.Equals(t, results[0].Decision, types.Allow)
+		testutil.Equals(t, results[0].DeterminingPolicies, []types.PolicyID{"alice"})
+		testutil.Equals(t, results[1].Decision, types.Deny)
+		testutil.Equals(t, results[2].Decision, types.Allow)
+	})
+
+	t.Run("deadlineReturnsPartialResults", func(t *testing.T) {
+		t.Parallel()
+		ctx := &expiringContext{Context: context.Background(), checks: 2}
+		results, completed, err := QueryDecisionBatch(ctx, policies, nil, requests)
+		testutil.ErrorIs(t, err, context.DeadlineExceeded)
+		testutil.Equals(t, completed, 2)
+		testutil.Equals(t, len(results), 3)
+		testutil.Equals(t, results[0].Decision, types.Allow)
+		testutil.Equals(t, results[1].Decision, types.Deny)
+		testutil.Equals(t, results[2], (*QueryDecisionResult)(nil))
+	})
+
+	t.Run("alreadyCanceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, completed, err := QueryDecisionBatch(ctx, policies, nil, requests)
+		testutil.ErrorIs(t, err, context.Canceled)
+		testutil.Equals(t, completed, 0)
+		testutil.Equals(t, results, make([]*QueryDecisionResult, 3))
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+		forbid := map[types.PolicyID]*ast.Policy{
+			"alice":  ast.Permit().PrincipalEq(alice),
+			"forbid": ast.Forbid(),
+		}
+		results, _, err := QueryDecisionBatch(context.Background(), forbid, nil, requests[:1], WithCombiningAlgorithm(PermitOverrides))
+		testutil.OK(t, err)
+		testutil.Equals(t, results[0].Decision, types.Allow)
+	})
+}
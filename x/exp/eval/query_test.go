@@ -18,6 +18,7 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/cedar-policy/cedar-go/internal/testutil"
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/ast"
 )
@@ -982,3 +983,89 @@ func TestQueryDecisionAuditLog(t *testing.T) {
 		t.Error("Expected at least one determining policy")
 	}
 }
+
+func TestQueryEmptyPolicySet(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	read := types.NewEntityUID("Action", "read")
+	doc := types.NewEntityUID("Document", "doc1")
+
+	for name, policies := range map[string]map[types.PolicyID]*ast.Policy{
+		"nil":   nil,
+		"empty": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			want := &QueryResult{Decision: types.Deny, Definite: true}
+			testutil.Equals(t, QueryPrincipals(policies, nil, read, doc, types.Record{}), want)
+			testutil.Equals(t, QueryResources(policies, nil, alice, read, types.Record{}), want)
+			testutil.Equals(t, QueryActions(policies, nil, alice, doc, types.Record{}), want)
+
+			decision := QueryDecision(policies, nil, alice, read, doc, types.Record{})
+			testutil.Equals(t, decision, &QueryDecisionResult{Decision: types.Deny})
+		})
+	}
+}
+
+func TestQueryExcludedBy(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	read := types.NewEntityUID("Action", "read")
+	report := types.NewEntityUID("Document", "report.pdf")
+	readme := types.NewEntityUID("Document", "readme.txt")
+	confidential := types.NewEntityUID("Folder", "confidential")
+	entities := types.EntityMap{
+		report:       {UID: report, Parents: types.NewEntityUIDSet(confidential)},
+		confidential: {UID: confidential},
+	}
+
+	t.Run("resources", func(t *testing.T) {
+		policies := map[types.PolicyID]*ast.Policy{
+			"read-report":         ast.Permit().PrincipalEq(alice).ResourceEq(report),
+			"read-readme":         ast.Permit().PrincipalEq(alice).ResourceEq(readme),
+			"forbid-confidential": ast.Forbid().ResourceIn(confidential),
+			"forbid-pdf":          ast.Forbid().When(ast.Resource().Equal(ast.Value(report))),
+			"forbid-bob":          ast.Forbid().PrincipalEq(types.NewEntityUID("User", "bob")),
+		}
+		result := QueryResources(policies, entities, alice, read, types.Record{})
+		testutil.Equals(t, result.Decision, types.Allow)
+		testutil.Equals(t, result.SatisfyingValues, []types.EntityUID{readme})
+		testutil.Equals(t, result.ExcludedBy, map[types.EntityUID][]types.PolicyID{
+			report: {"forbid-confidential", "forbid-pdf"},
+		})
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		view := types.NewEntityUID("Action", "view")
+		del := types.NewEntityUID("Action", "delete")
+		policies := map[types.PolicyID]*ast.Policy{
+			"editors":                ast.Permit().ActionInSet(view, del),
+			"no-delete-confidential": ast.Forbid().ActionEq(del).ResourceIn(confidential),
+		}
+		result := QueryActions(policies, entities, alice, report, types.Record{})
+		testutil.Equals(t, result.SatisfyingValues, []types.EntityUID{view})
+		testutil.Equals(t, result.ExcludedBy, map[types.EntityUID][]types.PolicyID{
+			del: {"no-delete-confidential"},
+		})
+	})
+
+	t.Run("allExcluded", func(t *testing.T) {
+		policies := map[types.PolicyID]*ast.Policy{
+			"read-report": ast.Permit().ResourceEq(report),
+			"forbid-all":  ast.Forbid(),
+		}
+		result := QueryResources(policies, entities, alice, read, types.Record{})
+		testutil.Equals(t, result.Decision, types.Deny)
+		testutil.Equals(t, len(result.SatisfyingValues), 0)
+		testutil.Equals(t, result.ExcludedBy, map[types.EntityUID][]types.PolicyID{
+			report: {"forbid-all"},
+		})
+	})
+
+	t.Run("erroringForbidDoesNotExclude", func(t *testing.T) {
+		policies := map[types.PolicyID]*ast.Policy{
+			"read-readme":   ast.Permit().ResourceEq(readme),
+			"forbid-secret": ast.Forbid().When(ast.Resource().Access("secret")),
+		}
+		result := QueryResources(policies, entities, alice, read, types.Record{})
+		testutil.Equals(t, result.SatisfyingValues, []types.EntityUID{readme})
+		testutil.Equals(t, result.ExcludedBy, nil)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// CollectReadValues evaluates the attribute reads performed by the policies
+// whose scope applies to request, and returns the resolved values keyed by
+// their Cedar path, e.g. "principal.department". It is meant for recording
+// the inputs of an authorization decision, for example in an audit log.
+//
+// Only the deepest access of each chain is reported, so reading
+// `principal.manager.department` yields that path rather than
+// `principal.manager`. Reads that fail to evaluate, such as an access of a
+// missing attribute, are reported in the error map instead of the value map;
+// they do not stop the collection of other reads. Each read is evaluated on
+// its own, so a read guarded by `has` is reported as an error when the
+// attribute is absent.
+func CollectReadValues(
+	policies map[types.PolicyID]*ast.Policy,
+	entities types.EntityMap,
+	request types.Request,
+) (map[string]types.Value, map[string]error) {
+	env := Env{
+		Principal: request.Principal,
+		Action:    request.Action,
+		Resource:  request.Resource,
+		Context:   request.Context,
+		Entities:  entities,
+	}
+	values := make(map[string]types.Value)
+	errs := make(map[string]error)
+	for _, p := range policies {
+		if !scopeApplies(env, p) {
+			continue
+		}
+		for _, cond := range p.Conditions {
+			collectReadValues(env, cond.Body, values, errs)
+		}
+	}
+	return values, errs
+}
+
+// scopeApplies reports whether the principal, action, and resource scope of p
+// are satisfied in env.
+func scopeApplies(env Env, p *ast.Policy) bool {
+	scope := *p
+	scope.Conditions = nil
+	v, err := Eval(PolicyToNode(&scope).AsIsNode(), env)
+	return err == nil && v == types.True
+}
+
+// collectReadValues evaluates the attribute access chains within n.
+func collectReadValues(env Env, n ast.IsNode, values map[string]types.Value, errs map[string]error) {
+	if n == nil {
+		return
+	}
+	if path, ok := attributePath(n); ok {
+		if _, seen := values[path]; seen {
+			return
+		}
+		if _, seen := errs[path]; seen {
+			return
+		}
+		if v, err := Eval(n, env); err != nil {
+			errs[path] = err
+		} else {
+			values[path] = v
+		}
+		return
+	}
+	for _, child := range getNodeChildren(n) {
+		collectReadValues(env, child, values, errs)
+	}
+}
+
+// attributePath returns the Cedar path of an attribute access chain rooted at
+// a variable, e.g. "principal.manager.department".
+func attributePath(n ast.IsNode) (string, bool) {
+	if _, ok := n.(ast.NodeTypeAccess); !ok {
+		return "", false
+	}
+	p, ok := ast.AttributePathOf(n)
+	return p.String(), ok
+}
@@ -0,0 +1,80 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+func TestCollectReadValues(t *testing.T) {
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	eng := types.NewEntityUID("Department", "eng")
+	view := types.NewEntityUID("Action", "view")
+	edit := types.NewEntityUID("Action", "edit")
+	doc := types.NewEntityUID("Document", "readme")
+
+	entities := types.EntityMap{
+		alice: {UID: alice, Attributes: types.NewRecord(types.RecordMap{
+			"department": eng,
+			"manager":    bob,
+		})},
+		bob: {UID: bob, Attributes: types.NewRecord(types.RecordMap{
+			"department": eng,
+		})},
+		doc: {UID: doc, Attributes: types.NewRecord(types.RecordMap{
+			"owner": alice,
+		})},
+	}
+
+	policies := map[types.PolicyID]*ast.Policy{
+		"dept": ast.Permit().ActionEq(view).When(
+			ast.Principal().Access("department").Equal(ast.Value(eng)),
+		),
+		"manager": ast.Permit().When(
+			ast.Principal().Access("manager").Access("department").Equal(ast.Resource().Access("owner").Access("department")),
+		),
+		"missing": ast.Forbid().When(
+			ast.Principal().Access("suspended").And(ast.Context().Access("mfa")),
+		),
+		"guarded": ast.Permit().When(
+			ast.Context().Has("ip").And(ast.Context().Access("ip").Equal(ast.String("10.0.0.1"))),
+		),
+		"inapplicable": ast.Permit().ActionEq(edit).When(
+			ast.Resource().Access("classification").Equal(ast.String("public")),
+		),
+	}
+
+	values, errs := CollectReadValues(policies, entities, types.Request{
+		Principal: alice,
+		Action:    view,
+		Resource:  doc,
+		Context:   types.NewRecord(types.RecordMap{"mfa": types.True}),
+	})
+	testutil.Equals(t, values, map[string]types.Value{
+		"principal.department":         eng,
+		"principal.manager.department": eng,
+		"resource.owner.department":    eng,
+		"context.mfa":                  types.True,
+	})
+
+	testutil.Equals(t, len(errs), 2)
+	testutil.Error(t, errs["principal.suspended"])
+	testutil.Error(t, errs["context.ip"])
+}
@@ -95,6 +95,7 @@ type ResidualSet struct {
 // Policies that evaluate to false are marked as ResidualFalse and excluded
 // from further consideration. Policies with unresolved variables are marked
 // as ResidualVariable. Policies that encounter errors are marked as ResidualError.
+// The options configure evaluation as they do for Eval.
 //
 // Example:
 //
@@ -114,7 +115,8 @@ type ResidualSet struct {
 //	        // Policy needs more information
 //	    }
 //	}
-func PartialPolicySet(env Env, policies map[types.PolicyID]*ast.Policy) *ResidualSet {
+func PartialPolicySet(env Env, policies map[types.PolicyID]*ast.Policy, opts ...Option) *ResidualSet {
+	env = newEvalConfig(env, opts).env
 	result := &ResidualSet{}
 
 	for id, policy := range policies {
@@ -0,0 +1,91 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+	"github.com/cedar-policy/cedar-go/x/exp/validator"
+)
+
+// ErrRequestMismatch is wrapped by the error reported when WithSchemaValidation
+// rejects a request.
+var ErrRequestMismatch = errors.New("request does not match schema")
+
+// WithSchemaValidation makes the query functions check the concrete parts of
+// the request against s before evaluating any policy. The action must be
+// declared, the principal and resource types must be allowed for it, and the
+// context must match its context type. When the action is being queried, some
+// declared action must accept the request. A rejected request yields a Deny
+// result whose Err wraps ErrRequestMismatch.
+func WithSchemaValidation(s *schema.Schema) DecisionOption {
+	return func(c *decisionConfig) {
+		c.schema = s
+	}
+}
+
+// checkRequest validates the concrete parts of env against the schema given
+// by WithSchemaValidation, if any.
+func (c decisionConfig) checkRequest(env Env) error {
+	if c.schema == nil {
+		return nil
+	}
+	s := c.schema
+	if action, ok := concreteUID(env.Action); ok {
+		info, ok := s.ActionInfo(action)
+		if !ok {
+			return fmt.Errorf("%w: action %s is not declared", ErrRequestMismatch, action)
+		}
+		if err := checkAction(action, info, env); err != nil {
+			return fmt.Errorf("%w: %w", ErrRequestMismatch, err)
+		}
+		return nil
+	}
+	for action := range s.Actions() {
+		info, _ := s.ActionInfo(action)
+		if checkAction(action, info, env) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no action accepts the principal, resource, and context", ErrRequestMismatch)
+}
+
+// checkAction validates the concrete principal, resource, and context of env
+// against the declaration of action.
+func checkAction(action types.EntityUID, info *schema.ActionTypeInfo, env Env) error {
+	if p, ok := concreteUID(env.Principal); ok && !slices.Contains(info.PrincipalTypes, p.Type) {
+		return fmt.Errorf("principal type %s is not allowed for action %s", p.Type, action)
+	}
+	if r, ok := concreteUID(env.Resource); ok && !slices.Contains(info.ResourceTypes, r.Type) {
+		return fmt.Errorf("resource type %s is not allowed for action %s", r.Type, action)
+	}
+	if errs := validator.CheckValue("context", env.Context, info.Context); len(errs) > 0 {
+		return fmt.Errorf("action %s: %w", action, errs[0])
+	}
+	return nil
+}
+
+// concreteUID returns v as an entity UID unless it is a Variable.
+func concreteUID(v types.Value) (types.EntityUID, bool) {
+	if _, ok := ToVariable(v); ok {
+		return types.EntityUID{}, false
+	}
+	uid, ok := v.(types.EntityUID)
+	return uid, ok
+}
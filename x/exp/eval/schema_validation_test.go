@@ -0,0 +1,119 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestWithSchemaValidation(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		entity Group;
+		action view appliesTo {
+			principal: User,
+			resource: Document,
+			context: { mfa: Bool, ip?: ipaddr, tags?: Set<String>, owner?: User }
+		};
+		action join appliesTo { principal: User, resource: Group };
+	`))
+	testutil.OK(t, err)
+
+	alice := types.NewEntityUID("User", "alice")
+	doc := types.NewEntityUID("Document", "readme")
+	group := types.NewEntityUID("Group", "admins")
+	view := types.NewEntityUID("Action", "view")
+	policies := map[types.PolicyID]*ast.Policy{
+		"p": ast.Permit(),
+	}
+	validContext := types.NewRecord(types.RecordMap{
+		"mfa":   types.True,
+		"ip":    types.IPAddr{},
+		"tags":  types.NewSet(types.String("a")),
+		"owner": alice,
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		result := QueryDecision(policies, nil, alice, view, doc, validContext, WithSchemaValidation(s))
+		testutil.OK(t, result.Err)
+		testutil.Equals(t, result.Decision, types.Allow)
+	})
+
+	t.Run("defaultOff", func(t *testing.T) {
+		result := QueryDecision(policies, nil, alice, view, group, types.Record{})
+		testutil.OK(t, result.Err)
+		testutil.Equals(t, result.Decision, types.Allow)
+	})
+
+	tests := []struct {
+		name      string
+		principal types.EntityUID
+		action    types.EntityUID
+		resource  types.EntityUID
+		context   types.Record
+		errSubstr string
+	}{
+		{"undeclaredAction", alice, types.NewEntityUID("Action", "edit"), doc, validContext, "action Action::\"edit\" is not declared"},
+		{"principalType", doc, view, doc, validContext, "principal type Document is not allowed"},
+		{"resourceType", alice, view, group, validContext, "resource type Group is not allowed"},
+		{"missingAttribute", alice, view, doc, types.Record{}, "context.mfa: required attribute is missing"},
+		{"extraAttribute", alice, view, doc, types.NewRecord(types.RecordMap{"mfa": types.True, "extra": types.Long(1)}), "context.extra: attribute is not declared"},
+		{"wrongType", alice, view, doc, types.NewRecord(types.RecordMap{"mfa": types.String("yes")}), "context.mfa: expected Bool, got string"},
+		{"wrongExtension", alice, view, doc, types.NewRecord(types.RecordMap{"mfa": types.True, "ip": types.Long(1)}), "context.ip: expected ipaddr"},
+		{"wrongSetElement", alice, view, doc, types.NewRecord(types.RecordMap{"mfa": types.True, "tags": types.NewSet(types.Long(1))}), "context.tags.element: expected String"},
+		{"wrongEntityType", alice, view, doc, types.NewRecord(types.RecordMap{"mfa": types.True, "owner": doc}), "context.owner: expected Entity<User>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := QueryDecision(policies, nil, tt.principal, tt.action, tt.resource, tt.context, WithSchemaValidation(s))
+			testutil.Equals(t, result.Decision, types.Deny)
+			testutil.FatalIf(t, !errors.Is(result.Err, ErrRequestMismatch), "got %v, want ErrRequestMismatch", result.Err)
+			testutil.FatalIf(t, !strings.Contains(result.Err.Error(), tt.errSubstr), "got %v, want %q", result.Err, tt.errSubstr)
+			testutil.Equals(t, len(result.DeterminingPolicies), 0)
+		})
+	}
+
+	t.Run("queryPrincipals", func(t *testing.T) {
+		result := QueryPrincipals(policies, nil, view, doc, validContext, WithSchemaValidation(s))
+		testutil.OK(t, result.Err)
+
+		result = QueryPrincipals(policies, nil, view, group, validContext, WithSchemaValidation(s))
+		testutil.FatalIf(t, !errors.Is(result.Err, ErrRequestMismatch), "got %v, want ErrRequestMismatch", result.Err)
+		testutil.Equals(t, result.Decision, types.Deny)
+	})
+
+	t.Run("queryResources", func(t *testing.T) {
+		result := QueryResources(policies, nil, alice, view, types.Record{}, WithSchemaValidation(s))
+		testutil.FatalIf(t, !errors.Is(result.Err, ErrRequestMismatch), "got %v, want ErrRequestMismatch", result.Err)
+	})
+
+	t.Run("queryActions", func(t *testing.T) {
+		// join accepts an empty context even though view does not.
+		result := QueryActions(policies, nil, alice, group, types.Record{}, WithSchemaValidation(s))
+		testutil.OK(t, result.Err)
+
+		result = QueryActions(policies, nil, doc, group, types.Record{}, WithSchemaValidation(s))
+		testutil.FatalIf(t, !errors.Is(result.Err, ErrRequestMismatch), "got %v, want ErrRequestMismatch", result.Err)
+		testutil.FatalIf(t, !strings.Contains(result.Err.Error(), "no action accepts"), "got %v", result.Err)
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// MaxTruthTableAttributes is the largest number of attributes that TruthTable
+// varies, which keeps tables at no more than 65536 rows.
+const MaxTruthTableAttributes = 16
+
+// ErrTruthTableAttributes is wrapped by the error TruthTable returns when the
+// attributes to vary are unsuitable.
+var ErrTruthTableAttributes = errors.New("invalid truth table attributes")
+
+// TruthRow is one combination of the boolean context attributes varied by
+// TruthTable and the outcome of the policy for it.
+type TruthRow struct {
+	// Values maps each varied attribute to its value in this row.
+	Values map[string]bool
+	// Matches reports whether the policy's scope and conditions are all
+	// satisfied, in which case its effect applies.
+	Matches bool
+	// Err is the error raised while evaluating the policy. A policy that
+	// fails to evaluate does not match.
+	Err error
+}
+
+// TruthTable evaluates policy for every combination of values of the boolean
+// context attributes in boolAttrs, which makes the logic of `when` and
+// `unless` conditions over several flags easy to review and document. The
+// principal, action, resource and all other context attributes are those of
+// baseRequest.
+//
+// There is one row per combination, in the order of counting in binary with
+// the first attribute as the most significant bit: the first row sets every
+// attribute to false and the last sets every attribute to true.
+//
+// The attributes must be distinct and at most MaxTruthTableAttributes. If s
+// is not nil, the action of baseRequest must be declared in it and each
+// attribute must be a Bool in the action's context type.
+func TruthTable(
+	policy *ast.Policy,
+	s *schema.Schema,
+	entities types.EntityMap,
+	baseRequest types.Request,
+	boolAttrs []string,
+) ([]TruthRow, error) {
+	if err := checkTruthTableAttributes(s, baseRequest.Action, boolAttrs); err != nil {
+		return nil, err
+	}
+	node := PolicyToNode(policy).AsIsNode()
+	rows := make([]TruthRow, 0, 1<<len(boolAttrs))
+	for combination := range 1 << len(boolAttrs) {
+		ctx := maps.Clone(baseRequest.Context.Map())
+		if ctx == nil {
+			ctx = types.RecordMap{}
+		}
+		row := TruthRow{Values: make(map[string]bool, len(boolAttrs))}
+		for i, attr := range boolAttrs {
+			v := combination&(1<<(len(boolAttrs)-1-i)) != 0
+			row.Values[attr] = v
+			ctx[types.String(attr)] = types.Boolean(v)
+		}
+		env := Env{
+			Principal: baseRequest.Principal,
+			Action:    baseRequest.Action,
+			Resource:  baseRequest.Resource,
+			Context:   types.NewRecord(ctx),
+			Entities:  entities,
+		}
+		v, err := Eval(node, env)
+		row.Err = err
+		row.Matches = err == nil && v == types.True
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// checkTruthTableAttributes validates the attributes that TruthTable varies
+// for action, against s if it is not nil.
+func checkTruthTableAttributes(s *schema.Schema, action types.EntityUID, boolAttrs []string) error {
+	if len(boolAttrs) > MaxTruthTableAttributes {
+		return fmt.Errorf("%w: %d attributes exceed the maximum of %d", ErrTruthTableAttributes, len(boolAttrs), MaxTruthTableAttributes)
+	}
+	seen := make(map[string]bool, len(boolAttrs))
+	for _, attr := range boolAttrs {
+		if seen[attr] {
+			return fmt.Errorf("%w: attribute %s is repeated", ErrTruthTableAttributes, attr)
+		}
+		seen[attr] = true
+	}
+	if s == nil {
+		return nil
+	}
+	info, ok := s.ActionInfo(action)
+	if !ok {
+		return fmt.Errorf("%w: action %s is not declared", ErrTruthTableAttributes, action)
+	}
+	for _, attr := range boolAttrs {
+		t := info.Context.Attributes[attr]
+		if _, ok := t.Type.(schema.BoolType); !ok {
+			return fmt.Errorf("%w: context attribute %s of action %s is not a Bool", ErrTruthTableAttributes, attr, action)
+		}
+	}
+	return nil
+}
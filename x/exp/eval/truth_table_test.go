@@ -0,0 +1,117 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/testutil"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestTruthTable(t *testing.T) {
+	t.Parallel()
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		action view appliesTo {
+			principal: User,
+			resource: Document,
+			context: { mfa: Bool, internal: Bool, suspended: Bool, region: String },
+		};
+	`))
+	testutil.OK(t, err)
+
+	req := types.Request{
+		Principal: types.NewEntityUID("User", "alice"),
+		Action:    types.NewEntityUID("Action", "view"),
+		Resource:  types.NewEntityUID("Document", "readme"),
+		Context:   types.NewRecord(types.RecordMap{"region": types.String("eu"), "suspended": types.False}),
+	}
+	// permit when { context.mfa || context.internal } unless { context.suspended }
+	policy := ast.Permit().
+		When(ast.Context().Access("mfa").Or(ast.Context().Access("internal"))).
+		Unless(ast.Context().Access("suspended"))
+
+	t.Run("allCombinations", func(t *testing.T) {
+		t.Parallel()
+		rows, err := TruthTable(policy, s, nil, req, []string{"mfa", "internal"})
+		testutil.OK(t, err)
+		testutil.Equals(t, rows, []TruthRow{
+			{Values: map[string]bool{"mfa": false, "internal": false}, Matches: false},
+			{Values: map[string]bool{"mfa": false, "internal": true}, Matches: true},
+			{Values: map[string]bool{"mfa": true, "internal": false}, Matches: true},
+			{Values: map[string]bool{"mfa": true, "internal": true}, Matches: true},
+		})
+	})
+
+	t.Run("baseRequestFixesOtherAttributes", func(t *testing.T) {
+		t.Parallel()
+		suspended := req
+		suspended.Context = types.NewRecord(types.RecordMap{"region": types.String("eu"), "suspended": types.True})
+		rows, err := TruthTable(policy, s, nil, suspended, []string{"mfa", "internal"})
+		testutil.OK(t, err)
+		for _, row := range rows {
+			testutil.Equals(t, row.Matches, false)
+		}
+	})
+
+	t.Run("noAttributes", func(t *testing.T) {
+		t.Parallel()
+		rows, err := TruthTable(policy, s, nil, req, nil)
+		testutil.OK(t, err)
+		testutil.Equals(t, len(rows), 1)
+		testutil.Error(t, rows[0].Err)
+		testutil.Equals(t, rows[0].Matches, false)
+	})
+
+	t.Run("withoutSchema", func(t *testing.T) {
+		t.Parallel()
+		rows, err := TruthTable(policy, nil, nil, req, []string{"mfa", "internal", "suspended"})
+		testutil.OK(t, err)
+		testutil.Equals(t, len(rows), 8)
+		testutil.Equals(t, rows[3].Values, map[string]bool{"mfa": false, "internal": true, "suspended": true})
+		testutil.Equals(t, rows[3].Matches, false)
+		testutil.Equals(t, rows[6].Matches, true)
+	})
+
+	t.Run("invalidAttributes", func(t *testing.T) {
+		t.Parallel()
+		tooMany := make([]string, MaxTruthTableAttributes+1)
+		for i := range tooMany {
+			tooMany[i] = string(rune('a' + i))
+		}
+		tests := []struct {
+			name  string
+			req   types.Request
+			attrs []string
+		}{
+			{"notBool", req, []string{"region"}},
+			{"undeclared", req, []string{"admin"}},
+			{"repeated", req, []string{"mfa", "mfa"}},
+			{"tooMany", req, tooMany},
+			{"unknownAction", types.Request{Action: types.NewEntityUID("Action", "edit")}, []string{"mfa"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				_, err := TruthTable(policy, s, nil, tt.req, tt.attrs)
+				testutil.ErrorIs(t, err, ErrTruthTableAttributes)
+			})
+		}
+	})
+}
@@ -199,8 +199,9 @@ func convertRecordType(rec resolved.RecordType) RecordType {
 	attrs := make(map[string]AttributeType, len(rec))
 	for name, attr := range rec {
 		attrs[string(name)] = AttributeType{
-			Type:     convertType(attr.Type),
-			Required: !attr.Optional,
+			Type:        convertType(attr.Type),
+			Required:    !attr.Optional,
+			Annotations: convertAnnotations(attr.Annotations),
 		}
 	}
 	return RecordType{Attributes: attrs}
@@ -211,8 +212,9 @@ func convertRecordAttrs(rec resolved.RecordType) map[string]AttributeType {
 	attrs := make(map[string]AttributeType, len(rec))
 	for name, attr := range rec {
 		attrs[string(name)] = AttributeType{
-			Type:     convertType(attr.Type),
-			Required: !attr.Optional,
+			Type:        convertType(attr.Type),
+			Required:    !attr.Optional,
+			Annotations: convertAnnotations(attr.Annotations),
 		}
 	}
 	return attrs
@@ -0,0 +1,193 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/ast"
+)
+
+// EntityDef describes an entity type declared with [Builder.Entity].
+type EntityDef struct {
+	// MemberOf lists the entity types that entities of this type can be
+	// members of.
+	MemberOf []types.EntityType
+	// Attributes is the shape of the entity.
+	Attributes ast.RecordType
+	// Tags is the type of the entity's tags, or nil if it has none.
+	Tags        ast.IsType
+	Annotations ast.Annotations
+}
+
+// ActionDef describes an action declared with [Builder.Action]. An action
+// without principal or resource types never applies to a request, which is
+// how action groups are declared; such an action cannot have a context.
+type ActionDef struct {
+	PrincipalTypes []types.EntityType
+	ResourceTypes  []types.EntityType
+	// Context is the type of the request context, usually an
+	// [ast.RecordType] or a reference to a common type. Nil means an empty
+	// record.
+	Context ast.IsType
+	// MemberOf lists the IDs of the action groups, in the same namespace,
+	// that this action belongs to.
+	MemberOf    []types.String
+	Annotations ast.Annotations
+}
+
+// Builder builds a Schema from Go values, as an alternative to writing it in
+// the Cedar or JSON format. Declarations are added to the empty namespace
+// until Namespace selects another one. Type names are resolved as in the
+// Cedar format, relative to the namespace they are used in.
+//
+// Builder methods can be chained; errors such as duplicate declarations are
+// collected and returned by Build:
+//
+//	s, err := schema.NewBuilder().
+//	    Namespace("PhotoApp").
+//	    Entity("User", schema.EntityDef{
+//	        Attributes: ast.RecordType{"name": {Type: ast.String()}},
+//	    }).
+//	    Entity("Photo", schema.EntityDef{}).
+//	    Action("view", schema.ActionDef{
+//	        PrincipalTypes: []types.EntityType{"User"},
+//	        ResourceTypes:  []types.EntityType{"Photo"},
+//	    }).
+//	    Build()
+type Builder struct {
+	namespace  types.Path
+	namespaces map[types.Path]*ast.Namespace
+	err        error
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{namespaces: map[types.Path]*ast.Namespace{}}
+}
+
+// Namespace directs the declarations that follow into the namespace ns. The
+// empty string selects the empty namespace.
+func (b *Builder) Namespace(ns types.Path) *Builder {
+	b.namespace = ns
+	return b
+}
+
+// Entity declares the entity type name in the current namespace.
+func (b *Builder) Entity(name types.Ident, def EntityDef) *Builder {
+	ns := b.current()
+	if b.checkTypeName(ns, name) {
+		entity := ast.Entity{
+			Annotations: def.Annotations,
+			Shape:       def.Attributes,
+			Tags:        def.Tags,
+		}
+		for _, parent := range def.MemberOf {
+			entity.ParentTypes = append(entity.ParentTypes, ast.EntityType(parent))
+		}
+		ns.Entities[name] = entity
+	}
+	return b
+}
+
+// Enum declares the enumerated entity type name in the current namespace,
+// whose entities are exactly those with the given IDs.
+func (b *Builder) Enum(name types.Ident, values ...types.String) *Builder {
+	ns := b.current()
+	if b.checkTypeName(ns, name) {
+		ns.Enums[name] = ast.Enum{Values: values}
+	}
+	return b
+}
+
+// CommonType declares the common type name in the current namespace.
+func (b *Builder) CommonType(name types.Ident, t ast.IsType) *Builder {
+	ns := b.current()
+	if _, ok := ns.CommonTypes[name]; ok {
+		b.setErr(fmt.Errorf("duplicate common type %q in namespace %q", name, b.namespace))
+		return b
+	}
+	ns.CommonTypes[name] = ast.CommonType{Type: t}
+	return b
+}
+
+// Action declares the action name in the current namespace.
+func (b *Builder) Action(name types.String, def ActionDef) *Builder {
+	ns := b.current()
+	if _, ok := ns.Actions[name]; ok {
+		b.setErr(fmt.Errorf("duplicate action %q in namespace %q", name, b.namespace))
+		return b
+	}
+	if def.Context != nil && len(def.PrincipalTypes) == 0 && len(def.ResourceTypes) == 0 {
+		b.setErr(fmt.Errorf("action %q in namespace %q has a context but no principal or resource types", name, b.namespace))
+		return b
+	}
+	action := ast.Action{Annotations: def.Annotations}
+	for _, parent := range def.MemberOf {
+		action.Parents = append(action.Parents, ast.ParentRefFromID(parent))
+	}
+	if len(def.PrincipalTypes) > 0 || len(def.ResourceTypes) > 0 {
+		action.AppliesTo = &ast.AppliesTo{Context: def.Context}
+		for _, t := range def.PrincipalTypes {
+			action.AppliesTo.Principals = append(action.AppliesTo.Principals, ast.EntityType(t))
+		}
+		for _, t := range def.ResourceTypes {
+			action.AppliesTo.Resources = append(action.AppliesTo.Resources, ast.EntityType(t))
+		}
+	}
+	ns.Actions[name] = action
+	return b
+}
+
+// Build resolves the declarations into a Schema. It returns the errors
+// recorded while building, or an error if the schema is not well-formed, for
+// example because it refers to an undeclared type.
+func (b *Builder) Build() (*Schema, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	a := &ast.Schema{}
+	for path, ns := range b.namespaces {
+		// Copy so that later declarations do not change the built Schema.
+		c := copyNamespace(*ns)
+		if path == "" {
+			a.Entities, a.Enums, a.Actions, a.CommonTypes = c.Entities, c.Enums, c.Actions, c.CommonTypes
+			continue
+		}
+		if a.Namespaces == nil {
+			a.Namespaces = ast.Namespaces{}
+		}
+		a.Namespaces[path] = c
+	}
+	return newFromAST(a)
+}
+
+func (b *Builder) current() *ast.Namespace {
+	ns, ok := b.namespaces[b.namespace]
+	if !ok {
+		ns = &ast.Namespace{
+			Entities:    ast.Entities{},
+			Enums:       ast.Enums{},
+			Actions:     ast.Actions{},
+			CommonTypes: ast.CommonTypes{},
+		}
+		b.namespaces[b.namespace] = ns
+	}
+	return ns
+}
+
+// checkTypeName records an error and reports false if name is already
+// declared as an entity or enum type in ns.
+func (b *Builder) checkTypeName(ns *ast.Namespace, name types.Ident) bool {
+	_, entity := ns.Entities[name]
+	_, enum := ns.Enums[name]
+	if entity || enum {
+		b.setErr(fmt.Errorf("duplicate entity type %q in namespace %q", name, b.namespace))
+		return false
+	}
+	return true
+}
+
+func (b *Builder) setErr(err error) {
+	b.err = errors.Join(b.err, err)
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/resolved"
+)
+
+// DefaultAnnotation is the annotation that gives an optional attribute the
+// value to assume when a request leaves it out, written as it would be in
+// the request: `@default("false") trusted?: Bool`. The value must be a Bool,
+// Long or String literal or the text of an extension value, such as
+// @default("10.0.0.0/8") for an ipaddr. Cedar itself ignores the annotation;
+// eval.PrepareContext applies it to contexts.
+//
+// Only optional attributes may carry a default, since a required attribute
+// can never be left out. Constructing a Schema that annotates a required
+// attribute with @default is an error.
+const DefaultAnnotation = "default"
+
+// checkDefaults reports the first required attribute of rs, in a stable
+// order, that is annotated with @default.
+func checkDefaults(rs *resolved.Schema) error {
+	for _, name := range slices.Sorted(maps.Keys(rs.Entities)) {
+		e := rs.Entities[name]
+		if err := checkRecordDefaults(e.Shape); err != nil {
+			return fmt.Errorf("entity type %s: %w", name, err)
+		}
+		if err := checkTypeDefaults(e.Tags); err != nil {
+			return fmt.Errorf("tags of entity type %s: %w", name, err)
+		}
+	}
+	for _, uid := range slices.SortedFunc(maps.Keys(rs.Actions), types.EntityUID.Compare) {
+		a := rs.Actions[uid]
+		if a.AppliesTo == nil {
+			continue
+		}
+		if err := checkRecordDefaults(a.AppliesTo.Context); err != nil {
+			return fmt.Errorf("context of action %s: %w", uid, err)
+		}
+	}
+	return nil
+}
+
+func checkRecordDefaults(rec resolved.RecordType) error {
+	for _, name := range slices.Sorted(maps.Keys(rec)) {
+		attr := rec[name]
+		if _, ok := attr.Annotations[DefaultAnnotation]; ok && !attr.Optional {
+			return fmt.Errorf("attribute %q: @%s is only allowed on optional attributes", name, DefaultAnnotation)
+		}
+		if err := checkTypeDefaults(attr.Type); err != nil {
+			return fmt.Errorf("attribute %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func checkTypeDefaults(t resolved.IsType) error {
+	switch t := t.(type) {
+	case resolved.RecordType:
+		return checkRecordDefaults(t)
+	case resolved.SetType:
+		return checkTypeDefaults(t.Element)
+	}
+	return nil
+}
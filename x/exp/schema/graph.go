@@ -0,0 +1,141 @@
+package schema
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/resolved"
+)
+
+// GraphNodeKind distinguishes the nodes of a SchemaGraph.
+type GraphNodeKind string
+
+// The kinds of SchemaGraph nodes.
+const (
+	GraphNodeEntityType GraphNodeKind = "entityType"
+	GraphNodeAction     GraphNodeKind = "action"
+)
+
+// GraphEdgeKind distinguishes the edges of a SchemaGraph.
+type GraphEdgeKind string
+
+// The kinds of SchemaGraph edges.
+const (
+	// GraphEdgeMemberOf links an entity type to a type it can be a member
+	// of, or an action to an action group it belongs to.
+	GraphEdgeMemberOf GraphEdgeKind = "memberOf"
+	// GraphEdgePrincipal links an action to a principal type it applies to.
+	GraphEdgePrincipal GraphEdgeKind = "principal"
+	// GraphEdgeResource links an action to a resource type it applies to.
+	GraphEdgeResource GraphEdgeKind = "resource"
+)
+
+// A GraphNode is an entity type or an action of a schema.
+type GraphNode struct {
+	// ID is the fully qualified entity type, such as MyApp::User, or the
+	// action UID, such as MyApp::Action::"view".
+	ID   string        `json:"id"`
+	Kind GraphNodeKind `json:"kind"`
+	// Namespace is the namespace that declares the node, or "" for the
+	// empty namespace.
+	Namespace string `json:"namespace"`
+	// Name is the unqualified type name or the action ID.
+	Name string `json:"name"`
+}
+
+// A GraphEdge is a directed edge between the nodes with the IDs From and To.
+type GraphEdge struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Kind GraphEdgeKind `json:"kind"`
+}
+
+// A SchemaGraph is the dependency graph of the entity types and actions of a
+// schema. Nodes are sorted by ID and edges by source, kind and target.
+type SchemaGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// DependencyGraph returns the graph of the schema's entity types and actions,
+// with edges for entity type and action membership and for the principal and
+// resource types that each action applies to. It is derived from the schema
+// alone, so it can be rendered, for example with ToDOT, to document the
+// schema as it is.
+func (s *Schema) DependencyGraph() SchemaGraph {
+	g := SchemaGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	for et, info := range s.entityTypes {
+		ns, name := resolved.SplitPath(types.Path(et))
+		g.Nodes = append(g.Nodes, GraphNode{ID: string(et), Kind: GraphNodeEntityType, Namespace: string(ns), Name: string(name)})
+		for _, parent := range info.MemberOfTypes {
+			g.Edges = append(g.Edges, GraphEdge{From: string(et), To: string(parent), Kind: GraphEdgeMemberOf})
+		}
+	}
+	for uid, info := range s.actionTypes {
+		from := uid.String()
+		ns, _ := resolved.SplitPath(types.Path(uid.Type))
+		g.Nodes = append(g.Nodes, GraphNode{ID: from, Kind: GraphNodeAction, Namespace: string(ns), Name: string(uid.ID)})
+		for _, parent := range info.MemberOf {
+			g.Edges = append(g.Edges, GraphEdge{From: from, To: parent.String(), Kind: GraphEdgeMemberOf})
+		}
+		for _, pt := range info.PrincipalTypes {
+			g.Edges = append(g.Edges, GraphEdge{From: from, To: string(pt), Kind: GraphEdgePrincipal})
+		}
+		for _, rt := range info.ResourceTypes {
+			g.Edges = append(g.Edges, GraphEdge{From: from, To: string(rt), Kind: GraphEdgeResource})
+		}
+	}
+	slices.SortFunc(g.Nodes, func(a, b GraphNode) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(g.Edges, func(a, b GraphEdge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.To, b.To))
+	})
+	g.Edges = slices.Compact(g.Edges)
+	return g
+}
+
+// ToDOT renders g in the Graphviz DOT language. The nodes of each namespace
+// are grouped in a cluster labeled with the namespace; entity types are drawn
+// as boxes and actions as ellipses, and edges are labeled with their kind.
+func (g SchemaGraph) ToDOT() string {
+	byNamespace := map[string][]GraphNode{}
+	for _, n := range g.Nodes {
+		byNamespace[n.Namespace] = append(byNamespace[n.Namespace], n)
+	}
+	var b strings.Builder
+	b.WriteString("digraph schema {\n")
+	for _, n := range byNamespace[""] {
+		writeDOTNode(&b, "\t", n)
+	}
+	for _, ns := range slices.Sorted(maps.Keys(byNamespace)) {
+		if ns == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\tsubgraph %s {\n\t\tlabel=%s;\n", dotQuote("cluster_"+ns), dotQuote(ns))
+		for _, n := range byNamespace[ns] {
+			writeDOTNode(&b, "\t\t", n)
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(string(e.Kind)))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func writeDOTNode(b *strings.Builder, indent string, n GraphNode) {
+	shape := "box"
+	if n.Kind == GraphNodeAction {
+		shape = "ellipse"
+	}
+	fmt.Fprintf(b, "%s%s [shape=%s, label=%s];\n", indent, dotQuote(n.ID), shape, dotQuote(n.Name))
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/resolved"
+)
+
+// InlineCommonTypes returns an equivalent schema without common types, for
+// consumers that do not support them. Every attribute, tag, and context type
+// that referred to a common type, directly or through other common types, is
+// replaced by its definition, and entity type references within the replaced
+// types are fully qualified. Entities, actions, and requests validate the same
+// against the returned schema as against s.
+//
+// It returns an error if the common type definitions are cyclic.
+func (s *Schema) InlineCommonTypes() (*Schema, error) {
+	rs, err := s.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("inlining common types: %w", err)
+	}
+	in := s.astOrEmpty()
+	out := &ast.Schema{
+		Entities: inlineEntities("", in.Entities, rs),
+		Enums:    in.Enums,
+		Actions:  inlineActions("", in.Actions, rs),
+	}
+	if in.Namespaces != nil {
+		out.Namespaces = make(ast.Namespaces, len(in.Namespaces))
+		for name, ns := range in.Namespaces {
+			out.Namespaces[name] = ast.Namespace{
+				Annotations: ns.Annotations,
+				Entities:    inlineEntities(name, ns.Entities, rs),
+				Enums:       ns.Enums,
+				Actions:     inlineActions(name, ns.Actions, rs),
+			}
+		}
+	}
+	return newFromAST(out)
+}
+
+func inlineEntities(ns types.Path, entities ast.Entities, rs *resolved.Schema) ast.Entities {
+	if entities == nil {
+		return nil
+	}
+	out := make(ast.Entities, len(entities))
+	for name, entity := range entities {
+		res := rs.Entities[types.EntityType(qualifyName(string(ns), string(name)))]
+		if entity.Shape != nil {
+			entity.Shape = inlineRecordType(res.Shape)
+		}
+		if entity.Tags != nil {
+			entity.Tags = inlineType(res.Tags)
+		}
+		out[name] = entity
+	}
+	return out
+}
+
+func inlineActions(ns types.Path, actions ast.Actions, rs *resolved.Schema) ast.Actions {
+	if actions == nil {
+		return nil
+	}
+	actionType := types.EntityType(qualifyName(string(ns), "Action"))
+	out := make(ast.Actions, len(actions))
+	for name, action := range actions {
+		if action.AppliesTo != nil && action.AppliesTo.Context != nil {
+			appliesTo := *action.AppliesTo
+			res := rs.Actions[types.NewEntityUID(actionType, name)]
+			appliesTo.Context = inlineRecordType(res.AppliesTo.Context)
+			action.AppliesTo = &appliesTo
+		}
+		out[name] = action
+	}
+	return out
+}
+
+// inlineType converts a resolved type, in which common types are already
+// inlined, back to an AST type.
+func inlineType(t resolved.IsType) ast.IsType {
+	switch t := t.(type) {
+	case resolved.StringType:
+		return ast.String()
+	case resolved.LongType:
+		return ast.Long()
+	case resolved.BoolType:
+		return ast.Bool()
+	case resolved.ExtensionType:
+		return ast.ExtensionType(t)
+	case resolved.SetType:
+		return ast.Set(inlineType(t.Element))
+	case resolved.RecordType:
+		return inlineRecordType(t)
+	case resolved.EntityType:
+		return ast.EntityType(types.EntityType(t))
+	}
+	return nil
+}
+
+func inlineRecordType(rec resolved.RecordType) ast.RecordType {
+	out := make(ast.RecordType, len(rec))
+	for name, attr := range rec {
+		out[name] = ast.Attribute{
+			Type:        inlineType(attr.Type),
+			Optional:    attr.Optional,
+			Annotations: ast.Annotations(attr.Annotations),
+		}
+	}
+	return out
+}
@@ -41,6 +41,22 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 	return json.Marshal(out)
 }
 
+// MarshalFlatJSON encodes the bare declarations in the flat JSON format, with
+// entityTypes and actions at the top level. Named namespaces are not
+// included.
+func (s *Schema) MarshalFlatJSON() ([]byte, error) {
+	ns, err := marshalNamespace("", ast.Namespace{
+		Entities:    s.Entities,
+		Enums:       s.Enums,
+		Actions:     s.Actions,
+		CommonTypes: s.CommonTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ns)
+}
+
 // UnmarshalJSON parses a JSON schema into the AST.
 func (s *Schema) UnmarshalJSON(b []byte) error {
 	var raw map[string]json.RawMessage
@@ -408,7 +424,7 @@ func unmarshalType(jt *jsonType) (ast.IsType, error) {
 		}
 		elem, err := unmarshalType(jt.Element)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("element: %w", err)
 		}
 		return ast.Set(elem), nil
 	case "Record":
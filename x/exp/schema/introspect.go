@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"cmp"
 	"iter"
+	"slices"
 
 	"github.com/cedar-policy/cedar-go/types"
 )
@@ -141,6 +143,26 @@ func (s *Schema) RequestEnvs() iter.Seq[RequestEnv] {
 	}
 }
 
+// AllRequestShapes returns every principal-type / action / resource-type
+// combination the schema allows, taken from the cartesian product of each
+// action's principalTypes and resourceTypes. Actions are identified by their
+// fully qualified UID, so actions of the same name in different namespaces
+// yield distinct shapes. The result is deduplicated and sorted by action, then
+// principal type, then resource type.
+func (s *Schema) AllRequestShapes() []RequestShape {
+	shapes := slices.Clone(s.requestEnvs)
+	slices.SortFunc(shapes, compareRequestShapes)
+	return slices.Compact(shapes)
+}
+
+func compareRequestShapes(a, b RequestShape) int {
+	return cmp.Or(
+		cmp.Compare(a.Action.String(), b.Action.String()),
+		cmp.Compare(a.PrincipalType, b.PrincipalType),
+		cmp.Compare(a.ResourceType, b.ResourceType),
+	)
+}
+
 // ActionInfo returns the schema information for a given action.
 func (s *Schema) ActionInfo(action types.EntityUID) (*ActionTypeInfo, bool) {
 	info, ok := s.actionTypes[action]
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/cedar-policy/cedar-go/x/exp/schema/internal/parser"
+)
+
+// LoadFS reads and parses the schema file at name in fsys, which allows
+// loading a schema embedded with go:embed. Files with a ".json" extension are
+// parsed as Cedar JSON schemas, in either the namespaced or the flat format;
+// all other files are parsed as human-readable Cedar schemas. Errors are
+// prefixed with the filename.
+func LoadFS(fsys fs.FS, name string) (*Schema, error) {
+	src, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if path.Ext(name) == ".json" {
+		s, err := NewFromJSON(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return s, nil
+	}
+	// Parse errors already carry the filename and position.
+	a, err := parser.ParseSchema(name, src)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cedar schema: %w", err)
+	}
+	s, err := newFromAST(a)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return s, nil
+}
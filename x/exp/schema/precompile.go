@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// ReferenceError reports entity type references in a schema that do not name
+// a declared entity type. Errors are sorted for stable output.
+type ReferenceError struct {
+	Errors []string
+}
+
+func (e *ReferenceError) Error() string {
+	return strings.Join(e.Errors, "; ")
+}
+
+// precompiled caches the result of the reference check done by Precompile.
+// It is computed at most once per Schema and shared by every consumer of the
+// same pointer.
+type precompiled struct {
+	once sync.Once
+	err  error
+}
+
+// Precompile checks that every entity type referenced by memberOfTypes,
+// principalTypes and resourceTypes is declared, returning a [*ReferenceError]
+// if not. The check runs at most once per Schema; later calls, including
+// concurrent ones, return the cached result.
+//
+// The type tables that validators consult, such as the entity and action
+// type maps, are built when the Schema is constructed and shared by every
+// validator over it, so the reference check is the only work that Precompile
+// defers. Validators call Precompile when they are constructed; calling it up
+// front moves that check out of the first validator construction.
+func (s *Schema) Precompile() error {
+	s.precompiled.once.Do(func() {
+		s.precompiled.err = s.checkTypeReferences()
+	})
+	return s.precompiled.err
+}
+
+func (s *Schema) checkTypeReferences() error {
+	var errs []string
+	for _, et := range slices.Sorted(maps.Keys(s.entityTypes)) {
+		for _, mot := range s.entityTypes[et].MemberOfTypes {
+			if _, ok := s.entityTypes[mot]; !ok {
+				errs = append(errs, fmt.Sprintf("entity type %s references unknown memberOfTypes: %s", et, mot))
+			}
+		}
+	}
+	for _, uid := range slices.SortedFunc(maps.Keys(s.actionTypes), types.EntityUID.Compare) {
+		info := s.actionTypes[uid]
+		for _, pt := range info.PrincipalTypes {
+			if _, ok := s.entityTypes[pt]; !ok {
+				errs = append(errs, fmt.Sprintf("action %s references unknown principalType: %s", uid, pt))
+			}
+		}
+		for _, rt := range info.ResourceTypes {
+			if _, ok := s.entityTypes[rt]; !ok {
+				errs = append(errs, fmt.Sprintf("action %s references unknown resourceType: %s", uid, rt))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &ReferenceError{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// ResolveEntityType returns the fully qualified entity type that the schema
+// declares for name, which may be unqualified or partially qualified. A
+// declared type resolves to itself. Otherwise name resolves to the declared
+// type that it ends, at a namespace boundary, so that User resolves to
+// MyApp::User and Admin::User to MyApp::Admin::User. The types of declared
+// actions, such as MyApp::Action, are included.
+//
+// It returns false if no declared type matches name, or if several do.
+func (s *Schema) ResolveEntityType(name types.EntityType) (types.EntityType, bool) {
+	if s.declaresType(name) {
+		return name, true
+	}
+	suffix := "::" + string(name)
+	var match types.EntityType
+	found := false
+	for t := range s.declaredTypes() {
+		if !strings.HasSuffix(string(t), suffix) {
+			continue
+		}
+		if found {
+			return "", false
+		}
+		match, found = t, true
+	}
+	return match, found
+}
+
+// ResolveEntityTypeIn resolves name as a policy written for the default
+// namespace would: name refers to namespace::name if the schema declares
+// that type, and otherwise to name itself if the schema declares it. It
+// returns false if the schema declares neither, or if it declares both, in
+// which case name is ambiguous. Qualified names are only resolved as is.
+func (s *Schema) ResolveEntityTypeIn(name types.EntityType, namespace string) (types.EntityType, bool) {
+	asIs := s.declaresType(name)
+	if namespace == "" || strings.Contains(string(name), "::") {
+		return name, asIs
+	}
+	qualified := types.EntityType(namespace + "::" + string(name))
+	if !s.declaresType(qualified) {
+		return name, asIs
+	}
+	return qualified, !asIs
+}
+
+// declaresType reports whether t is a declared entity type or the type of a
+// declared action.
+func (s *Schema) declaresType(t types.EntityType) bool {
+	if _, ok := s.entityTypes[t]; ok {
+		return true
+	}
+	for uid := range s.actionTypes {
+		if uid.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredTypes returns the declared entity types and the types of declared
+// actions, each once.
+func (s *Schema) declaredTypes() map[types.EntityType]bool {
+	declared := make(map[types.EntityType]bool, len(s.entityTypes)+1)
+	for t := range s.entityTypes {
+		declared[t] = true
+	}
+	for uid := range s.actionTypes {
+		declared[uid.Type] = true
+	}
+	return declared
+}
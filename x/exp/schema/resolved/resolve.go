@@ -4,6 +4,9 @@ package resolved
 
 import (
 	"fmt"
+	"iter"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/cedar-policy/cedar-go/types"
@@ -56,21 +59,11 @@ type Action struct {
 
 // Resolve transforms an AST schema into a fully resolved schema.
 func Resolve(s *ast.Schema) (*Schema, error) {
-	r := &resolverState{
-		entityTypes: make(map[types.EntityType]bool),
-		enumTypes:   make(map[types.EntityType]bool),
-		commonTypes: make(map[types.Path]ast.IsType),
-	}
-
 	// Phase 1: Register all declarations
-	if err := r.registerDecls("", s.Entities, s.Enums, s.CommonTypes); err != nil {
+	r, err := newResolverState(s)
+	if err != nil {
 		return nil, err
 	}
-	for nsName, ns := range s.Namespaces {
-		if err := r.registerDecls(nsName, ns.Entities, ns.Enums, ns.CommonTypes); err != nil {
-			return nil, err
-		}
-	}
 
 	// Phase 2: Check for illegal shadowing (RFC 70)
 	if err := checkShadowing(s); err != nil {
@@ -119,15 +112,79 @@ func Resolve(s *ast.Schema) (*Schema, error) {
 		return nil, err
 	}
 
+	// Phase 6: Resolve every common type definition, so that unknown types
+	// are rejected even in common types that nothing uses
+	for _, name := range slices.Sorted(maps.Keys(r.commonTypes)) {
+		if _, err := r.resolveType(extractNamespace(name), r.commonTypes[name]); err != nil {
+			return nil, fmt.Errorf("common type %q: %w", name, err)
+		}
+	}
+
 	return result, nil
 }
 
 type resolverState struct {
 	entityTypes map[types.EntityType]bool
 	enumTypes   map[types.EntityType]bool
+	actionTypes map[types.EntityType]bool
 	commonTypes map[types.Path]ast.IsType
 }
 
+// newResolverState registers the declarations of every namespace of s.
+func newResolverState(s *ast.Schema) (*resolverState, error) {
+	r := &resolverState{
+		entityTypes: make(map[types.EntityType]bool),
+		enumTypes:   make(map[types.EntityType]bool),
+		actionTypes: make(map[types.EntityType]bool),
+		commonTypes: make(map[types.Path]ast.IsType),
+	}
+	if err := r.registerDecls("", s.Entities, s.Enums, s.CommonTypes); err != nil {
+		return nil, err
+	}
+	r.registerActionType("", s.Actions)
+	for nsName, ns := range s.Namespaces {
+		if err := r.registerDecls(nsName, ns.Entities, ns.Enums, ns.CommonTypes); err != nil {
+			return nil, err
+		}
+		r.registerActionType(nsName, ns.Actions)
+	}
+	return r, nil
+}
+
+// CommonTypeLookup returns a function that reports the fully qualified
+// common type that a type reference written in namespace ns of s names,
+// following the same disambiguation rules as Resolve. It reports false for
+// references to entity, enum, action and built-in types, and for undefined
+// types.
+func CommonTypeLookup(s *ast.Schema) (func(ns types.Path, ref ast.TypeRef) (types.Path, bool), error) {
+	r, err := newResolverState(s)
+	if err != nil {
+		return nil, err
+	}
+	return r.lookupCommonType, nil
+}
+
+// registerActionType records the action entity type of a namespace that
+// declares at least one action, so attribute types may refer to it.
+func (r *resolverState) registerActionType(nsName types.Path, actions ast.Actions) {
+	if len(actions) > 0 {
+		r.actionTypes[qualifyActionType(nsName)] = true
+	}
+}
+
+// isEntityType reports whether et names a declared entity or enum type.
+func (r *resolverState) isEntityType(et types.EntityType) bool {
+	return r.entityTypes[et] || r.enumTypes[et]
+}
+
+// isAttrEntityType reports whether et may be used as an entity type inside a
+// type expression. In addition to declared entity and enum types this admits
+// action entity types, so attributes can hold action UIDs. Action types are
+// not accepted for memberOf or appliesTo references.
+func (r *resolverState) isAttrEntityType(et types.EntityType) bool {
+	return r.isEntityType(et) || r.actionTypes[et]
+}
+
 func (r *resolverState) registerDecls(nsName types.Path, entities ast.Entities, enums ast.Enums, commonTypes ast.CommonTypes) error {
 	for name := range entities {
 		if _, ok := enums[name]; ok {
@@ -366,13 +423,13 @@ func (r *resolverState) resolveType(ns types.Path, t ast.IsType) (IsType, error)
 	case ast.SetType:
 		elem, err := r.resolveType(ns, t.Element)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("element: %w", err)
 		}
 		return SetType{Element: elem}, nil
 	case ast.RecordType:
 		return r.resolveRecordType(ns, t)
 	case ast.EntityTypeRef:
-		et, err := r.resolveEntityTypeRef(ns, t)
+		et, err := r.lookupEntityTypeRef(ns, t, r.isAttrEntityType)
 		if err != nil {
 			return nil, err
 		}
@@ -401,11 +458,15 @@ func (r *resolverState) resolveRecordType(ns types.Path, rec ast.RecordType) (Re
 }
 
 func (r *resolverState) resolveEntityTypeRef(ns types.Path, ref ast.EntityTypeRef) (types.EntityType, error) {
+	return r.lookupEntityTypeRef(ns, ref, r.isEntityType)
+}
+
+func (r *resolverState) lookupEntityTypeRef(ns types.Path, ref ast.EntityTypeRef, declared func(types.EntityType) bool) (types.EntityType, error) {
 	path := types.Path(ref)
 	// If it's already a qualified path (contains ::), resolve directly
 	if strings.Contains(string(path), "::") {
 		et := types.EntityType(path)
-		if r.entityTypes[et] || r.enumTypes[et] {
+		if declared(et) {
 			return et, nil
 		}
 		return "", fmt.Errorf("undefined entity type %q", path)
@@ -413,12 +474,12 @@ func (r *resolverState) resolveEntityTypeRef(ns types.Path, ref ast.EntityTypeRe
 	// Unqualified: try NS::Name first, then bare Name
 	if ns != "" {
 		qualified := types.EntityType(string(ns) + "::" + string(path))
-		if r.entityTypes[qualified] || r.enumTypes[qualified] {
+		if declared(qualified) {
 			return qualified, nil
 		}
 	}
 	bare := types.EntityType(path)
-	if r.entityTypes[bare] || r.enumTypes[bare] {
+	if declared(bare) {
 		return bare, nil
 	}
 	return "", fmt.Errorf("undefined entity type %q", path)
@@ -438,28 +499,22 @@ func (r *resolverState) resolveTypeRef(ns types.Path, ref ast.TypeRef) (IsType,
 	}
 
 	// Unqualified: follow disambiguation rules
+	// 1. and 3. Check NS::N, then N, as common type
+	if path, ok := r.lookupCommonType(ns, ref); ok {
+		return r.resolveType(extractNamespace(path), r.commonTypes[path])
+	}
+	// 2. Check NS::N as entity type
 	if ns != "" {
-		qualifiedPath := types.Path(string(ns) + "::" + string(ref))
-		// 1. Check NS::N as common type
-		if ct, ok := r.commonTypes[qualifiedPath]; ok {
-			return r.resolveType(ns, ct)
-		}
-		// 2. Check NS::N as entity type
-		qualifiedET := types.EntityType(qualifiedPath)
-		if r.entityTypes[qualifiedET] || r.enumTypes[qualifiedET] {
+		qualifiedET := types.EntityType(string(ns) + "::" + string(ref))
+		if r.isAttrEntityType(qualifiedET) {
 			return EntityType(qualifiedET), nil
 		}
 	}
 
-	// 3. Check N as common type in empty namespace
-	path := types.Path(ref)
-	if ct, ok := r.commonTypes[path]; ok {
-		return r.resolveType("", ct)
-	}
-
 	// 4. Check N as entity type in empty namespace
+	path := types.Path(ref)
 	bareET := types.EntityType(ref)
-	if r.entityTypes[bareET] || r.enumTypes[bareET] {
+	if r.isAttrEntityType(bareET) {
 		return EntityType(bareET), nil
 	}
 
@@ -471,6 +526,29 @@ func (r *resolverState) resolveTypeRef(ns types.Path, ref ast.TypeRef) (IsType,
 	return nil, fmt.Errorf("undefined type %q", ref)
 }
 
+// lookupCommonType returns the common type that ref names in namespace ns:
+// a qualified reference names itself, and an unqualified one names NS::N
+// unless an entity type NS::N shadows N of the empty namespace.
+func (r *resolverState) lookupCommonType(ns types.Path, ref ast.TypeRef) (types.Path, bool) {
+	if strings.Contains(string(ref), "::") {
+		path := types.Path(ref)
+		_, ok := r.commonTypes[path]
+		return path, ok
+	}
+	if ns != "" {
+		qualifiedPath := types.Path(string(ns) + "::" + string(ref))
+		if _, ok := r.commonTypes[qualifiedPath]; ok {
+			return qualifiedPath, true
+		}
+		if r.isAttrEntityType(types.EntityType(qualifiedPath)) {
+			return "", false
+		}
+	}
+	path := types.Path(ref)
+	_, ok := r.commonTypes[path]
+	return path, ok
+}
+
 func (r *resolverState) resolveQualifiedTypeRef(ref ast.TypeRef) (IsType, error) {
 	// Check for __cedar:: prefix first
 	if strings.HasPrefix(string(ref), "__cedar::") {
@@ -489,7 +567,7 @@ func (r *resolverState) resolveQualifiedTypeRef(ref ast.TypeRef) (IsType, error)
 	}
 	// Try as entity type
 	et := types.EntityType(ref)
-	if r.entityTypes[et] || r.enumTypes[et] {
+	if r.isAttrEntityType(et) {
 		return EntityType(et), nil
 	}
 	return nil, fmt.Errorf("undefined type %q", ref)
@@ -533,28 +611,33 @@ func (r *resolverState) validateActionMembership(result *Schema) error {
 		}
 	}
 
-	// Detect cycles using DFS
+	// Detect cycles using DFS, in a fixed order so that the reported cycle
+	// does not depend on map iteration.
 	visited := make(map[types.EntityUID]int) // 0=unvisited, 1=visiting, 2=done
+	var stack []types.EntityUID
 	var visit func(types.EntityUID) error
 	visit = func(uid types.EntityUID) error {
 		switch visited[uid] {
 		case 1:
-			return fmt.Errorf("cycle detected in action hierarchy involving %s", uid)
+			start := slices.Index(stack, uid)
+			return &CycleError{Path: append(slices.Clone(stack[start:]), uid)}
 		case 2:
 			return nil
 		}
 		visited[uid] = 1
+		stack = append(stack, uid)
 		action := result.Actions[uid]
-		for parent := range action.Entity.Parents.All() {
+		for _, parent := range sortedUIDs(action.Entity.Parents.All()) {
 			if err := visit(parent); err != nil {
 				return err
 			}
 		}
+		stack = stack[:len(stack)-1]
 		visited[uid] = 2
 		return nil
 	}
 
-	for uid := range result.Actions {
+	for _, uid := range sortedUIDs(maps.Keys(result.Actions)) {
 		if err := visit(uid); err != nil {
 			return err
 		}
@@ -563,6 +646,31 @@ func (r *resolverState) validateActionMembership(result *Schema) error {
 	return nil
 }
 
+func sortedUIDs(uids iter.Seq[types.EntityUID]) []types.EntityUID {
+	return slices.SortedFunc(uids, types.EntityUID.Compare)
+}
+
+// CycleError reports a cycle in the action hierarchy. Path lists the actions
+// along the cycle, starting and ending with the same action, for example
+// [Action::"a", Action::"b", Action::"a"].
+//
+// Only action cycles are errors, so Path holds action UIDs rather than entity
+// types. Cycles among entity types are allowed, as in the Cedar reference
+// implementation, because memberOfTypes only declares the permitted parent
+// types. The error is reported when the schema is resolved, before any
+// validator is constructed.
+type CycleError struct {
+	Path []types.EntityUID
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, len(e.Path))
+	for i, uid := range e.Path {
+		parts[i] = uid.String()
+	}
+	return "cycle detected in action hierarchy: " + strings.Join(parts, " -> ")
+}
+
 func lookupBuiltin(path types.Path) IsType {
 	switch path {
 	case "String":
@@ -625,9 +733,17 @@ func qualifyActionType(ns types.Path) types.EntityType {
 }
 
 func extractNamespace(path types.Path) types.Path {
+	ns, _ := SplitPath(path)
+	return ns
+}
+
+// SplitPath splits a qualified name, such as "Acme::Docs::Document", into its
+// namespace, "Acme::Docs", and its unqualified name, "Document". The
+// namespace of an unqualified name is empty.
+func SplitPath(path types.Path) (namespace types.Path, name types.Ident) {
 	s := string(path)
 	if idx := strings.LastIndex(s, "::"); idx >= 0 {
-		return types.Path(s[:idx])
+		return types.Path(s[:idx]), types.Ident(s[idx+2:])
 	}
-	return ""
+	return "", types.Ident(s)
 }
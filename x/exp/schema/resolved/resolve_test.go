@@ -1,6 +1,7 @@
 package resolved_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go/internal/testutil"
@@ -84,6 +85,17 @@ func TestResolveCommonType(t *testing.T) {
 	testutil.Equals(t, ok, true)
 }
 
+func TestResolveUnusedCommonTypeUndefined(t *testing.T) {
+	s := &ast.Schema{
+		CommonTypes: ast.CommonTypes{
+			"Unused": ast.CommonType{Type: ast.RecordType{"a": ast.Attribute{Type: ast.TypeRef("Nope")}}},
+		},
+	}
+	_, err := resolved.Resolve(s)
+	testutil.Error(t, err)
+	testutil.Equals(t, err.Error(), `common type "Unused": attribute "a": undefined type "Nope"`)
+}
+
 func TestResolveCommonTypeCycle(t *testing.T) {
 	s := &ast.Schema{
 		CommonTypes: ast.CommonTypes{
@@ -367,7 +379,48 @@ func TestResolveActionCycle(t *testing.T) {
 		},
 	}
 	_, err := resolved.Resolve(s)
-	testutil.Error(t, err)
+	var cycleErr *resolved.CycleError
+	testutil.Equals(t, errors.As(err, &cycleErr), true)
+	a, b := types.NewEntityUID("Action", "a"), types.NewEntityUID("Action", "b")
+	testutil.Equals(t, cycleErr.Path, []types.EntityUID{a, b, a})
+	testutil.Equals(t, err.Error(), `cycle detected in action hierarchy: Action::"a" -> Action::"b" -> Action::"a"`)
+}
+
+func TestResolveActionCyclePath(t *testing.T) {
+	t.Run("self", func(t *testing.T) {
+		s := &ast.Schema{
+			Actions: ast.Actions{
+				"a": ast.Action{Parents: []ast.ParentRef{ast.ParentRefFromID("a")}},
+			},
+		}
+		_, err := resolved.Resolve(s)
+		var cycleErr *resolved.CycleError
+		testutil.Equals(t, errors.As(err, &cycleErr), true)
+		a := types.NewEntityUID("Action", "a")
+		testutil.Equals(t, cycleErr.Path, []types.EntityUID{a, a})
+	})
+
+	t.Run("excludesPathToCycle", func(t *testing.T) {
+		s := &ast.Schema{
+			Namespaces: ast.Namespaces{
+				"App": ast.Namespace{
+					Actions: ast.Actions{
+						"a": ast.Action{Parents: []ast.ParentRef{ast.ParentRefFromID("b")}},
+						"b": ast.Action{Parents: []ast.ParentRef{ast.ParentRefFromID("c")}},
+						"c": ast.Action{Parents: []ast.ParentRef{ast.ParentRefFromID("d")}},
+						"d": ast.Action{Parents: []ast.ParentRef{ast.ParentRefFromID("b")}},
+					},
+				},
+			},
+		}
+		_, err := resolved.Resolve(s)
+		var cycleErr *resolved.CycleError
+		testutil.Equals(t, errors.As(err, &cycleErr), true)
+		b := types.NewEntityUID("App::Action", "b")
+		c := types.NewEntityUID("App::Action", "c")
+		d := types.NewEntityUID("App::Action", "d")
+		testutil.Equals(t, cycleErr.Path, []types.EntityUID{b, c, d, b})
+	})
 }
 
 func TestResolveActionUndefinedParent(t *testing.T) {
@@ -415,6 +468,70 @@ func TestResolveEnumAsEntityType(t *testing.T) {
 	testutil.Equals(t, result.Entities["User"].Shape["s"].Type, resolved.IsType(resolved.EntityType("Status")))
 }
 
+func TestResolveActionAsAttributeType(t *testing.T) {
+	s := &ast.Schema{
+		Entities: ast.Entities{
+			"Role": ast.Entity{
+				Shape: ast.RecordType{
+					"grants": ast.Attribute{Type: ast.Set(ast.TypeRef("Action"))},
+				},
+			},
+		},
+		Actions: ast.Actions{
+			"view": ast.Action{},
+		},
+		Namespaces: ast.Namespaces{
+			"NS": ast.Namespace{
+				Entities: ast.Entities{
+					"Grant": ast.Entity{
+						Shape: ast.RecordType{
+							"local":  ast.Attribute{Type: ast.TypeRef("Action")},
+							"global": ast.Attribute{Type: ast.EntityTypeRef("NS::Action")},
+						},
+					},
+				},
+				Actions: ast.Actions{
+					"edit": ast.Action{},
+				},
+			},
+		},
+	}
+	result, err := resolved.Resolve(s)
+	testutil.OK(t, err)
+	grants := result.Entities["Role"].Shape["grants"].Type
+	testutil.Equals(t, grants, resolved.IsType(resolved.SetType{Element: resolved.EntityType("Action")}))
+	nsGrant := result.Entities["NS::Grant"]
+	testutil.Equals(t, nsGrant.Shape["local"].Type, resolved.IsType(resolved.EntityType("NS::Action")))
+	testutil.Equals(t, nsGrant.Shape["global"].Type, resolved.IsType(resolved.EntityType("NS::Action")))
+}
+
+func TestResolveActionTypeRequiresActions(t *testing.T) {
+	s := &ast.Schema{
+		Entities: ast.Entities{
+			"Role": ast.Entity{
+				Shape: ast.RecordType{
+					"grant": ast.Attribute{Type: ast.TypeRef("Action")},
+				},
+			},
+		},
+	}
+	_, err := resolved.Resolve(s)
+	testutil.Error(t, err)
+}
+
+func TestResolveActionTypeNotEntityParent(t *testing.T) {
+	s := &ast.Schema{
+		Entities: ast.Entities{
+			"User": ast.Entity{ParentTypes: []ast.EntityTypeRef{"Action"}},
+		},
+		Actions: ast.Actions{
+			"view": ast.Action{},
+		},
+	}
+	_, err := resolved.Resolve(s)
+	testutil.Error(t, err)
+}
+
 func TestResolveSetType(t *testing.T) {
 	s := &ast.Schema{
 		Entities: ast.Entities{
@@ -1299,3 +1416,14 @@ func TestResolveDuplicateEntityEnumInNamespace(t *testing.T) {
 	testutil.Error(t, err)
 	testutil.Equals(t, err.Error(), `"Baz::Foo" is declared twice`)
 }
+
+func TestSplitPath(t *testing.T) {
+	t.Parallel()
+	ns, name := resolved.SplitPath("Acme::Docs::Document")
+	testutil.Equals(t, ns, types.Path("Acme::Docs"))
+	testutil.Equals(t, name, types.Ident("Document"))
+
+	ns, name = resolved.SplitPath("User")
+	testutil.Equals(t, ns, types.Path(""))
+	testutil.Equals(t, name, types.Ident("User"))
+}
@@ -5,6 +5,8 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/schema/ast"
@@ -30,6 +32,9 @@ type Schema struct {
 	actionEntities types.EntityMap
 	requestEnvs    []RequestEnv
 	prIndex        map[principalResourceKey][]types.EntityUID
+
+	// Result of the reference check, lazily computed by Precompile
+	precompiled precompiled
 }
 
 type principalResourceKey struct {
@@ -37,6 +42,14 @@ type principalResourceKey struct {
 	ResourceType  types.EntityType
 }
 
+// CycleError is returned when constructing a Schema whose action hierarchy
+// has a cycle. Its Path lists the actions along the cycle, starting and
+// ending with the same action. Cycles among entity types are allowed, since
+// memberOfTypes only declares which parent types are permitted. A cyclic
+// schema cannot be constructed, so validators never report cycles
+// themselves.
+type CycleError = resolved.CycleError
+
 // NewFromCedar parses a Cedar human-readable schema and eagerly resolves
 // all type references. The returned Schema is immutable.
 func NewFromCedar(filename string, src []byte) (*Schema, error) {
@@ -75,6 +88,56 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 	return js.MarshalJSON()
 }
 
+// MarshalJSONFlat encodes the schema in the flat Cedar JSON format, with
+// entityTypes and actions at the top level. The flat format can only hold
+// declarations in the empty namespace, so an error is returned if the schema
+// declares any named namespace.
+func (s *Schema) MarshalJSONFlat() ([]byte, error) {
+	a := s.astOrEmpty()
+	if len(a.Namespaces) > 0 {
+		names := slices.Sorted(maps.Keys(a.Namespaces))
+		return nil, fmt.Errorf("flat JSON schema cannot hold namespaces: %v", names)
+	}
+	js := (*schemajson.Schema)(a)
+	return js.MarshalFlatJSON()
+}
+
+// MarshalJSONNamespaced encodes the schema in the namespaced Cedar JSON
+// format with the declarations of the empty namespace moved under ns, so
+// that entity type User becomes ns::User. Declarations already in a named
+// namespace are unchanged. An error is returned if ns is already declared, or
+// if the moved declarations would no longer resolve, for example because
+// another namespace refers to them by their unqualified names. An empty ns
+// is equivalent to MarshalJSON.
+func (s *Schema) MarshalJSONNamespaced(ns string) ([]byte, error) {
+	a := s.astOrEmpty()
+	if ns == "" || !hasBareDecls(a) {
+		return s.MarshalJSON()
+	}
+	path := types.Path(ns)
+	if _, ok := a.Namespaces[path]; ok {
+		return nil, fmt.Errorf("namespace %s is already declared", ns)
+	}
+
+	out := &ast.Schema{Namespaces: make(ast.Namespaces, len(a.Namespaces)+1)}
+	maps.Copy(out.Namespaces, a.Namespaces)
+	out.Namespaces[path] = ast.Namespace{
+		Entities:    a.Entities,
+		Enums:       a.Enums,
+		Actions:     a.Actions,
+		CommonTypes: a.CommonTypes,
+	}
+	if _, err := resolved.Resolve(out); err != nil {
+		return nil, fmt.Errorf("moving declarations to namespace %s: %w", ns, err)
+	}
+	js := (*schemajson.Schema)(out)
+	return js.MarshalJSON()
+}
+
+func hasBareDecls(a *ast.Schema) bool {
+	return len(a.Entities) > 0 || len(a.Enums) > 0 || len(a.Actions) > 0 || len(a.CommonTypes) > 0
+}
+
 // AST returns the underlying AST. The returned value must not be mutated.
 func (s *Schema) AST() *ast.Schema {
 	return s.astOrEmpty()
@@ -98,6 +161,9 @@ func newFromAST(a *ast.Schema) (*Schema, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkDefaults(rs); err != nil {
+		return nil, err
+	}
 	s := &Schema{inner: a}
 	s.buildFromResolved(rs)
 	return s, nil
@@ -2,8 +2,11 @@ package schema_test
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/cedar-policy/cedar-go/internal/testutil"
 	"github.com/cedar-policy/cedar-go/types"
@@ -700,6 +703,83 @@ func TestSchema(t *testing.T) {
 		testutil.FatalIf(t, len(atMap) == 0, "ActionTypesMap should not be empty")
 	})
 
+	t.Run("AllRequestShapes", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromCedar("", []byte(`
+			entity User;
+			entity Admin;
+			entity Doc;
+			action view appliesTo { principal: [User, Admin, User], resource: Doc };
+			action group;
+			namespace App {
+				entity Photo;
+				action share appliesTo { principal: User, resource: [Doc, Photo, Photo] };
+			}
+		`))
+		testutil.OK(t, err)
+		view := types.NewEntityUID("Action", "view")
+		appShare := types.NewEntityUID("App::Action", "share")
+		testutil.Equals(t, s.AllRequestShapes(), []schema.RequestShape{
+			{PrincipalType: "Admin", Action: view, ResourceType: "Doc"},
+			{PrincipalType: "User", Action: view, ResourceType: "Doc"},
+			{PrincipalType: "User", Action: appShare, ResourceType: "App::Photo"},
+			{PrincipalType: "User", Action: appShare, ResourceType: "Doc"},
+		})
+	})
+
+	t.Run("InlineCommonTypes", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromCedar("", []byte(`
+			type Name = String;
+			type Address = { street: Name, city?: Name };
+			entity Group;
+			entity User in [Group] { name: Name, home: Address, emails: Set<Name> } tags Name;
+			action view appliesTo { principal: User, resource: Group, context: Address };
+			namespace Shop {
+				type Owner = Customer;
+				type Item = { owner: Owner, sku: Name, @doc("in cents") price: Long };
+				entity Customer;
+				entity Product { item: Item };
+				action buy appliesTo { principal: Customer, resource: Product, context: { item: Item } };
+			}
+		`))
+		testutil.OK(t, err)
+
+		inlined, err := s.InlineCommonTypes()
+		testutil.OK(t, err)
+		testutil.Equals(t, inlined.EntityTypesMap(), s.EntityTypesMap())
+		testutil.Equals(t, inlined.ActionTypesMap(), s.ActionTypesMap())
+		testutil.Equals(t, len(inlined.CommonTypesMap()), 0)
+		testutil.Equals(t, len(inlined.AST().CommonTypes), 0)
+		testutil.Equals(t, len(inlined.AST().Namespaces["Shop"].CommonTypes), 0)
+
+		// Entity references inside inlined types are fully qualified.
+		product, ok := inlined.EntityTypeInfoFor("Shop::Product")
+		testutil.FatalIf(t, !ok, "missing Shop::Product")
+		item := product.Attributes["item"].Type.(schema.RecordType)
+		testutil.Equals(t, item.Attributes["owner"].Type, schema.CedarType(schema.EntityCedarType{Name: "Shop::Customer"}))
+
+		// The inlined schema round-trips through both serializations.
+		src, err := inlined.MarshalCedar()
+		testutil.OK(t, err)
+		testutil.FatalIf(t, strings.Contains(string(src), "type "), "unexpected common type in %s", src)
+		reparsed, err := schema.NewFromCedar("", src)
+		testutil.OK(t, err)
+		testutil.Equals(t, reparsed.EntityTypesMap(), s.EntityTypesMap())
+		testutil.Equals(t, reparsed.ActionTypesMap(), s.ActionTypesMap())
+		js, err := inlined.MarshalJSON()
+		testutil.OK(t, err)
+		testutil.FatalIf(t, strings.Contains(string(js), "commonTypes"), "unexpected common types in %s", js)
+	})
+
+	t.Run("InlineCommonTypesEmpty", func(t *testing.T) {
+		t.Parallel()
+		var s schema.Schema
+		inlined, err := s.InlineCommonTypes()
+		testutil.OK(t, err)
+		testutil.Equals(t, len(inlined.EntityTypesMap()), 0)
+	})
+
 	t.Run("FlatJSONSchema", func(t *testing.T) {
 		t.Parallel()
 		flatJSON := `{
@@ -721,6 +801,130 @@ func TestSchema(t *testing.T) {
 		testutil.FatalIf(t, !hasUser, "should have User entity type")
 	})
 
+	t.Run("MarshalJSONFlatRoundTrip", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromCedar("", []byte(`
+			type Name = String;
+			entity Group;
+			entity User in [Group] { name: Name };
+			entity Color enum ["red", "blue"];
+			action read;
+			action view in [read] appliesTo { principal: User, resource: Group, context: { color: Color } };
+		`))
+		testutil.OK(t, err)
+		b, err := s.MarshalJSONFlat()
+		testutil.OK(t, err)
+		var top map[string]json.RawMessage
+		testutil.OK(t, json.Unmarshal(b, &top))
+		_, hasEntityTypes := top["entityTypes"]
+		testutil.FatalIf(t, !hasEntityTypes, "flat JSON should have top-level entityTypes: %s", b)
+		s2, err := schema.NewFromJSON(b)
+		testutil.OK(t, err)
+		testutil.Equals(t, s2.AST(), s.AST())
+	})
+
+	t.Run("MarshalJSONFlatEmpty", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewSchemaFromAST(&ast.Schema{})
+		testutil.OK(t, err)
+		b, err := s.MarshalJSONFlat()
+		testutil.OK(t, err)
+		s2, err := schema.NewFromJSON(b)
+		testutil.OK(t, err)
+		testutil.Equals(t, len(s2.EntityTypesMap()), 0)
+	})
+
+	t.Run("MarshalJSONFlatNamespacesErr", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromCedar("", []byte(`namespace NS { entity User; }`))
+		testutil.OK(t, err)
+		_, err = s.MarshalJSONFlat()
+		testutil.Error(t, err)
+	})
+
+	t.Run("MarshalJSONNamespaced", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromJSON([]byte(`{
+			"entityTypes": {
+				"User": { "memberOfTypes": ["Group"] },
+				"Group": {}
+			},
+			"actions": {
+				"view": { "appliesTo": { "principalTypes": ["User"], "resourceTypes": ["Group"] } }
+			}
+		}`))
+		testutil.OK(t, err)
+		b, err := s.MarshalJSONNamespaced("App")
+		testutil.OK(t, err)
+		var top map[string]json.RawMessage
+		testutil.OK(t, json.Unmarshal(b, &top))
+		_, hasApp := top["App"]
+		testutil.FatalIf(t, !hasApp || len(top) != 1, "expected only the App namespace: %s", b)
+
+		s2, err := schema.NewFromJSON(b)
+		testutil.OK(t, err)
+		testutil.Equals(t, s2.AST().Namespaces["App"].Entities, s.AST().Entities)
+		testutil.Equals(t, s2.AST().Namespaces["App"].Actions, s.AST().Actions)
+		_, hasUser := s2.EntityTypesMap()["App::User"]
+		testutil.FatalIf(t, !hasUser, "should have App::User")
+		testutil.Equals(t, s2.EntityTypesMap()["App::User"].MemberOfTypes, []types.EntityType{"App::Group"})
+
+		b, err = s.MarshalJSONNamespaced("")
+		testutil.OK(t, err)
+		s3, err := schema.NewFromJSON(b)
+		testutil.OK(t, err)
+		testutil.Equals(t, s3.AST(), s.AST())
+	})
+
+	t.Run("MarshalJSONNamespacedErr", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromCedar("", []byte(`
+			entity User;
+			namespace App { entity Doc; }
+		`))
+		testutil.OK(t, err)
+		_, err = s.MarshalJSONNamespaced("App")
+		testutil.Error(t, err)
+
+		s, err = schema.NewFromCedar("", []byte(`
+			entity User;
+			namespace Other { entity Doc in [User]; }
+		`))
+		testutil.OK(t, err)
+		_, err = s.MarshalJSONNamespaced("App")
+		testutil.Error(t, err)
+	})
+
+	t.Run("LoadFS", func(t *testing.T) {
+		t.Parallel()
+		fsys := fstest.MapFS{
+			"schema/app.cedarschema": {Data: []byte(`entity User; action view appliesTo { principal: User, resource: User };`)},
+			"schema/app.json":        {Data: []byte(`{"entityTypes": {"User": {}}, "actions": {}}`)},
+			"schema/bad.cedarschema": {Data: []byte(`entity User in [Missing];`)},
+			"schema/bad.json":        {Data: []byte(`{"entityTypes": {"User": {"memberOfTypes": ["Missing"]}}, "actions": {}}`)},
+			"schema/syntax.json":     {Data: []byte(`{`)},
+		}
+
+		s, err := schema.LoadFS(fsys, "schema/app.cedarschema")
+		testutil.OK(t, err)
+		_, hasUser := s.EntityTypesMap()["User"]
+		testutil.FatalIf(t, !hasUser, "should have User")
+
+		s, err = schema.LoadFS(fsys, "schema/app.json")
+		testutil.OK(t, err)
+		_, hasUser = s.EntityTypesMap()["User"]
+		testutil.FatalIf(t, !hasUser, "should have User")
+
+		for _, name := range []string{"schema/bad.cedarschema", "schema/bad.json", "schema/syntax.json"} {
+			_, err = schema.LoadFS(fsys, name)
+			testutil.Error(t, err)
+			testutil.FatalIf(t, !strings.Contains(err.Error(), name), "expected filename in error: %v", err)
+		}
+
+		_, err = schema.LoadFS(fsys, "schema/missing.json")
+		testutil.Error(t, err)
+	})
+
 	t.Run("SchemaFragment", func(t *testing.T) {
 		t.Parallel()
 		frag1, err := schema.NewFragmentFromCedar("", []byte(`
@@ -749,6 +953,183 @@ func TestSchema(t *testing.T) {
 		_, err = schema.FromFragments(frag1, frag2)
 		testutil.Error(t, err)
 	})
+
+	t.Run("ActionCycleErr", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewFromCedar("", []byte(`
+			entity User;
+			action read in [write];
+			action write in [admin];
+			action admin in [read];
+			action view in [read];
+		`))
+		var cycleErr *schema.CycleError
+		testutil.Equals(t, errors.As(err, &cycleErr), true)
+		admin := types.NewEntityUID("Action", "admin")
+		read := types.NewEntityUID("Action", "read")
+		write := types.NewEntityUID("Action", "write")
+		testutil.Equals(t, cycleErr.Path, []types.EntityUID{admin, read, write, admin})
+	})
+
+	t.Run("EntityTypeCycleAllowed", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewFromCedar("", []byte(`entity A in [B]; entity B in [A];`))
+		testutil.OK(t, err)
+	})
+
+	t.Run("PrecompileConcurrent", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewFromCedar("", []byte(`
+			entity Group;
+			entity User in [Group];
+			action view appliesTo { principal: User, resource: Group };
+		`))
+		testutil.OK(t, err)
+		var wg sync.WaitGroup
+		errs := make([]error, 8)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = s.Precompile()
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			testutil.OK(t, err)
+		}
+		testutil.OK(t, s.Precompile())
+	})
+
+	t.Run("DefaultOnOptionalAttribute", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewFromCedar("", []byte(`
+			entity User = { @default("x") nickname?: String };
+			action view appliesTo { principal: User, resource: User, context: { @default("false") trusted?: Bool } };
+		`))
+		testutil.OK(t, err)
+	})
+
+	t.Run("DefaultOnRequiredAttribute", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewFromCedar("", []byte(`
+			entity User;
+			action view appliesTo { principal: User, resource: User, context: { window: { @default("1h") ttl: duration } } };
+		`))
+		testutil.Error(t, err)
+		testutil.Equals(t, err.Error(), `context of action Action::"view": attribute "window": attribute "ttl": @default is only allowed on optional attributes`)
+
+		_, err = schema.NewFromCedar("", []byte(`entity User = { @default("x") tags: Set<{ @default("y") name: String }> };`))
+		testutil.Error(t, err)
+		testutil.Equals(t, err.Error(), `entity type User: attribute "tags": @default is only allowed on optional attributes`)
+	})
+}
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MatchesCedar", func(t *testing.T) {
+		t.Parallel()
+		want, err := schema.NewFromCedar("", []byte(`
+			type Address = { street: String, zip?: String };
+			entity Status enum ["active", "inactive"];
+			namespace App {
+				entity Group;
+				entity User in [Group] { name: String, address: Address, status: Status, tags: Set<String> } tags String;
+				entity Doc { owner: User };
+				action read;
+				action view in [read] appliesTo { principal: User, resource: Doc, context: { ip: ipaddr } };
+			}
+		`))
+		testutil.OK(t, err)
+
+		got, err := schema.NewBuilder().
+			CommonType("Address", ast.RecordType{
+				"street": {Type: ast.String()},
+				"zip":    {Type: ast.String(), Optional: true},
+			}).
+			Enum("Status", "active", "inactive").
+			Namespace("App").
+			Entity("Group", schema.EntityDef{}).
+			Entity("User", schema.EntityDef{
+				MemberOf: []types.EntityType{"Group"},
+				Attributes: ast.RecordType{
+					"name":    {Type: ast.String()},
+					"address": {Type: ast.Type("Address")},
+					"status":  {Type: ast.EntityType("Status")},
+					"tags":    {Type: ast.Set(ast.String())},
+				},
+				Tags: ast.String(),
+			}).
+			Entity("Doc", schema.EntityDef{Attributes: ast.RecordType{"owner": {Type: ast.EntityType("User")}}}).
+			Action("read", schema.ActionDef{}).
+			Action("view", schema.ActionDef{
+				PrincipalTypes: []types.EntityType{"User"},
+				ResourceTypes:  []types.EntityType{"Doc"},
+				Context:        ast.RecordType{"ip": {Type: ast.IPAddr()}},
+				MemberOf:       []types.String{"read"},
+			}).
+			Build()
+		testutil.OK(t, err)
+		testutil.Equals(t, got.EntityTypesMap(), want.EntityTypesMap())
+		testutil.Equals(t, got.ActionTypesMap(), want.ActionTypesMap())
+	})
+
+	t.Run("BuildIsolated", func(t *testing.T) {
+		t.Parallel()
+		b := schema.NewBuilder().Entity("User", schema.EntityDef{})
+		s, err := b.Build()
+		testutil.OK(t, err)
+		b.Entity("Group", schema.EntityDef{})
+		_, ok := s.EntityTypesMap()["Group"]
+		testutil.Equals(t, ok, false)
+	})
+
+	t.Run("DuplicateErr", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewBuilder().
+			Entity("User", schema.EntityDef{}).
+			Enum("User", "alice").
+			Action("view", schema.ActionDef{}).
+			Action("view", schema.ActionDef{}).
+			CommonType("T", ast.Long()).
+			CommonType("T", ast.Long()).
+			Build()
+		testutil.Error(t, err)
+		for _, want := range []string{`entity type "User"`, `action "view"`, `common type "T"`} {
+			testutil.FatalIf(t, !strings.Contains(err.Error(), want), "error %q should mention %s", err, want)
+		}
+	})
+
+	t.Run("ContextWithoutAppliesToErr", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewBuilder().
+			Action("view", schema.ActionDef{Context: ast.RecordType{"ip": {Type: ast.String()}}}).
+			Build()
+		testutil.Error(t, err)
+		testutil.FatalIf(t, !strings.Contains(err.Error(), `action "view"`), "error %q should mention the action", err)
+	})
+
+	t.Run("NamespacesSeparate", func(t *testing.T) {
+		t.Parallel()
+		s, err := schema.NewBuilder().
+			Entity("Group", schema.EntityDef{}).
+			Namespace("App").
+			Entity("User", schema.EntityDef{MemberOf: []types.EntityType{"Group"}}).
+			Build()
+		testutil.OK(t, err)
+		_, bare := s.EntityTypesMap()["Group"]
+		_, namespaced := s.EntityTypesMap()["App::User"]
+		testutil.Equals(t, bare && namespaced, true)
+	})
+
+	t.Run("ResolveErr", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewBuilder().
+			Entity("User", schema.EntityDef{MemberOf: []types.EntityType{"Missing"}}).
+			Build()
+		testutil.Error(t, err)
+	})
 }
 
 func stringEquals(t *testing.T, got, want string) {
@@ -765,3 +1146,193 @@ func normalizeJSON(t *testing.T, in []byte) []byte {
 	testutil.OK(t, err)
 	return b
 }
+
+func TestResolveEntityType(t *testing.T) {
+	t.Parallel()
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Tenant;
+		action ping appliesTo { principal: Tenant, resource: Tenant };
+		namespace MyApp {
+			entity User;
+			entity Document;
+			action view appliesTo { principal: User, resource: Document };
+		}
+		namespace MyApp::Admin {
+			entity User;
+		}
+		namespace Billing {
+			entity Document;
+		}
+	`))
+	testutil.OK(t, err)
+
+	t.Run("ResolveEntityType", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name string
+			want types.EntityType
+			ok   bool
+		}{
+			{"MyApp::User", "MyApp::User", true},
+			{"Tenant", "Tenant", true},
+			{"Admin::User", "MyApp::Admin::User", true},
+			{"Action", "Action", true},
+			{"MyApp::Action", "MyApp::Action", true},
+			{"User", "", false},
+			{"Document", "", false},
+			{"Missing", "", false},
+			{"pp::User", "", false},
+		}
+		for _, tt := range tests {
+			got, ok := s.ResolveEntityType(types.EntityType(tt.name))
+			testutil.Equals(t, ok, tt.ok)
+			if tt.ok {
+				testutil.Equals(t, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("ResolveEntityTypeIn", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name      string
+			namespace string
+			want      types.EntityType
+			ok        bool
+		}{
+			{"User", "MyApp", "MyApp::User", true},
+			{"Action", "MyApp", "", false},
+			{"Action", "Billing", "Action", true},
+			{"User", "MyApp::Admin", "MyApp::Admin::User", true},
+			{"Tenant", "MyApp", "Tenant", true},
+			{"Tenant", "Billing", "Tenant", true},
+			{"Document", "Billing", "Billing::Document", true},
+			{"MyApp::User", "Billing", "MyApp::User", true},
+			{"User", "", "", false},
+			{"Missing", "MyApp", "", false},
+		}
+		for _, tt := range tests {
+			got, ok := s.ResolveEntityTypeIn(types.EntityType(tt.name), tt.namespace)
+			testutil.Equals(t, ok, tt.ok)
+			if tt.ok {
+				testutil.Equals(t, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestNestedTypeErrorPath(t *testing.T) {
+	t.Parallel()
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name string
+			src  string
+			want string
+		}{
+			{
+				"unknownElementType",
+				`{"": {"entityTypes": {"User": {"shape": {"type": "Record", "attributes": {
+					"friends": {"type": "Set", "element": {"type": "UnknownType"}}}}}}, "actions": {}}}`,
+				`entity "User" shape: attribute "friends": element: unknown type "UnknownType"`,
+			},
+			{
+				"undefinedElementEntity",
+				`{"": {"entityTypes": {"User": {"shape": {"type": "Record", "attributes": {
+					"friends": {"type": "Set", "element": {"type": "Entity", "name": "Usr"}}}}}}, "actions": {}}}`,
+				`entity "User" shape: attribute "friends": element: undefined entity type "Usr"`,
+			},
+			{
+				"nestedInCommonType",
+				`{"": {"commonTypes": {"Contacts": {"type": "Set", "element": {"type": "Record", "attributes": {
+					"tags": {"type": "Set", "element": {"type": "Strin"}}}}}}, "entityTypes": {}, "actions": {}}}`,
+				`common type "Contacts": element: attribute "tags": element: unknown type "Strin"`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				_, err := schema.NewFromJSON([]byte(tt.src))
+				testutil.Error(t, err)
+				testutil.FatalIf(t, !strings.Contains(err.Error(), tt.want), "error %q should contain %q", err, tt.want)
+			})
+		}
+	})
+
+	t.Run("Cedar", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewFromCedar("", []byte(`
+			type Contact = { emails: Set<Set<Emial>> };
+			entity User { contacts: Set<Contact> };
+		`))
+		testutil.Error(t, err)
+		want := `entity "User" shape: attribute "contacts": element: attribute "emails": element: element: undefined type "Emial"`
+		testutil.FatalIf(t, !strings.Contains(err.Error(), want), "error %q should contain %q", err, want)
+	})
+
+	t.Run("UnusedCommonType", func(t *testing.T) {
+		t.Parallel()
+		_, err := schema.NewFromCedar("", []byte(`
+			type Unused = { a: Set<Nope> };
+			entity User;
+		`))
+		testutil.Error(t, err)
+		want := `common type "Unused": attribute "a": element: undefined type "Nope"`
+		testutil.FatalIf(t, !strings.Contains(err.Error(), want), "error %q should contain %q", err, want)
+	})
+}
+
+func TestDependencyGraph(t *testing.T) {
+	t.Parallel()
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Tenant;
+		namespace MyApp {
+			entity Group in [Tenant];
+			entity User in [Group];
+			entity Document;
+			action read;
+			action view in [read] appliesTo { principal: User, resource: Document };
+		}
+	`))
+	testutil.OK(t, err)
+	g := s.DependencyGraph()
+
+	testutil.Equals(t, g.Nodes, []schema.GraphNode{
+		{ID: `MyApp::Action::"read"`, Kind: schema.GraphNodeAction, Namespace: "MyApp", Name: "read"},
+		{ID: `MyApp::Action::"view"`, Kind: schema.GraphNodeAction, Namespace: "MyApp", Name: "view"},
+		{ID: "MyApp::Document", Kind: schema.GraphNodeEntityType, Namespace: "MyApp", Name: "Document"},
+		{ID: "MyApp::Group", Kind: schema.GraphNodeEntityType, Namespace: "MyApp", Name: "Group"},
+		{ID: "MyApp::User", Kind: schema.GraphNodeEntityType, Namespace: "MyApp", Name: "User"},
+		{ID: "Tenant", Kind: schema.GraphNodeEntityType, Namespace: "", Name: "Tenant"},
+	})
+	testutil.Equals(t, g.Edges, []schema.GraphEdge{
+		{From: `MyApp::Action::"view"`, To: `MyApp::Action::"read"`, Kind: schema.GraphEdgeMemberOf},
+		{From: `MyApp::Action::"view"`, To: "MyApp::User", Kind: schema.GraphEdgePrincipal},
+		{From: `MyApp::Action::"view"`, To: "MyApp::Document", Kind: schema.GraphEdgeResource},
+		{From: "MyApp::Group", To: "Tenant", Kind: schema.GraphEdgeMemberOf},
+		{From: "MyApp::User", To: "MyApp::Group", Kind: schema.GraphEdgeMemberOf},
+	})
+
+	stringEquals(t, g.ToDOT(), `
+digraph schema {
+	"Tenant" [shape=box, label="Tenant"];
+	subgraph "cluster_MyApp" {
+		label="MyApp";
+		"MyApp::Action::\"read\"" [shape=ellipse, label="read"];
+		"MyApp::Action::\"view\"" [shape=ellipse, label="view"];
+		"MyApp::Document" [shape=box, label="Document"];
+		"MyApp::Group" [shape=box, label="Group"];
+		"MyApp::User" [shape=box, label="User"];
+	}
+	"MyApp::Action::\"view\"" -> "MyApp::Action::\"read\"" [label="memberOf"];
+	"MyApp::Action::\"view\"" -> "MyApp::User" [label="principal"];
+	"MyApp::Action::\"view\"" -> "MyApp::Document" [label="resource"];
+	"MyApp::Group" -> "Tenant" [label="memberOf"];
+	"MyApp::User" -> "MyApp::Group" [label="memberOf"];
+}`)
+
+	empty, err := schema.NewFromCedar("", nil)
+	testutil.OK(t, err)
+	testutil.Equals(t, empty.DependencyGraph(), schema.SchemaGraph{Nodes: []schema.GraphNode{}, Edges: []schema.GraphEdge{}})
+	stringEquals(t, empty.DependencyGraph().ToDOT(), "digraph schema {\n}")
+}
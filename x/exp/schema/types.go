@@ -121,6 +121,8 @@ func (RecordType) IsUnknown() bool { return false }
 type AttributeType struct {
 	Type     CedarType
 	Required bool
+	// Annotations from the schema, such as @doc("description").
+	Annotations Annotations
 }
 
 // ExtensionType represents Cedar extension types.
@@ -231,6 +233,10 @@ type RequestEnv struct {
 	ResourceType  types.EntityType
 }
 
+// RequestShape is a principal-type / action / resource-type combination, as
+// returned by [Schema.AllRequestShapes].
+type RequestShape = RequestEnv
+
 // TypesMatch checks if actual type is compatible with expected type.
 func TypesMatch(expected, actual CedarType) bool {
 	switch e := expected.(type) {
@@ -15,6 +15,9 @@
 package validator
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/cedar-policy/cedar-go"
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/schema"
@@ -47,6 +50,35 @@ func ValidatePolicies(s *schema.Schema, policies *cedar.PolicySet, opts ...Valid
 	return v.ValidatePolicies(policies)
 }
 
+// ValidatePolicyString parses src as a single Cedar policy and validates it
+// against a schema. A parse error is returned as the error, distinct from the
+// validation errors in the result; a schema the validator cannot use is
+// reported in the result.
+//
+// Example:
+//
+//	result, err := validator.ValidatePolicyString(schema, `permit(principal, action, resource);`)
+//	if err != nil {
+//	    log.Fatalf("parse error: %v", err)
+//	}
+//	for _, err := range result.Errors {
+//	    log.Printf("%d:%d: %s", err.Position.Line, err.Position.Column, err.Message)
+//	}
+func ValidatePolicyString(s *schema.Schema, src string, opts ...ValidatorOption) (PolicyValidationResult, error) {
+	v, err := New(s, opts...)
+	if err != nil {
+		var policy cedar.Policy
+		if parseErr := policy.UnmarshalCedar([]byte(src)); parseErr != nil {
+			return PolicyValidationResult{}, parseErr
+		}
+		return PolicyValidationResult{
+			Valid:  false,
+			Errors: []PolicyError{{Message: err.Error()}},
+		}, nil
+	}
+	return v.ValidatePolicyString(src)
+}
+
 // ValidateEntities validates all entities against a schema.
 // This is a convenience function that creates a Validator and validates entities.
 //
@@ -74,6 +106,30 @@ func ValidateEntities(s *schema.Schema, entities types.EntityMap, opts ...Valida
 	return v.ValidateEntities(entities)
 }
 
+// ValidateEntitiesGrouped validates entities against a schema and groups the
+// errors by entity, which suits showing them next to each entity in a UI. The
+// entities are valid when every entry is empty. An error is returned if the
+// validator cannot be created, since it concerns none of the entities.
+//
+// Example:
+//
+//	grouped, err := validator.ValidateEntitiesGrouped(schema, entities)
+//	if err != nil {
+//	    log.Fatalf("validator: %v", err)
+//	}
+//	for uid, errs := range grouped {
+//	    for _, err := range errs {
+//	        log.Printf("Entity %s: %s", uid, err.Message)
+//	    }
+//	}
+func ValidateEntitiesGrouped(s *schema.Schema, entities types.EntityMap, opts ...ValidatorOption) (map[types.EntityUID][]ValidationError, error) {
+	v, err := New(s, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return v.ValidateEntitiesGrouped(entities), nil
+}
+
 // ValidateRequest validates a request against a schema.
 // This is a convenience function that creates a Validator and validates a request.
 //
@@ -93,3 +149,137 @@ func ValidateRequest(s *schema.Schema, req cedar.Request) RequestValidationResul
 	}
 	return v.ValidateRequest(req)
 }
+
+// IsPolicySatisfiable reports whether some request allowed by the schema
+// could make the policy match, and if not, why. This is a convenience
+// function that creates a Validator and calls [Validator.IsPolicySatisfiable].
+//
+// Example:
+//
+//	if ok, reason := validator.IsPolicySatisfiable(schema, policy); !ok {
+//	    log.Printf("policy can never apply: %s", reason)
+//	}
+func IsPolicySatisfiable(s *schema.Schema, policy *cedar.Policy, opts ...ValidatorOption) (satisfiable bool, reason string) {
+	v, err := New(s, opts...)
+	if err != nil {
+		return false, err.Error()
+	}
+	return v.IsPolicySatisfiable(policy)
+}
+
+// CounterExample returns a request allowed by the schema that matches the
+// policy's scope, or (nil, false) if the policy can never apply. This is a
+// convenience function that creates a Validator and calls
+// [Validator.CounterExample].
+//
+// Example:
+//
+//	if req, ok := validator.CounterExample(schema, policy); ok {
+//	    fmt.Printf("try: %s %s %s\n", req.Principal, req.Action, req.Resource)
+//	}
+func CounterExample(s *schema.Schema, policy *cedar.Policy, opts ...ValidatorOption) (*cedar.Request, bool) {
+	v, err := New(s, opts...)
+	if err != nil {
+		return nil, false
+	}
+	return v.CounterExample(policy)
+}
+
+// PoliciesEquivalent reports whether two policies match the same requests
+// under the schema and have the same effect. This is a convenience function
+// that creates a Validator and calls [Validator.PoliciesEquivalent]. A result
+// of false means equivalence could not be proven.
+//
+// Example:
+//
+//	if ok, err := validator.PoliciesEquivalent(schema, before, after); err == nil && ok {
+//	    log.Println("rewrite preserves behavior")
+//	}
+func PoliciesEquivalent(s *schema.Schema, p1, p2 *cedar.Policy, opts ...ValidatorOption) (bool, error) {
+	v, err := New(s, opts...)
+	if err != nil {
+		return false, err
+	}
+	return v.PoliciesEquivalent(p1, p2)
+}
+
+// ConflictingForbids returns the IDs of the forbid policies that could
+// override permit for some request allowed by the schema. This is a
+// convenience function that creates a Validator and calls
+// [Validator.ConflictingForbids]. The result may include forbids that only
+// overlap with the permit in principle; if the schema cannot be used, every
+// forbid is returned.
+//
+// Example:
+//
+//	for _, id := range validator.ConflictingForbids(schema, grant, policies) {
+//	    log.Printf("new grant may be blocked by %s", id)
+//	}
+func ConflictingForbids(s *schema.Schema, permit *cedar.Policy, forbids *cedar.PolicySet, opts ...ValidatorOption) []types.PolicyID {
+	v, err := New(s, opts...)
+	if err != nil {
+		var all []types.PolicyID
+		for id, forbid := range forbids.All() {
+			if forbid.Effect() == types.Forbid {
+				all = append(all, id)
+			}
+		}
+		slices.Sort(all)
+		return all
+	}
+	return v.ConflictingForbids(permit, forbids)
+}
+
+// CheckSchemaMigration reports the policies that validate under oldSchema but
+// not under newSchema, together with the breaking schema changes, such as
+// removed attributes or actions, that each of them depends on. The options
+// apply to both schemas. An error is returned if either schema cannot be used
+// for validation.
+//
+// Example:
+//
+//	report, err := validator.CheckSchemaMigration(current, proposed, policies)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, b := range report.Broken {
+//	    log.Printf("policy %s breaks: %v", b.PolicyID, b.Changes)
+//	}
+func CheckSchemaMigration(oldSchema, newSchema *schema.Schema, policies *cedar.PolicySet, opts ...ValidatorOption) (MigrationReport, error) {
+	oldV, err := New(oldSchema, opts...)
+	if err != nil {
+		return MigrationReport{}, fmt.Errorf("old schema: %w", err)
+	}
+	newV, err := New(newSchema, opts...)
+	if err != nil {
+		return MigrationReport{}, fmt.Errorf("new schema: %w", err)
+	}
+	return checkSchemaMigration(oldV, newV, policies), nil
+}
+
+// ValidateAll validates policies, entities, and requests against a schema in a
+// single call. This is a convenience function intended for CI tooling; use
+// [AllResult.HasErrors] to derive an exit code and [AllResult.Summary] for output.
+//
+// If the schema itself is malformed, the error is reported in the Policies result.
+//
+// Example:
+//
+//	result := validator.ValidateAll(schema, policies, entities, requests)
+//	if result.HasErrors() {
+//	    log.Print(result.Summary())
+//	    os.Exit(1)
+//	}
+func ValidateAll(s *schema.Schema, policies *cedar.PolicySet, entities types.EntityMap, requests []cedar.Request, opts ...ValidatorOption) AllResult {
+	v, err := New(s, opts...)
+	if err != nil {
+		return AllResult{
+			Policies: PolicyValidationResult{
+				Valid:  false,
+				Errors: []PolicyError{{Message: err.Error()}},
+			},
+			Entities: EntityValidationResult{Valid: true},
+		}
+	}
+	return v.ValidateAll(policies, entities, requests)
+}
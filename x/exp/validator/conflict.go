@@ -0,0 +1,129 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// -----------------------------------------------------------------------------
+// Conflicting Forbids
+// -----------------------------------------------------------------------------
+
+// ConflictingForbids returns, sorted, the IDs of the forbid policies in
+// forbids that could override permit for some request allowed by the schema.
+// Permit policies in forbids are ignored.
+//
+// The result is a sound over-approximation: every forbid that can block the
+// permit is listed, but a listed forbid may only overlap with it in
+// principle. Both policies are reduced to their primitive conditions for each
+// request shape, as in [Validator.PoliciesEquivalent], and a forbid is
+// excluded only if no shape applies to both policies, or if for every shared
+// shape one of its conditions contradicts one of the permit's, such as
+// `principal == User::"alice"` against `principal == User::"bob"`, or `x`
+// against `!x`.
+func (v *Validator) ConflictingForbids(permit *cedar.Policy, forbids *cedar.PolicySet) []types.PolicyID {
+	p := v.qualifiedAST(permit)
+	var conflicts []types.PolicyID
+	for id, forbid := range forbids.All() {
+		if forbid.Effect() != types.Forbid {
+			continue
+		}
+		if v.mayOverlap(p, v.qualifiedAST(forbid)) {
+			conflicts = append(conflicts, id)
+		}
+	}
+	slices.Sort(conflicts)
+	return conflicts
+}
+
+// qualifiedAST returns the AST of policy, qualified against the default
+// namespace.
+func (v *Validator) qualifiedAST(policy *cedar.Policy) *ast.Policy {
+	p := (*ast.Policy)(policy.AST())
+	if v.defaultNamespace != "" {
+		p, _ = v.qualifyPolicy(p)
+	}
+	return p
+}
+
+// mayOverlap reports whether some request of a shape the schema allows could
+// be matched by both p1 and p2.
+func (v *Validator) mayOverlap(p1, p2 *ast.Policy) bool {
+	for _, shape := range v.schema.AllRequestShapes() {
+		c1, applies1 := v.shapeConjuncts(p1, shape)
+		if !applies1 {
+			continue
+		}
+		c2, applies2 := v.shapeConjuncts(p2, shape)
+		if applies2 && !contradicts(c1, c2) {
+			return true
+		}
+	}
+	return false
+}
+
+// contradicts reports whether some condition in a and some condition in b
+// cannot both hold.
+func contradicts(a, b []conjunct) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if excludes(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excludes reports whether x and y cannot both hold: either they are the same
+// condition with opposite outcomes, or they require the same variable to equal
+// different values.
+func excludes(x, y conjunct) bool {
+	if reflect.DeepEqual(x.node, y.node) {
+		return x.want != y.want
+	}
+	if !x.want || !y.want {
+		return false
+	}
+	xVar, xVal, ok := variableEquality(x.node)
+	if !ok {
+		return false
+	}
+	yVar, yVal, ok := variableEquality(y.node)
+	return ok && xVar == yVar && !xVal.Equal(yVal)
+}
+
+// variableEquality matches a condition of the form `variable == value`.
+func variableEquality(n ast.IsNode) (types.String, types.Value, bool) {
+	eq, ok := n.(ast.NodeTypeEquals)
+	if !ok {
+		return "", nil, false
+	}
+	variable, ok := eq.Left.(ast.NodeTypeVariable)
+	if !ok {
+		return "", nil, false
+	}
+	value, ok := eq.Right.(ast.NodeValue)
+	if !ok {
+		return "", nil, false
+	}
+	return variable.Name, value.Value, true
+}
@@ -0,0 +1,101 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestConflictingForbids(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User { active: Bool };
+		entity Doc { public: Bool };
+		entity Folder;
+		action view appliesTo { principal: User, resource: Doc };
+		action list appliesTo { principal: User, resource: Folder };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	forbids, err := cedar.NewPolicySetFromBytes("forbids.cedar", []byte(`
+		forbid(principal, action, resource) unless { principal.active };
+		forbid(principal, action == Action::"list", resource);
+		forbid(principal == User::"bob", action, resource);
+		forbid(principal, action, resource is Doc) when { !resource.public };
+		forbid(principal, action, resource is Doc) when { resource.public };
+		permit(principal, action, resource);
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse policies: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		permit string
+		want   []types.PolicyID
+	}{
+		{"unconstrained", `permit(principal, action, resource);`, []types.PolicyID{"policy0", "policy1", "policy2", "policy3", "policy4"}},
+		{"other action", `permit(principal, action == Action::"view", resource);`, []types.PolicyID{"policy0", "policy2", "policy3", "policy4"}},
+		{"other principal", `permit(principal == User::"alice", action == Action::"view", resource);`, []types.PolicyID{"policy0", "policy3", "policy4"}},
+		{"contradicting condition", `permit(principal == User::"alice", action, resource) when { principal.active && resource is Doc && resource.public };`, []types.PolicyID{"policy4"}},
+		{"other resource type", `permit(principal, action, resource is Folder) when { principal.active };`, []types.PolicyID{"policy1", "policy2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var permit cedar.Policy
+			if err := permit.UnmarshalCedar([]byte(tt.permit)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			if got := ConflictingForbids(s, &permit, forbids); !slices.Equal(got, tt.want) {
+				t.Errorf("ConflictingForbids() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("entity data", func(t *testing.T) {
+		var permit cedar.Policy
+		if err := permit.UnmarshalCedar([]byte(`permit(principal, action, resource);`)); err != nil {
+			t.Fatalf("Failed to parse policy: %v", err)
+		}
+		forbids, err := cedar.NewPolicySetFromBytes("forbids.cedar", []byte(`
+			forbid(principal, action, resource) when { User::"a" in Folder::"f" };
+			forbid(principal, action, resource) when { User::"a".active };
+		`))
+		if err != nil {
+			t.Fatalf("Failed to parse policies: %v", err)
+		}
+		want := []types.PolicyID{"policy0", "policy1"}
+		if got := ConflictingForbids(s, &permit, forbids); !slices.Equal(got, want) {
+			t.Errorf("ConflictingForbids() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("nil schema", func(t *testing.T) {
+		var permit cedar.Policy
+		if err := permit.UnmarshalCedar([]byte(`permit(principal, action, resource);`)); err != nil {
+			t.Fatalf("Failed to parse policy: %v", err)
+		}
+		want := []types.PolicyID{"policy0", "policy1", "policy2", "policy3", "policy4"}
+		if got := ConflictingForbids(nil, &permit, forbids); !slices.Equal(got, want) {
+			t.Errorf("ConflictingForbids() = %v, want %v", got, want)
+		}
+	})
+}
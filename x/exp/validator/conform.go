@@ -0,0 +1,206 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Value Conformance
+// -----------------------------------------------------------------------------
+
+// ValueError reports a value that does not conform to its schema type.
+type ValueError struct {
+	// Path locates the value, such as "context.device.trusted". The elements
+	// of a set share the path of the set followed by ".element".
+	Path    string
+	Message string
+	// Err, if not nil, is the error returned by Convert, Default or Reference
+	// that caused the problem.
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// ValueChecker checks values against schema types. Every attribute of a
+// record and every element of a set is checked, records are closed unless
+// their type is open or AllowUndeclared is set, and the unknown values of
+// partial evaluation conform to every type. The zero value checks values
+// without changing them.
+type ValueChecker struct {
+	// AllowUndeclared, if true, accepts and keeps record attributes that the
+	// record type does not declare, as non-strict validation does.
+	AllowUndeclared bool
+	// Convert, if not nil, is applied to each value that is checked against a
+	// type other than a record or set, before it is checked. It returns the
+	// value to check in its place, such as an extension value parsed from a
+	// string.
+	Convert func(val types.Value, t schema.CedarType) (types.Value, error)
+	// Default, if not nil, returns the value of an optional attribute that a
+	// record leaves out, or false to leave it out.
+	Default func(attr schema.AttributeType) (types.Value, bool, error)
+	// Reference, if not nil, is called with each entity reference that is of
+	// the type it is checked against. A non-nil error is reported at the path
+	// of the reference.
+	Reference func(uid types.EntityUID) error
+}
+
+// CheckValue checks val, found at path, against t with a zero ValueChecker.
+func CheckValue(path string, val types.Value, t schema.CedarType) []*ValueError {
+	_, errs := ValueChecker{}.Check(path, val, t)
+	return errs
+}
+
+// Check checks val, found at path, against t. It returns val with Convert
+// and Default applied, along with every problem found, in a stable order.
+func (c ValueChecker) Check(path string, val types.Value, t schema.CedarType) (types.Value, []*ValueError) {
+	w := valueWalker{checker: c}
+	val = w.value(path, val, t)
+	return val, w.errs
+}
+
+// valueWalker checks a single value, collecting the problems it finds.
+type valueWalker struct {
+	checker ValueChecker
+	errs    []*ValueError
+}
+
+func (w *valueWalker) errorf(path, format string, args ...any) {
+	w.errs = append(w.errs, &ValueError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (w *valueWalker) fail(path string, err error) {
+	w.errs = append(w.errs, &ValueError{Path: path, Message: err.Error(), Err: err})
+}
+
+func (w *valueWalker) value(path string, val types.Value, t schema.CedarType) types.Value {
+	if eval.IsVariable(val) {
+		return val
+	}
+	switch t := t.(type) {
+	case schema.RecordType:
+		rec, ok := val.(types.Record)
+		if !ok {
+			w.errorf(path, "expected record, got %s", eval.TypeName(val))
+			return val
+		}
+		return w.record(path, rec, t)
+	case schema.SetType:
+		set, ok := val.(types.Set)
+		if !ok {
+			w.errorf(path, "expected set, got %s", eval.TypeName(val))
+			return val
+		}
+		var elems []types.Value
+		for elem := range set.All() {
+			elems = append(elems, w.value(path+".element", elem, t.Element))
+		}
+		return types.NewSet(elems...)
+	}
+	if w.checker.Convert != nil {
+		converted, err := w.checker.Convert(val, t)
+		if err != nil {
+			w.fail(path, err)
+			return val
+		}
+		val = converted
+	}
+	if !isValueOf(val, t) {
+		w.errorf(path, "expected %s, got %s", t, eval.TypeName(val))
+		return val
+	}
+	if uid, ok := val.(types.EntityUID); ok && w.checker.Reference != nil {
+		if err := w.checker.Reference(uid); err != nil {
+			w.fail(path, err)
+		}
+	}
+	return val
+}
+
+func (w *valueWalker) record(path string, rec types.Record, t schema.RecordType) types.Record {
+	out := types.RecordMap{}
+	for _, name := range slices.Sorted(maps.Keys(t.Attributes)) {
+		attr := t.Attributes[name]
+		attrPath := path + "." + name
+		if v, ok := rec.Get(types.String(name)); ok {
+			out[types.String(name)] = w.value(attrPath, v, attr.Type)
+			continue
+		}
+		if attr.Required {
+			w.errorf(attrPath, "required attribute is missing")
+			continue
+		}
+		if w.checker.Default == nil {
+			continue
+		}
+		v, ok, err := w.checker.Default(attr)
+		if err != nil {
+			w.fail(attrPath, err)
+		} else if ok {
+			out[types.String(name)] = v
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(rec.Map())) {
+		if _, ok := t.Attributes[string(name)]; ok {
+			continue
+		}
+		if !t.OpenRecord && !w.checker.AllowUndeclared {
+			w.errorf(path+"."+string(name), "attribute is not declared")
+			continue
+		}
+		out[name], _ = rec.Get(name)
+	}
+	return types.NewRecord(out)
+}
+
+// isValueOf reports whether val is of the primitive, entity, or extension
+// type t. Types that do not constrain values, such as UnknownType, accept any
+// value.
+func isValueOf(val types.Value, t schema.CedarType) bool {
+	switch t := t.(type) {
+	case schema.BoolType:
+		_, ok := val.(types.Boolean)
+		return ok
+	case schema.LongType:
+		_, ok := val.(types.Long)
+		return ok
+	case schema.StringType:
+		_, ok := val.(types.String)
+		return ok
+	case schema.AnyEntityType:
+		_, ok := val.(types.EntityUID)
+		return ok
+	case schema.EntityCedarType:
+		uid, ok := val.(types.EntityUID)
+		return ok && uid.Type == t.Name
+	case schema.ExtensionType:
+		ext, ok := extensionType(val)
+		return ok && ext == t
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestCheckValue(t *testing.T) {
+	device := schema.RecordType{Attributes: map[string]schema.AttributeType{
+		"trusted": {Type: schema.BoolType{}, Required: true},
+	}}
+	context := schema.RecordType{Attributes: map[string]schema.AttributeType{
+		"level":  {Type: schema.LongType{}, Required: true},
+		"ip":     {Type: schema.ExtensionType{Name: "ipaddr"}},
+		"tags":   {Type: schema.SetType{Element: schema.StringType{}}},
+		"owner":  {Type: schema.EntityCedarType{Name: "User"}},
+		"device": {Type: device},
+	}}
+
+	tests := []struct {
+		name string
+		val  types.Value
+		want []string
+	}{
+		{"valid", types.NewRecord(types.RecordMap{
+			"level":  types.Long(1),
+			"ip":     types.IPAddr{},
+			"tags":   types.NewSet(types.String("a")),
+			"owner":  types.NewEntityUID("User", "alice"),
+			"device": types.NewRecord(types.RecordMap{"trusted": types.True}),
+		}), nil},
+		{"not a record", types.Long(1), []string{"context: expected record, got long"}},
+		{"every problem", types.NewRecord(types.RecordMap{
+			"ip":     types.Long(1),
+			"tags":   types.NewSet(types.String("a"), types.Long(2)),
+			"owner":  types.NewEntityUID("Doc", "d"),
+			"device": types.NewRecord(types.RecordMap{"extra": types.True}),
+		}), []string{
+			"context.device.trusted: required attribute is missing",
+			"context.device.extra: attribute is not declared",
+			"context.ip: expected ipaddr, got long",
+			"context.level: required attribute is missing",
+			"context.owner: expected Entity<User>, got (entity of type `Doc`)",
+			"context.tags.element: expected String, got long",
+		}},
+		{"unknown values", types.NewRecord(types.RecordMap{
+			"level":  eval.Variable("level"),
+			"device": eval.Variable("device"),
+		}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range CheckValue("context", tt.val, context) {
+				got = append(got, err.Error())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("CheckValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueCheckerConvertAndDefault(t *testing.T) {
+	rec := schema.RecordType{Attributes: map[string]schema.AttributeType{
+		"ip":      {Type: schema.ExtensionType{Name: "ipaddr"}, Required: true},
+		"trusted": {Type: schema.BoolType{}},
+	}}
+	c := ValueChecker{
+		Convert: func(val types.Value, t schema.CedarType) (types.Value, error) {
+			if s, ok := val.(types.String); ok && t == (schema.ExtensionType{Name: "ipaddr"}) {
+				return types.ParseIPAddr(string(s))
+			}
+			return val, nil
+		},
+		Default: func(attr schema.AttributeType) (types.Value, bool, error) {
+			return types.False, true, nil
+		},
+	}
+	got, errs := c.Check("context", types.NewRecord(types.RecordMap{"ip": types.String("10.0.0.1")}), rec)
+	if len(errs) != 0 {
+		t.Fatalf("Check() errors: %v", errs)
+	}
+	ip, _ := types.ParseIPAddr("10.0.0.1")
+	want := types.NewRecord(types.RecordMap{"ip": ip, "trusted": types.False})
+	if !got.Equal(want) {
+		t.Errorf("Check() = %v, want %v", got, want)
+	}
+
+	_, errs = c.Check("context", types.NewRecord(types.RecordMap{"ip": types.String("nope")}), rec)
+	if len(errs) != 1 || errs[0].Path != "context.ip" {
+		t.Errorf("Check() errors = %v, want one error at context.ip", errs)
+	}
+}
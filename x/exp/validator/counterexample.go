@@ -0,0 +1,165 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"time"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Example Requests
+// -----------------------------------------------------------------------------
+
+// exampleID is the entity ID given to principals and resources that the
+// policy scope does not name.
+const exampleID = "example"
+
+// CounterExample returns a request, allowed by the schema, that matches the
+// scope of policy. It returns (nil, false) if [Validator.IsPolicySatisfiable]
+// reports that the policy can never apply, or if no request matches the scope
+// without entity data.
+//
+// Request shapes are tried in the order of [schema.Schema.AllRequestShapes],
+// skipping actions for which the policy's conditions can never hold. The
+// principal and resource are the entities named by the scope or, when the
+// scope only constrains their type, entities of that type with the ID
+// "example". Because membership is reflexive, `principal in Group::"g"` is
+// satisfied by Group::"g" itself when the shape allows that type, so the
+// example matches the scope without any entity data. A request carries no
+// entities, so a shape whose principal or resource could only satisfy such a
+// scope as a descendant of the named entity, such as a User shape for
+// `principal in Group::"g"`, yields no example.
+// The context holds an example value for every required attribute of the
+// action's context type. Generation is deterministic.
+func (v *Validator) CounterExample(policy *cedar.Policy) (*cedar.Request, bool) {
+	if ok, _ := v.IsPolicySatisfiable(policy); !ok {
+		return nil, false
+	}
+	p := (*ast.Policy)(policy.AST())
+	if v.defaultNamespace != "" {
+		p, _ = v.qualifyPolicy(p)
+	}
+	for _, shape := range v.schema.AllRequestShapes() {
+		if req, ok := v.exampleFor(p, shape); ok {
+			return req, true
+		}
+	}
+	return nil, false
+}
+
+// exampleFor returns a request of the given shape that matches p's scope.
+func (v *Validator) exampleFor(p *ast.Policy, shape schema.RequestShape) (*cedar.Request, bool) {
+	env, _ := v.partialEnv(shape.Action)
+	actionOnly := &ast.Policy{Effect: p.Effect, Principal: ast.ScopeTypeAll{}, Action: p.Action, Resource: ast.ScopeTypeAll{}}
+	if _, keep := eval.PartialPolicy(env, actionOnly); !keep {
+		return nil, false
+	}
+	if _, ok := v.conditionsMatch(shape.Action, p); !ok {
+		return nil, false
+	}
+	principal, ok := scopeExample(p.Principal, shape.PrincipalType)
+	if !ok {
+		return nil, false
+	}
+	resource, ok := scopeExample(p.Resource, shape.ResourceType)
+	if !ok {
+		return nil, false
+	}
+	var context types.Record
+	if info, ok := v.actionTypes[shape.Action]; ok {
+		context = exampleValue(info.Context).(types.Record)
+	}
+	return &cedar.Request{
+		Principal: principal,
+		Action:    shape.Action,
+		Resource:  resource,
+		Context:   context,
+	}, true
+}
+
+// scopeExample returns an entity of type t that satisfies scope on its own,
+// without relying on entity data.
+func scopeExample(scope ast.IsScopeNode, t types.EntityType) (types.EntityUID, bool) {
+	switch s := scope.(type) {
+	case ast.ScopeTypeAll:
+		return types.NewEntityUID(t, exampleID), true
+	case ast.ScopeTypeIs:
+		return types.NewEntityUID(t, exampleID), s.Type == t
+	case ast.ScopeTypeEq:
+		return s.Entity, s.Entity.Type == t
+	case ast.ScopeTypeIn:
+		// Membership is reflexive, so the named entity is itself in scope.
+		return s.Entity, s.Entity.Type == t
+	case ast.ScopeTypeIsIn:
+		return s.Entity, s.Type == t && s.Entity.Type == t
+	}
+	return types.EntityUID{}, false
+}
+
+// exampleValue returns an example value of type t, or nil if t is not a
+// concrete type. Primitives and extension values are zero values, sets hold
+// one example element, records hold their required attributes only, and
+// entity references name an entity with the ID "example".
+func exampleValue(t schema.CedarType) types.Value {
+	switch t := t.(type) {
+	case schema.BoolType:
+		return types.False
+	case schema.LongType:
+		return types.Long(0)
+	case schema.StringType:
+		return types.String("")
+	case schema.EntityCedarType:
+		return types.NewEntityUID(t.Name, exampleID)
+	case schema.SetType:
+		// A set of a single element lets the element type be inferred.
+		if elem := exampleValue(t.Element); elem != nil {
+			return types.NewSet(elem)
+		}
+		return types.NewSet()
+	case schema.RecordType:
+		m := types.RecordMap{}
+		for name, attr := range t.Attributes {
+			if !attr.Required {
+				continue
+			}
+			if val := exampleValue(attr.Type); val != nil {
+				m[types.String(name)] = val
+			}
+		}
+		return types.NewRecord(m)
+	case schema.ExtensionType:
+		return exampleExtension(t.Name)
+	}
+	return nil
+}
+
+func exampleExtension(name string) types.Value {
+	switch name {
+	case "ipaddr":
+		ip, _ := types.ParseIPAddr("0.0.0.0")
+		return ip
+	case "datetime":
+		return types.NewDatetime(time.Unix(0, 0).UTC())
+	case "duration":
+		return types.NewDuration(0)
+	}
+	return types.Decimal{}
+}
@@ -0,0 +1,112 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestCounterExample(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Org;
+		entity User in [Org] { age: Long };
+		entity Doc;
+		entity Folder;
+		action read;
+		action edit in [read] appliesTo { principal: User, resource: Doc };
+		action view in [read] appliesTo {
+			principal: User,
+			resource: [Doc, Folder],
+			context: { level: Long, ip: ipaddr, tags: Set<String>, owner: User, note?: String, device: { trusted: Bool } }
+		};
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	alice := types.NewEntityUID("User", "alice")
+	doc := types.NewEntityUID("Doc", "d1")
+	edit := types.NewEntityUID("Action", "edit")
+	view := types.NewEntityUID("Action", "view")
+	example := func(t types.EntityType) types.EntityUID { return types.NewEntityUID(t, "example") }
+
+	tests := []struct {
+		name   string
+		policy string
+		want   *cedar.Request
+	}{
+		{"unconstrained", `permit(principal, action, resource);`,
+			&cedar.Request{Principal: example("User"), Action: edit, Resource: example("Doc"), Context: types.NewRecord(nil)}},
+		{"named entities", `permit(principal == User::"alice", action == Action::"edit", resource == Doc::"d1");`,
+			&cedar.Request{Principal: alice, Action: edit, Resource: doc, Context: types.NewRecord(nil)}},
+		{"resource type", `permit(principal, action in Action::"read", resource is Folder);`,
+			&cedar.Request{Principal: example("User"), Action: view, Resource: example("Folder"), Context: types.NewRecord(types.RecordMap{
+				"level":  types.Long(0),
+				"ip":     exampleValue(schema.ExtensionType{Name: "ipaddr"}),
+				"tags":   types.NewSet(types.String("")),
+				"owner":  example("User"),
+				"device": types.NewRecord(types.RecordMap{"trusted": types.False}),
+			})}},
+		{"is in", `permit(principal is User in User::"alice", action == Action::"edit", resource);`,
+			&cedar.Request{Principal: alice, Action: edit, Resource: example("Doc"), Context: types.NewRecord(nil)}},
+		{"reflexive in", `permit(principal in User::"alice", action == Action::"edit", resource);`,
+			&cedar.Request{Principal: alice, Action: edit, Resource: example("Doc"), Context: types.NewRecord(nil)}},
+		{"conditions skip action", `permit(principal, action, resource) when { action == Action::"view" };`,
+			&cedar.Request{Principal: example("User"), Action: view, Resource: example("Doc"), Context: types.NewRecord(types.RecordMap{
+				"level":  types.Long(0),
+				"ip":     exampleValue(schema.ExtensionType{Name: "ipaddr"}),
+				"tags":   types.NewSet(types.String("")),
+				"owner":  example("User"),
+				"device": types.NewRecord(types.RecordMap{"trusted": types.False}),
+			})}},
+		{"entity data condition", `permit(principal, action == Action::"edit", resource) when { User::"a" in Org::"o" };`,
+			&cedar.Request{Principal: example("User"), Action: edit, Resource: example("Doc"), Context: types.NewRecord(nil)}},
+		{"descendant only", `permit(principal in Org::"o", action, resource);`, nil},
+		{"impossible scope", `permit(principal is Doc, action, resource);`, nil},
+		{"impossible condition", `permit(principal, action, resource) when { 1 > 2 };`, nil},
+	}
+
+	v, err := New(s)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy cedar.Policy
+			if err := policy.UnmarshalCedar([]byte(tt.policy)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			got, ok := CounterExample(s, &policy)
+			if tt.want == nil {
+				if ok || got != nil {
+					t.Fatalf("CounterExample() = %v, %v, want nil, false", got, ok)
+				}
+				return
+			}
+			if !ok || !got.Equal(*tt.want) {
+				t.Fatalf("CounterExample() = %v, %v, want %v", got, ok, tt.want)
+			}
+			if res := v.ValidateRequest(*got); !res.Valid {
+				t.Errorf("Example request does not validate: %s", res.Error)
+			}
+			if again, _ := CounterExample(s, &policy); !again.Equal(*got) {
+				t.Errorf("CounterExample() is not deterministic: %v then %v", got, again)
+			}
+		})
+	}
+}
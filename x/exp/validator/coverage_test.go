@@ -4555,10 +4555,10 @@ func TestExpectArgsWrongCountDirect(t *testing.T) {
 	ctx := &typeContext{v: v}
 
 	// Wrong count: pass 2 actual args but expect 1
-	ctx.expectArgs("testFunc", []schema.CedarType{schema.StringType{}, schema.LongType{}}, schema.StringType{})
+	ctx.expectArgs("testFunc", extFunction(schema.StringType{}, schema.StringType{}, nil), []schema.CedarType{schema.StringType{}, schema.LongType{}})
 	found := false
 	for _, e := range ctx.errors {
-		if strings.Contains(e, "expects 1 argument(s), got 2") {
+		if strings.Contains(e, "function testFunc expects 1 argument, got 2") {
 			found = true
 			break
 		}
@@ -28,6 +28,8 @@
 //
 // For one-off validation, use the convenience functions [ValidatePolicies],
 // [ValidateEntities], and [ValidateRequest] which create a Validator internally.
+// [ValidateAll] runs all three and returns a consolidated [AllResult], which is
+// convenient for CI tooling that needs a single pass/fail outcome.
 //
 // # Validator Options
 //
@@ -36,8 +38,22 @@
 //   - [WithMaxAttributeLevel]: Limits attribute access depth (RFC 76 level-based validation).
 //     Level 1 allows principal.name but not principal.manager.name.
 //   - [WithStrictEntityValidation]: Rejects entities with undeclared attributes.
+//   - [WithStrictEntityValidationFor], [WithOpenEntityTypes]: Turn strict entity
+//     validation on or off for individual entity types.
+//   - [WithValidateEntityReferences]: Rejects entity attributes that refer to
+//     entities missing from the validated entities.
 //   - [WithAllowUnknownEntityTypes]: Allows unknown entity types in schema references
 //     (matches Lean behavior).
+//   - [WithDefaultNamespace]: Resolves unqualified entity types and actions in
+//     policies against a namespace, e.g. User::"alice" as MyApp::User::"alice".
+//   - [WithHeterogeneousSetWarnings]: Reports set literals with mixed element
+//     types, such as [1, "two"], as warnings instead of errors.
+//   - [WithMultiTypeAttributeWarnings]: Reports attribute accesses that are
+//     missing or conflicting across several possible principal or resource
+//     types as warnings instead of errors.
+//   - [RequireAnnotations]: Reports policies that lack required annotations,
+//     such as @id or @owner, or whose @id differs from their policy ID, as a
+//     governance check.
 //
 // Example with options:
 //
@@ -55,6 +71,17 @@
 //
 // Use [WithAllowUnknownEntityTypes] for lenient mode that allows unknown types.
 //
+// # Schema Linting
+//
+// [LintSchema] reports hygiene issues in a well-formed schema that do not make
+// it invalid, such as entity types that nothing references or common types
+// used for both required and optional attributes:
+//
+//	findings, err := validator.LintSchema(schema)
+//	for _, f := range findings {
+//	    fmt.Printf("%s: %s\n", f.Code, f.Message)
+//	}
+//
 // # Policy Validation
 //
 // [Validator.ValidatePolicies] checks that all policies in a PolicySet are well-typed
@@ -71,12 +98,66 @@
 //
 //	result := validator.ValidatePolicies(schema, policies)
 //
+// [ValidatePolicyString] parses and validates a single policy in one step. A
+// parse error is returned as an error, separate from the validation errors in
+// the result, and each [PolicyError] carries the policy's source Position.
+// [PolicyValidationResult.WriteJSONL] writes the errors and warnings as JSON
+// Lines, one object per problem, for CI and log pipelines.
+//
 // Policy validation includes:
 //   - Type checking of expressions in when/unless clauses
 //   - Scope validation (principal/resource types match action constraints)
-//   - Optional attribute access warnings (use "has" to check first)
+//   - Optional attribute access warnings (use "has" to check first; a "has"
+//     guard in an && operand or if condition narrows the attribute to present)
 //   - Impossible policy detection (policy can never match any request)
 //
+// [Validator.IsPolicySatisfiable] goes further for a single policy: it also
+// partially evaluates the policy for each declared action, so conditions
+// that can never hold, such as when { 1 > 2 }, are reported with a reason:
+//
+//	if ok, reason := v.IsPolicySatisfiable(policy); !ok {
+//	    fmt.Printf("policy can never apply: %s\n", reason)
+//	}
+//
+// For a policy that can apply, [Validator.CounterExample] returns a
+// deterministic example request allowed by the schema that matches the
+// policy's scope, which is useful as a starting point for tests.
+//
+// [Validator.PoliciesEquivalent] compares two policies in the same way. It
+// reports true only when it can prove that both match the same requests with
+// the same effect, for example when one is a reordering of the other's
+// conditions; false means equivalence could not be proven:
+//
+//	if ok, err := v.PoliciesEquivalent(before, after); err == nil && ok {
+//	    fmt.Println("rewrite preserves behavior")
+//	}
+//
+// [Validator.ConflictingForbids] lists the forbid policies that could
+// override a permit, for example to warn that a new grant may be blocked by
+// existing denials. It errs on the side of listing a forbid:
+//
+//	for _, id := range v.ConflictingForbids(grant, policies) {
+//	    fmt.Printf("may be blocked by %s\n", id)
+//	}
+//
+// For editor feedback, [Validator.ValidatePolicyFast] validates a single
+// policy against only the actions and entity types it references. See its
+// documentation for the checks this limits.
+//
+// # Schema Migration
+//
+// [CheckSchemaMigration] validates policies under two versions of a schema
+// and lists those that only validate under the old one, each with the
+// removed actions, removed attributes or changed attribute types it depends
+// on. Run it before deploying a schema change:
+//
+//	report, err := validator.CheckSchemaMigration(current, proposed, policies)
+//	if err == nil && !report.Compatible() {
+//	    for _, b := range report.Broken {
+//	        fmt.Printf("%s: %v\n", b.PolicyID, b.Changes)
+//	    }
+//	}
+//
 // # Entity Validation
 //
 // [Validator.ValidateEntities] checks that all entities conform to the schema:
@@ -96,7 +177,12 @@
 //	}
 //
 // Use [WithStrictEntityValidation] to also reject entities with attributes
-// not declared in the schema.
+// not declared in the schema. [WithStrictEntityValidationFor] and
+// [WithOpenEntityTypes] override that choice for individual entity types.
+//
+// [Validator.ValidateEntitiesGrouped] reports the same problems grouped by
+// entity, with an empty entry for each valid entity, for showing errors next
+// to the entities they concern.
 //
 // # Request Validation
 //
@@ -113,4 +199,9 @@
 //	if !result.Valid {
 //	    fmt.Printf("Request error: %s\n", result.Error)
 //	}
+//
+// [CheckValue] checks a single value, such as a context record, against a
+// schema type and reports every problem with the path of the offending value.
+// A [ValueChecker] can also convert values and fill in absent optional
+// attributes while checking.
 package validator
@@ -15,21 +15,24 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/schema"
 )
 
 // validateEntity validates a single entity.
-func (v *Validator) validateEntity(uid types.EntityUID, entity types.Entity) []EntityError {
+func (v *Validator) validateEntity(uid types.EntityUID, entity types.Entity, entities types.EntityMap) []EntityError {
 	entityInfo, ok := v.entityTypes[uid.Type]
 	if !ok {
 		return v.handleUnknownEntityType(uid)
 	}
 
 	var errs []EntityError
-	errs = append(errs, v.validateEntityAttributes(uid, entity, entityInfo)...)
+	errs = append(errs, v.validateEntityAttributes(uid, entity, entityInfo, entities)...)
 	errs = append(errs, v.validateUndeclaredAttributes(uid, entity, entityInfo)...)
 	errs = append(errs, v.validateParentRelationships(uid, entity, entityInfo)...)
 	return errs
@@ -43,38 +46,70 @@ func (v *Validator) handleUnknownEntityType(uid types.EntityUID) []EntityError {
 	return []EntityError{{
 		EntityUID: uid,
 		Message:   fmt.Sprintf("entity type %s is not defined in schema", uid.Type),
+		Code:      ErrUnknownEntity,
 	}}
 }
 
 // validateEntityAttributes validates all declared attributes of an entity.
-func (v *Validator) validateEntityAttributes(uid types.EntityUID, entity types.Entity, info *schema.EntityTypeInfo) []EntityError {
+// Records nested in attributes are closed only when the entity type is
+// validated strictly; the top-level attributes are checked by
+// validateUndeclaredAttributes. When enabled, each entity reference of the
+// right type, including every element of a set, is also checked to exist.
+func (v *Validator) validateEntityAttributes(uid types.EntityUID, entity types.Entity, info *schema.EntityTypeInfo, entities types.EntityMap) []EntityError {
+	checker := ValueChecker{AllowUndeclared: !v.strictFor(uid.Type)}
+	if v.entityReferences {
+		checker.Reference = func(ref types.EntityUID) error {
+			if _, ok := entities[ref]; ok || v.isKnownActionEntity(ref) {
+				return nil
+			}
+			return &missingReferenceError{uid: ref}
+		}
+	}
 	var errs []EntityError
-	for attrName, attrType := range info.Attributes {
-		if err := v.validateEntityAttribute(uid, entity, attrName, attrType); err != nil {
-			errs = append(errs, *err)
+	for _, attrName := range slices.Sorted(maps.Keys(info.Attributes)) {
+		attrType := info.Attributes[attrName]
+		attrVal, exists := entity.Attributes.Get(types.String(attrName))
+		if !exists {
+			if attrType.Required {
+				errs = append(errs, EntityError{EntityUID: uid, Message: fmt.Sprintf("required attribute %s is missing", attrName), Code: ErrMissingAttribute})
+			}
+			continue
+		}
+		_, valErrs := checker.Check(attrName, attrVal, attrType.Type)
+		for _, err := range valErrs {
+			code := ErrUnexpectedType
+			var missing *missingReferenceError
+			if errors.As(err, &missing) {
+				code = ErrMissingEntityReference
+			}
+			errs = append(errs, EntityError{EntityUID: uid, Message: "attribute " + err.Error(), Code: code})
 		}
 	}
 	return errs
 }
 
-// validateEntityAttribute validates a single attribute of an entity.
-func (v *Validator) validateEntityAttribute(uid types.EntityUID, entity types.Entity, attrName string, attrType schema.AttributeType) *EntityError {
-	attrVal, exists := entity.Attributes.Get(types.String(attrName))
-	if !exists {
-		if attrType.Required {
-			return &EntityError{EntityUID: uid, Message: fmt.Sprintf("required attribute %s is missing", attrName)}
-		}
-		return nil
-	}
-	if err := v.validateValue(attrVal, attrType.Type); err != nil {
-		return &EntityError{EntityUID: uid, Message: fmt.Sprintf("attribute %s: %v", attrName, err)}
+// missingReferenceError reports an entity reference to an entity that is not
+// among the validated entities.
+type missingReferenceError struct {
+	uid types.EntityUID
+}
+
+func (e *missingReferenceError) Error() string {
+	return fmt.Sprintf("referenced entity %s does not exist", e.uid)
+}
+
+// strictFor reports whether entities of type et are validated strictly. A
+// per-type setting takes precedence over the global one.
+func (v *Validator) strictFor(et types.EntityType) bool {
+	if v.openEntityTypes[et] {
+		return false
 	}
-	return nil
+	return v.strictEntityTypes[et] || v.strictEntityValidation
 }
 
 // validateUndeclaredAttributes checks for undeclared attributes in strict mode.
 func (v *Validator) validateUndeclaredAttributes(uid types.EntityUID, entity types.Entity, info *schema.EntityTypeInfo) []EntityError {
-	if !v.strictEntityValidation || info.OpenRecord {
+	if !v.strictFor(uid.Type) || info.OpenRecord {
 		return nil
 	}
 	var errs []EntityError
@@ -83,6 +118,7 @@ func (v *Validator) validateUndeclaredAttributes(uid types.EntityUID, entity typ
 			errs = append(errs, EntityError{
 				EntityUID: uid,
 				Message:   fmt.Sprintf("attribute %s is not declared in schema", attrName),
+				Code:      ErrUndeclaredAttribute,
 			})
 		}
 	}
@@ -97,6 +133,7 @@ func (v *Validator) validateParentRelationships(uid types.EntityUID, entity type
 			errs = append(errs, EntityError{
 				EntityUID: uid,
 				Message:   fmt.Sprintf("entity cannot be member of type %s", parent.Type),
+				Code:      ErrInvalidParent,
 			})
 		}
 	}
@@ -104,63 +141,15 @@ func (v *Validator) validateParentRelationships(uid types.EntityUID, entity type
 }
 
 // validateContext validates context against an expected record type.
+// Undeclared attributes are rejected only in strict mode.
 func (v *Validator) validateContext(context types.Value, expected schema.RecordType) error {
-	rec, ok := context.(types.Record)
-	if !ok {
-		return fmt.Errorf("context must be a record, got %T", context)
-	}
-
-	if err := v.validateContextAttributes(rec, expected); err != nil {
-		return err
-	}
-	return v.validateContextUndeclaredAttributes(rec, expected)
-}
-
-// validateContextAttributes validates all declared context attributes.
-func (v *Validator) validateContextAttributes(rec types.Record, expected schema.RecordType) error {
-	for attrName, attrType := range expected.Attributes {
-		if err := v.validateContextAttribute(rec, attrName, attrType); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// validateContextAttribute validates a single context attribute.
-func (v *Validator) validateContextAttribute(rec types.Record, attrName string, attrType schema.AttributeType) error {
-	val, exists := rec.Get(types.String(attrName))
-	if !exists {
-		if attrType.Required {
-			return fmt.Errorf("required context attribute %s is missing", attrName)
-		}
-		return nil
-	}
-	if err := v.validateValue(val, attrType.Type); err != nil {
-		return fmt.Errorf("context attribute %s: %v", attrName, err)
-	}
-	return nil
-}
-
-// validateContextUndeclaredAttributes checks for undeclared context attributes in strict mode.
-func (v *Validator) validateContextUndeclaredAttributes(rec types.Record, expected schema.RecordType) error {
-	if !v.strictEntityValidation || expected.OpenRecord {
-		return nil
-	}
-	for attrName := range rec.All() {
-		if _, declared := expected.Attributes[string(attrName)]; !declared {
-			return fmt.Errorf("context attribute %s is not declared in schema", attrName)
-		}
-	}
-	return nil
-}
-
-// validateValue validates a value against an expected type.
-func (v *Validator) validateValue(val types.Value, expected schema.CedarType) error {
-	actual := v.inferType(val)
-	if !schema.TypesMatch(expected, actual) {
-		return fmt.Errorf("expected %s, got %s", expected, actual)
+	checker := ValueChecker{AllowUndeclared: !v.strictEntityValidation}
+	_, valErrs := checker.Check("context", context, expected)
+	var errs []error
+	for _, err := range valErrs {
+		errs = append(errs, err)
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // inferType infers the Cedar type from a value.
@@ -178,17 +167,28 @@ func (v *Validator) inferType(val types.Value) schema.CedarType {
 		return v.inferSetType(typedVal)
 	case types.Record:
 		return v.inferRecordType(typedVal)
+	default:
+		if ext, ok := extensionType(val); ok {
+			return ext
+		}
+		return schema.UnknownType{}
+	}
+}
+
+// extensionType returns the extension type of val, or false if val is not an
+// extension value.
+func extensionType(val types.Value) (schema.ExtensionType, bool) {
+	switch val.(type) {
 	case types.Decimal:
-		return schema.ExtensionType{Name: "decimal"}
+		return schema.ExtensionType{Name: "decimal"}, true
 	case types.IPAddr:
-		return schema.ExtensionType{Name: "ipaddr"}
+		return schema.ExtensionType{Name: "ipaddr"}, true
 	case types.Datetime:
-		return schema.ExtensionType{Name: "datetime"}
+		return schema.ExtensionType{Name: "datetime"}, true
 	case types.Duration:
-		return schema.ExtensionType{Name: "duration"}
-	default:
-		return schema.UnknownType{}
+		return schema.ExtensionType{Name: "duration"}, true
 	}
+	return schema.ExtensionType{}, false
 }
 
 // inferSetType infers the type of a Set value.
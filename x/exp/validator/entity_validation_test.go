@@ -15,9 +15,11 @@
 package validator
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/cedar-policy/cedar-go"
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/schema"
 )
@@ -618,6 +620,86 @@ func TestValidateEntitiesWithActionEntity(t *testing.T) {
 	}
 }
 
+// TestEntityAttributeHoldingActions tests that an entity attribute typed as a
+// set of actions accepts action UIDs, and that a policy checking membership of
+// the request action in that set validates and evaluates.
+func TestEntityAttributeHoldingActions(t *testing.T) {
+	schemaText := `
+		entity Role {
+			grants: Set<Action>
+		};
+		entity User {
+			role: Role
+		};
+		entity Document;
+		action view, edit appliesTo {
+			principal: User,
+			resource: Document
+		};
+	`
+
+	s, err := schema.NewFromCedar("", []byte(schemaText))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	viewer := types.NewEntityUID("Role", "viewer")
+	alice := types.NewEntityUID("User", "alice")
+	doc := types.NewEntityUID("Document", "readme")
+	view := types.NewEntityUID("Action", "view")
+	edit := types.NewEntityUID("Action", "edit")
+	entities := types.EntityMap{
+		viewer: types.Entity{
+			UID: viewer,
+			Attributes: types.NewRecord(types.RecordMap{
+				"grants": types.NewSet(view),
+			}),
+		},
+		alice: types.Entity{
+			UID:        alice,
+			Attributes: types.NewRecord(types.RecordMap{"role": viewer}),
+		},
+		doc: types.Entity{UID: doc},
+	}
+
+	assertEntityValidationResult(t, ValidateEntities(s, entities), true, "")
+
+	bad := types.EntityMap{
+		viewer: types.Entity{
+			UID: viewer,
+			Attributes: types.NewRecord(types.RecordMap{
+				"grants": types.NewSet(alice),
+			}),
+		},
+	}
+	assertEntityValidationResult(t, ValidateEntities(s, bad), false, "")
+
+	var policy cedar.Policy
+	if err := policy.UnmarshalCedar([]byte(`permit(principal, action, resource) when { principal.role.grants.contains(action) };`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	policies := cedar.NewPolicySet()
+	policies.Add("grants", &policy)
+
+	if result := ValidatePolicies(s, policies); !result.Valid {
+		t.Fatalf("Expected valid policy, got errors: %v", result.Errors)
+	}
+
+	tests := []struct {
+		action types.EntityUID
+		want   types.Decision
+	}{
+		{view, types.Allow},
+		{edit, types.Deny},
+	}
+	for _, tt := range tests {
+		req := cedar.Request{Principal: alice, Action: tt.action, Resource: doc, Context: types.Record{}}
+		if got, _ := cedar.Authorize(policies, entities, req); got != tt.want {
+			t.Errorf("Authorize(%v) = %v, want %v", tt.action, got, tt.want)
+		}
+	}
+}
+
 // TestStrictEntityValidation tests that strict entity validation catches
 // undeclared attributes.
 func TestStrictEntityValidation(t *testing.T) {
@@ -673,6 +755,114 @@ func TestStrictEntityValidation(t *testing.T) {
 	}
 }
 
+// TestPerTypeStrictEntityValidation tests that per-type strictness settings
+// override the global one and leave open entity types alone.
+func TestPerTypeStrictEntityValidation(t *testing.T) {
+	schemaJSON := `{
+		"": {
+			"entityTypes": {
+				"User": {
+					"shape": {
+						"type": "Record",
+						"attributes": {
+							"name": {"type": "String", "required": true}
+						}
+					}
+				},
+				"Document": {
+					"shape": {
+						"type": "Record",
+						"attributes": {
+							"title": {"type": "String", "required": true}
+						}
+					}
+				},
+				"Device": {}
+			},
+			"actions": {}
+		}
+	}`
+
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	user := types.EntityUID{Type: "User", ID: "alice"}
+	doc := types.EntityUID{Type: "Document", ID: "readme"}
+	device := types.EntityUID{Type: "Device", ID: "laptop"}
+	entities := types.EntityMap{
+		user: types.Entity{
+			Attributes: types.NewRecord(types.RecordMap{
+				"name":  types.String("Alice"),
+				"extra": types.String("x"),
+			}),
+		},
+		doc: types.Entity{
+			Attributes: types.NewRecord(types.RecordMap{
+				"title": types.String("README"),
+				"extra": types.String("x"),
+			}),
+		},
+		device: types.Entity{
+			Attributes: types.NewRecord(types.RecordMap{
+				"extra": types.String("x"),
+			}),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        []ValidatorOption
+		wantInvalid []types.EntityUID
+	}{
+		{
+			name: "lenient by default",
+		},
+		{
+			name:        "strict for one type",
+			opts:        []ValidatorOption{WithStrictEntityValidationFor("User")},
+			wantInvalid: []types.EntityUID{user},
+		},
+		{
+			name:        "open type under global strict",
+			opts:        []ValidatorOption{WithStrictEntityValidation(), WithOpenEntityTypes("User")},
+			wantInvalid: []types.EntityUID{doc},
+		},
+		{
+			name: "later option wins",
+			opts: []ValidatorOption{
+				WithStrictEntityValidationFor("User", "Document"),
+				WithOpenEntityTypes("Document"),
+			},
+			wantInvalid: []types.EntityUID{user},
+		},
+		{
+			name: "open record stays open",
+			opts: []ValidatorOption{WithStrictEntityValidationFor("Device")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateEntities(s, entities, tt.opts...)
+			var got []types.EntityUID
+			for _, e := range result.Errors {
+				if e.Code != ErrUndeclaredAttribute {
+					t.Errorf("unexpected error: %v", e)
+				}
+				got = append(got, e.EntityUID)
+			}
+			if !slices.Equal(got, tt.wantInvalid) {
+				t.Errorf("errors for %v, want %v", got, tt.wantInvalid)
+			}
+			if result.Valid != (len(tt.wantInvalid) == 0) {
+				t.Errorf("Valid = %v, want %v", result.Valid, len(tt.wantInvalid) == 0)
+			}
+		})
+	}
+}
+
 // TestEntityValidationWithParents tests entity validation including parent relationships.
 func TestEntityValidationWithParents(t *testing.T) {
 	schemaJSON := `{
@@ -740,3 +930,194 @@ func TestEntityValidationWithParents(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateEntitiesGrouped(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Group;
+		entity Org;
+		entity User in [Group] { name: String, age: Long };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	robot := types.NewEntityUID("Robot", "r2")
+	entities := types.EntityMap{
+		alice: {
+			UID:        alice,
+			Attributes: types.NewRecord(types.RecordMap{"name": types.String("Alice"), "age": types.Long(30)}),
+			Parents:    types.NewEntityUIDSet(types.NewEntityUID("Group", "admins")),
+		},
+		bob: {
+			UID:        bob,
+			Attributes: types.NewRecord(types.RecordMap{"age": types.String("old"), "nickname": types.String("b")}),
+			Parents:    types.NewEntityUIDSet(types.NewEntityUID("Org", "acme")),
+		},
+		robot: {UID: robot},
+	}
+
+	got, err := ValidateEntitiesGrouped(s, entities, WithStrictEntityValidation())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected an entry per entity, got %v", got)
+	}
+	if len(got[alice]) != 0 {
+		t.Errorf("Expected alice to be valid, got %v", got[alice])
+	}
+
+	wantBob := map[ValidationErrorCode]string{
+		ErrMissingAttribute:    "required attribute name is missing",
+		ErrUnexpectedType:      "attribute age: expected Long, got string",
+		ErrUndeclaredAttribute: "attribute nickname is not declared in schema",
+		ErrInvalidParent:       "entity cannot be member of type Org",
+	}
+	if len(got[bob]) != len(wantBob) {
+		t.Fatalf("Expected %d errors for bob, got %v", len(wantBob), got[bob])
+	}
+	for _, e := range got[bob] {
+		if want, ok := wantBob[e.Code]; !ok || e.Message != want {
+			t.Errorf("Unexpected error for bob: %v", e)
+		}
+	}
+
+	if len(got[robot]) != 1 || got[robot][0].Code != ErrUnknownEntity {
+		t.Errorf("Expected unknown entity error for robot, got %v", got[robot])
+	}
+}
+
+func TestValidateEntitiesGroupedWithNilSchema(t *testing.T) {
+	got, err := ValidateEntitiesGrouped(nil, types.EntityMap{})
+	if err == nil {
+		t.Errorf("Expected error for nil schema, got %v", got)
+	}
+	if got != nil {
+		t.Errorf("Expected no grouped errors for nil schema, got %v", got)
+	}
+}
+
+func TestValidateEntityReferences(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Team;
+		entity User {
+			manager?: User,
+			reviewers?: Set<User>,
+			profile?: { team: Team },
+		};
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	alice := types.NewEntityUID("User", "alice")
+	bob := types.NewEntityUID("User", "bob")
+	ghost := types.NewEntityUID("User", "ghost")
+	team := types.NewEntityUID("Team", "core")
+	user := func(attrs types.RecordMap) types.Entity {
+		return types.Entity{Attributes: types.NewRecord(attrs)}
+	}
+
+	tests := []struct {
+		name     string
+		entities types.EntityMap
+		opts     []ValidatorOption
+		want     []ValidationErrorCode
+	}{
+		{
+			name: "existing references",
+			entities: types.EntityMap{
+				alice: user(types.RecordMap{
+					"manager":   bob,
+					"reviewers": types.NewSet(bob),
+					"profile":   types.NewRecord(types.RecordMap{"team": team}),
+				}),
+				bob:  user(nil),
+				team: {},
+			},
+			opts: []ValidatorOption{WithValidateEntityReferences()},
+		},
+		{
+			name:     "missing reference ignored by default",
+			entities: types.EntityMap{alice: user(types.RecordMap{"manager": ghost})},
+		},
+		{
+			name:     "missing reference",
+			entities: types.EntityMap{alice: user(types.RecordMap{"manager": ghost})},
+			opts:     []ValidatorOption{WithValidateEntityReferences()},
+			want:     []ValidationErrorCode{ErrMissingEntityReference},
+		},
+		{
+			name: "missing references nested in sets and records",
+			entities: types.EntityMap{
+				alice: user(types.RecordMap{
+					"reviewers": types.NewSet(bob, ghost),
+					"profile":   types.NewRecord(types.RecordMap{"team": team}),
+				}),
+				bob: user(nil),
+			},
+			opts: []ValidatorOption{WithValidateEntityReferences()},
+			want: []ValidationErrorCode{ErrMissingEntityReference, ErrMissingEntityReference},
+		},
+		{
+			name: "wrong type is a type error",
+			entities: types.EntityMap{
+				alice: user(types.RecordMap{"manager": team}),
+			},
+			opts: []ValidatorOption{WithValidateEntityReferences()},
+			want: []ValidationErrorCode{ErrUnexpectedType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateEntities(s, tt.entities, tt.opts...)
+			var got []ValidationErrorCode
+			for _, e := range result.Errors {
+				got = append(got, e.Code)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("error codes = %v, want %v (errors: %v)", got, tt.want, result.Errors)
+			}
+		})
+	}
+
+	t.Run("message names the reference", func(t *testing.T) {
+		entities := types.EntityMap{alice: user(types.RecordMap{"manager": ghost})}
+		result := ValidateEntities(s, entities, WithValidateEntityReferences())
+		if len(result.Errors) != 1 || result.Errors[0].EntityUID != alice ||
+			!strings.Contains(result.Errors[0].Message, `attribute manager: referenced entity User::"ghost" does not exist`) {
+			t.Errorf("unexpected errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("mixed set reports every element", func(t *testing.T) {
+		// The set holds a missing User and, wrongly, two Teams, one of them
+		// missing. The result must not depend on the order of the set.
+		entities := types.EntityMap{
+			alice: user(types.RecordMap{
+				"reviewers": types.NewSet(ghost, team, types.NewEntityUID("Team", "gone"), bob),
+			}),
+			bob: user(nil),
+		}
+		want := []string{
+			`missing_entity_reference attribute reviewers.element: referenced entity User::"ghost" does not exist`,
+			"unexpected_type attribute reviewers.element: expected Entity<User>, got (entity of type `Team`)",
+			"unexpected_type attribute reviewers.element: expected Entity<User>, got (entity of type `Team`)",
+		}
+		for range 20 {
+			result := ValidateEntities(s, entities, WithValidateEntityReferences())
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, string(e.Code)+" "+e.Message)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("errors = %q, want %q", got, want)
+			}
+		}
+	})
+}
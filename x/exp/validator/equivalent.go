@@ -0,0 +1,238 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Policy Equivalence
+// -----------------------------------------------------------------------------
+
+// PoliciesEquivalent reports whether p1 and p2 match exactly the same
+// requests allowed by the schema and have the same effect, so that swapping
+// one for the other never changes an authorization decision.
+//
+// Equivalence is undecidable in general, so the check is sound but
+// incomplete: a result of true is always correct, while false means the
+// policies could not be proven equivalent. For each request shape the schema
+// allows, each policy is reduced to the set of primitive conditions that must
+// all hold for it to match. Scope constraints, `when` and `unless` clauses,
+// conjunctions, negated disjunctions and negations are flattened, so
+// `when { a && b }` and `when { b && a }`, or `unless { !x }` and
+// `when { x }`, reduce to the same set. Each condition is then partially
+// evaluated with the action known and the entity types of the principal and
+// resource taken from the shape. Entity data other than the schema's action
+// hierarchy is unknown, so conditions such as `User::"a" in Group::"g"` are
+// compared as written. The policies are equivalent if the sets are
+// identical for every shape.
+//
+// An error is returned if either policy does not validate against the
+// schema, other than for being impossible.
+func (v *Validator) PoliciesEquivalent(p1, p2 *cedar.Policy) (bool, error) {
+	a1, err := v.equivalenceAST("first", p1)
+	if err != nil {
+		return false, err
+	}
+	a2, err := v.equivalenceAST("second", p2)
+	if err != nil {
+		return false, err
+	}
+
+	for _, shape := range v.schema.AllRequestShapes() {
+		c1, applies1 := v.shapeConjuncts(a1, shape)
+		c2, applies2 := v.shapeConjuncts(a2, shape)
+		if !applies1 && !applies2 {
+			continue
+		}
+		if applies1 != applies2 || a1.Effect != a2.Effect || !sameConjuncts(c1, c2) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// equivalenceAST validates policy and returns its AST, qualified against the
+// default namespace.
+func (v *Validator) equivalenceAST(which string, policy *cedar.Policy) (*ast.Policy, error) {
+	errs, _ := v.validatePolicy("", policy)
+	for _, e := range errs {
+		if !strings.HasPrefix(e.Message, "impossiblePolicy") {
+			return nil, fmt.Errorf("%s policy is not valid: %s", which, e.Message)
+		}
+	}
+	return v.qualifiedAST(policy), nil
+}
+
+// conjunct is a primitive condition of a policy: the policy matches only if
+// node evaluates, without error, to want.
+type conjunct struct {
+	node ast.IsNode
+	want bool
+}
+
+// shapeConjuncts returns the residual conditions p places on requests of the
+// given shape. It reports false if p can never match such a request.
+func (v *Validator) shapeConjuncts(p *ast.Policy, shape schema.RequestShape) ([]conjunct, bool) {
+	var residual []conjunct
+	for _, c := range splitConjuncts(eval.PolicyToNode(p).AsIsNode(), true, nil) {
+		if holds, decided := v.decideByType(c, shape); decided {
+			if !holds {
+				return nil, false
+			}
+			continue
+		}
+		c, holds, decided, _ := v.partialConjunct(shape.Action, c)
+		if decided {
+			if !holds {
+				return nil, false
+			}
+			continue
+		}
+		if !containsConjunct(residual, c) {
+			residual = append(residual, c)
+		}
+	}
+	return residual, true
+}
+
+// splitConjuncts appends to out the primitive conditions that must all hold
+// for n to evaluate to want. Because an erroring condition never lets a
+// policy match, the order in which they are evaluated does not matter.
+func splitConjuncts(n ast.IsNode, want bool, out []conjunct) []conjunct {
+	switch n := n.(type) {
+	case ast.NodeTypeNot:
+		return splitConjuncts(n.Arg, !want, out)
+	case ast.NodeTypeAnd:
+		if want {
+			return splitConjuncts(n.Right, true, splitConjuncts(n.Left, true, out))
+		}
+	case ast.NodeTypeOr:
+		if !want {
+			return splitConjuncts(n.Right, false, splitConjuncts(n.Left, false, out))
+		}
+	case ast.NodeTypeIsIn:
+		if want {
+			isNode := ast.NodeTypeIs{Left: n.Left, EntityType: n.EntityType}
+			inNode := ast.NodeTypeIn{BinaryNode: ast.BinaryNode{Left: n.Left, Right: n.Entity}}
+			return append(out, conjunct{node: isNode, want: true}, conjunct{node: inNode, want: true})
+		}
+	}
+	return append(out, conjunct{node: n, want: want})
+}
+
+// decideByType resolves conditions on the type of the principal or resource
+// using the entity types of the request shape.
+func (v *Validator) decideByType(c conjunct, shape schema.RequestShape) (holds, decided bool) {
+	switch n := c.node.(type) {
+	case ast.NodeTypeIs:
+		if t, ok := shapeVariableType(n.Left, shape); ok {
+			return (t == n.EntityType) == c.want, true
+		}
+	case ast.NodeTypeIn:
+		t, ok := shapeVariableType(n.Left, shape)
+		target, isUID := n.Right.(ast.NodeValue)
+		if !ok || !isUID || !c.want {
+			return false, false
+		}
+		if uid, ok := target.Value.(types.EntityUID); ok && uid.Type != t &&
+			!v.canBeDescendantOf(t, uid.Type, make(map[types.EntityType]bool)) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// shapeVariableType returns the entity type of n in the request shape if n is
+// the principal or resource variable.
+func shapeVariableType(n ast.IsNode, shape schema.RequestShape) (types.EntityType, bool) {
+	variable, ok := n.(ast.NodeTypeVariable)
+	if !ok {
+		return "", false
+	}
+	switch variable.Name {
+	case "principal":
+		return shape.PrincipalType, true
+	case "resource":
+		return shape.ResourceType, true
+	}
+	return "", false
+}
+
+// partialConjunct partially evaluates c for the given action. If the result
+// is known, decided is true and holds reports whether c is satisfied; a
+// condition that always errors is never satisfied, and err is its error.
+// Otherwise the residual condition is returned. A condition whose result
+// depends on the data of an entity other than an action is returned
+// unchanged, since that data is not known.
+func (v *Validator) partialConjunct(action types.EntityUID, c conjunct) (residual conjunct, holds, decided bool, err error) {
+	env, entities := v.partialEnv(action)
+	p := &ast.Policy{
+		Effect:     ast.EffectPermit,
+		Principal:  ast.ScopeTypeAll{},
+		Action:     ast.ScopeTypeAll{},
+		Resource:   ast.ScopeTypeAll{},
+		Conditions: []ast.ConditionType{{Condition: ast.Condition(c.want), Body: c.node}},
+	}
+	out, keep := eval.PartialPolicy(env, p)
+	switch {
+	case entities.unknown:
+		return c, false, false, nil
+	case !keep:
+		return c, false, true, nil
+	case len(out.Conditions) == 0:
+		return c, true, true, nil
+	}
+	body := out.Conditions[0].Body
+	if err, isErr := eval.ToPartialError(body); isErr {
+		return c, false, true, err
+	}
+	if neverHolds(out.Conditions[0]) {
+		return c, false, true, nil
+	}
+	return conjunct{node: body, want: c.want}, false, false, nil
+}
+
+func containsConjunct(cs []conjunct, c conjunct) bool {
+	for _, x := range cs {
+		if x.want == c.want && reflect.DeepEqual(x.node, c.node) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameConjuncts reports whether a and b, which hold no duplicates, contain
+// the same conditions.
+func sameConjuncts(a, b []conjunct) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, c := range a {
+		if !containsConjunct(b, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestPoliciesEquivalent(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Group;
+		entity User in [Group] { age: Long, active: Bool };
+		entity Doc { public: Bool };
+		action read;
+		action view in [read] appliesTo { principal: User, resource: Doc, context: { level: Long } };
+		action edit appliesTo { principal: User, resource: Doc };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		p1, p2 string
+		want   bool
+	}{
+		{"identical", `permit(principal, action, resource) when { principal.active };`, `permit(principal, action, resource) when { principal.active };`, true},
+		{"commuted conjunction", `permit(principal, action, resource) when { principal.active && resource.public };`, `permit(principal, action, resource) when { resource.public && principal.active };`, true},
+		{"split conditions", `permit(principal, action, resource) when { principal.active && resource.public };`, `permit(principal, action, resource) when { resource.public } when { principal.active };`, true},
+		{"unless negation", `permit(principal, action, resource) unless { !principal.active };`, `permit(principal, action, resource) when { principal.active };`, true},
+		{"de morgan", `permit(principal, action, resource) unless { !principal.active || !resource.public };`, `permit(principal, action, resource) when { principal.active && resource.public };`, true},
+		{"scope versus condition", `permit(principal == User::"alice", action, resource);`, `permit(principal, action, resource) when { principal == User::"alice" };`, true},
+		{"redundant type test", `permit(principal is User, action, resource);`, `permit(principal, action, resource);`, true},
+		{"action group", `permit(principal, action in Action::"read", resource);`, `permit(principal, action == Action::"view", resource);`, true},
+		{"constant condition", `permit(principal, action, resource) when { 1 < 2 && principal.active };`, `permit(principal, action, resource) when { principal.active };`, true},
+		{"impossible policies", `permit(principal, action, resource) when { false };`, `permit(principal in Doc::"d", action, resource);`, true},
+		{"different effect", `permit(principal, action, resource);`, `forbid(principal, action, resource);`, false},
+		{"different actions", `permit(principal, action == Action::"view", resource);`, `permit(principal, action == Action::"edit", resource);`, false},
+		{"extra condition", `permit(principal, action, resource) when { principal.active };`, `permit(principal, action, resource) when { principal.active && resource.public };`, false},
+		{"negated conjunction", `permit(principal, action, resource) unless { principal.active && resource.public };`, `permit(principal, action, resource) unless { resource.public } unless { principal.active };`, false},
+		{"disjunction", `permit(principal, action, resource) when { principal.active || resource.public };`, `permit(principal, action, resource) when { resource.public || principal.active };`, false},
+		{"entity data", `permit(principal, action, resource) when { User::"a" in Group::"g" };`, `permit(principal, action, resource) when { User::"b" in Group::"g" };`, false},
+		{"entity data and constant", `permit(principal, action, resource) when { User::"a" in Group::"g" && 1 < 2 };`, `permit(principal, action, resource) when { User::"a" in Group::"g" };`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p1, p2 cedar.Policy
+			if err := p1.UnmarshalCedar([]byte(tt.p1)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			if err := p2.UnmarshalCedar([]byte(tt.p2)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			got, err := PoliciesEquivalent(s, &p1, &p2)
+			if err != nil {
+				t.Fatalf("PoliciesEquivalent() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PoliciesEquivalent() = %v, want %v", got, tt.want)
+			}
+			if got, _ := PoliciesEquivalent(s, &p2, &p1); got != tt.want {
+				t.Errorf("PoliciesEquivalent() is not symmetric")
+			}
+		})
+	}
+}
+
+func TestPoliciesEquivalentInvalidPolicy(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User { age: Long };
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	var valid, invalid cedar.Policy
+	if err := valid.UnmarshalCedar([]byte(`permit(principal, action, resource);`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if err := invalid.UnmarshalCedar([]byte(`permit(principal, action, resource) when { principal.age == "x" };`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	_, err = PoliciesEquivalent(s, &valid, &invalid)
+	if err == nil || !strings.Contains(err.Error(), "second policy is not valid") {
+		t.Errorf("Expected an invalid policy error, got %v", err)
+	}
+}
@@ -33,6 +33,14 @@ const (
 	// ErrInvalidScope indicates an invalid scope constraint in a policy.
 	ErrInvalidScope ValidationErrorCode = "invalid_scope"
 
+	// ErrMissingAnnotation indicates a policy without an annotation required by
+	// RequireAnnotations.
+	ErrMissingAnnotation ValidationErrorCode = "missing_annotation"
+
+	// ErrMismatchedIDAnnotation indicates a policy whose @id annotation differs
+	// from its ID in the policy set, when RequireAnnotations requires @id.
+	ErrMismatchedIDAnnotation ValidationErrorCode = "mismatched_id_annotation"
+
 	// Type errors
 
 	// ErrUnexpectedType indicates a type mismatch in an expression.
@@ -91,6 +99,23 @@ const (
 	// ErrUndeclaredAttribute indicates an attribute that is not declared in the schema
 	// (only reported in strict validation mode).
 	ErrUndeclaredAttribute ValidationErrorCode = "undeclared_attribute"
+
+	// ErrMissingEntityReference indicates an entity attribute that refers to an
+	// entity absent from the validated entities (only reported with
+	// WithValidateEntityReferences). A reference of the wrong entity type is
+	// reported as ErrUnexpectedType instead.
+	ErrMissingEntityReference ValidationErrorCode = "missing_entity_reference"
+
+	// Schema lint findings
+
+	// ErrUnreferencedEntityType indicates an entity type that no action, attribute,
+	// tag, or memberOfTypes declaration refers to.
+	ErrUnreferencedEntityType ValidationErrorCode = "unreferenced_entity_type"
+
+	// ErrInconsistentCommonType indicates a common type that is used for both
+	// required and optional attributes, or both as an action context and
+	// elsewhere.
+	ErrInconsistentCommonType ValidationErrorCode = "inconsistent_common_type"
 )
 
 // ValidationError provides structured error information for validation failures.
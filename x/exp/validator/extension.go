@@ -0,0 +1,86 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// extSignature describes the parameter and return types of a built-in
+// extension function or method. For methods, the first parameter is the
+// receiver.
+type extSignature struct {
+	params   []schema.CedarType
+	ret      schema.CedarType
+	isMethod bool
+	// literal, if set, validates a string literal passed to a constructor.
+	literal func(string) bool
+}
+
+var (
+	ipaddrType   = schema.ExtensionType{Name: "ipaddr"}
+	decimalType  = schema.ExtensionType{Name: "decimal"}
+	datetimeType = schema.ExtensionType{Name: "datetime"}
+	durationType = schema.ExtensionType{Name: "duration"}
+)
+
+func extFunction(param, ret schema.CedarType, literal func(string) bool) extSignature {
+	return extSignature{params: []schema.CedarType{param}, ret: ret, literal: literal}
+}
+
+func extMethod(ret schema.CedarType, params ...schema.CedarType) extSignature {
+	return extSignature{params: params, ret: ret, isMethod: true}
+}
+
+// extSignatures holds the signature of every built-in extension function and
+// method, keyed by name.
+var extSignatures = map[string]extSignature{
+	// IP address constructor: ip(String) -> ipaddr
+	"ip":     extFunction(schema.StringType{}, ipaddrType, isValidIPLiteral),
+	"ipaddr": extFunction(schema.StringType{}, ipaddrType, isValidIPLiteral),
+
+	// IP address methods: ipaddr.isIpv4() -> Bool, ipaddr.isInRange(ipaddr) -> Bool
+	"isIpv4":      extMethod(schema.BoolType{}, ipaddrType),
+	"isIpv6":      extMethod(schema.BoolType{}, ipaddrType),
+	"isLoopback":  extMethod(schema.BoolType{}, ipaddrType),
+	"isMulticast": extMethod(schema.BoolType{}, ipaddrType),
+	"isInRange":   extMethod(schema.BoolType{}, ipaddrType, ipaddrType),
+
+	// Decimal constructor and comparisons: decimal.lessThan(decimal) -> Bool
+	"decimal":            extFunction(schema.StringType{}, decimalType, isValidDecimalLiteral),
+	"lessThan":           extMethod(schema.BoolType{}, decimalType, decimalType),
+	"lessThanOrEqual":    extMethod(schema.BoolType{}, decimalType, decimalType),
+	"greaterThan":        extMethod(schema.BoolType{}, decimalType, decimalType),
+	"greaterThanOrEqual": extMethod(schema.BoolType{}, decimalType, decimalType),
+
+	// Datetime and duration constructors
+	"datetime": extFunction(schema.StringType{}, datetimeType, isValidDatetimeLiteral),
+	"duration": extFunction(schema.StringType{}, durationType, isValidDurationLiteral),
+
+	// Datetime methods: datetime.offset(duration) -> datetime,
+	// datetime.durationSince(datetime) -> duration, datetime.toDate() -> datetime,
+	// datetime.toTime() -> duration
+	"offset":        extMethod(datetimeType, datetimeType, durationType),
+	"durationSince": extMethod(durationType, datetimeType, datetimeType),
+	"toDate":        extMethod(datetimeType, datetimeType),
+	"toTime":        extMethod(durationType, datetimeType),
+
+	// Duration conversions: duration.toDays() -> Long
+	"toDays":         extMethod(schema.LongType{}, durationType),
+	"toHours":        extMethod(schema.LongType{}, durationType),
+	"toMinutes":      extMethod(schema.LongType{}, durationType),
+	"toSeconds":      extMethod(schema.LongType{}, durationType),
+	"toMilliseconds": extMethod(schema.LongType{}, durationType),
+}
@@ -0,0 +1,44 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/internal/extensions"
+	"github.com/cedar-policy/cedar-go/types"
+)
+
+// TestExtSignaturesMatchExtMap keeps the signature table in step with the
+// extensions the evaluator implements.
+func TestExtSignaturesMatchExtMap(t *testing.T) {
+	for name, info := range extensions.ExtMap {
+		sig, ok := extSignatures[string(name)]
+		if !ok {
+			t.Errorf("extension %s has no signature", name)
+			continue
+		}
+		if len(sig.params) != info.Args || sig.isMethod != info.IsMethod {
+			t.Errorf("extension %s: signature has %d params, method %v; evaluator has %d args, method %v",
+				name, len(sig.params), sig.isMethod, info.Args, info.IsMethod)
+		}
+	}
+	for name := range extSignatures {
+		// ipaddr is accepted as an alias of the ip constructor.
+		if _, ok := extensions.ExtMap[types.Path(name)]; !ok && name != "ipaddr" {
+			t.Errorf("signature %s has no extension", name)
+		}
+	}
+}
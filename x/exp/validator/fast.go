@@ -0,0 +1,212 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Single-Policy Validation
+// -----------------------------------------------------------------------------
+
+// ValidatePolicyFast validates a single policy against the part of the schema
+// it references, for interactive use such as validating a policy on every
+// keystroke in an editor. Errors and warnings are reported with an empty
+// PolicyID.
+//
+// The policy is fully typechecked, but only against the actions named by its
+// action scope, together with the actions in the named groups, and the entity
+// types reachable from the policy and those actions through appliesTo,
+// attribute and context types, and memberOfTypes. When the action scope is
+// unconstrained the whole schema is used. Schema well-formedness is checked
+// once by [New] and the check for a schema without any usable action still
+// covers the whole schema, but checks that would need the rest of the type
+// tables are limited to the subset:
+//
+//   - entity types outside the subset are treated as undeclared; every type
+//     the policy names, or reaches through its actions' appliesTo and through
+//     attribute, context and memberOfTypes declarations, is in the subset
+//   - unqualified names are resolved against the default namespace using
+//     only the subset
+//
+// Use [Validator.ValidatePolicies] for the complete result.
+func (v *Validator) ValidatePolicyFast(policy *cedar.Policy) PolicyValidationResult {
+	p := (*ast.Policy)(policy.AST())
+	sub := v.subsetFor(p)
+	errs, warnings := sub.checkPolicy("", policy)
+	return PolicyValidationResult{Valid: len(errs) == 0, Errors: errs, Warnings: warnings}
+}
+
+// subsetFor returns a copy of v whose action and entity type tables only hold
+// the declarations p can depend on. If p's action scope is unconstrained, v
+// itself is returned.
+func (v *Validator) subsetFor(p *ast.Policy) *Validator {
+	qualified := p
+	if v.defaultNamespace != "" {
+		qualified, _ = v.qualifyPolicy(p)
+	}
+	var targets []types.EntityUID
+	switch s := qualified.Action.(type) {
+	case ast.ScopeTypeEq:
+		targets = []types.EntityUID{s.Entity}
+	case ast.ScopeTypeIn:
+		targets = []types.EntityUID{s.Entity}
+	case ast.ScopeTypeInSet:
+		targets = s.Entities
+	default:
+		return v
+	}
+
+	sub := *v
+	sub.actionTypes = make(map[types.EntityUID]*schema.ActionTypeInfo)
+	sub.entityTypes = make(map[types.EntityType]*schema.EntityTypeInfo)
+	c := &subsetCollector{v: v, sub: &sub}
+	for _, uid := range v.actionUIDsIn(targets) {
+		c.action(uid)
+	}
+	c.policy(p)
+	if qualified != p {
+		c.policy(qualified)
+	}
+	return &sub
+}
+
+// subsetCollector copies the declarations reachable from a policy into sub.
+type subsetCollector struct {
+	v   *Validator
+	sub *Validator
+}
+
+func (c *subsetCollector) action(uid types.EntityUID) {
+	info, ok := c.v.actionTypes[uid]
+	if !ok {
+		return
+	}
+	if _, seen := c.sub.actionTypes[uid]; seen {
+		return
+	}
+	c.sub.actionTypes[uid] = info
+	for _, t := range info.PrincipalTypes {
+		c.entityType(t)
+	}
+	for _, t := range info.ResourceTypes {
+		c.entityType(t)
+	}
+	c.cedarType(info.Context)
+}
+
+// entityType copies t, its ancestors and the entity types of its attributes.
+// Under a default namespace, the qualified variant of an unqualified name is
+// copied as well so that ambiguity is still detected.
+func (c *subsetCollector) entityType(t types.EntityType) {
+	if c.v.defaultNamespace != "" && !strings.Contains(string(t), "::") {
+		c.entityType(types.EntityType(c.v.defaultNamespace + "::" + string(t)))
+	}
+	info, ok := c.v.entityTypes[t]
+	if !ok {
+		return
+	}
+	if _, seen := c.sub.entityTypes[t]; seen {
+		return
+	}
+	c.sub.entityTypes[t] = info
+	for _, parent := range info.MemberOfTypes {
+		c.entityType(parent)
+	}
+	for _, attr := range info.Attributes {
+		c.cedarType(attr.Type)
+	}
+}
+
+func (c *subsetCollector) cedarType(t schema.CedarType) {
+	switch t := t.(type) {
+	case schema.EntityCedarType:
+		c.entityType(types.EntityType(t.Name))
+	case schema.SetType:
+		c.cedarType(t.Element)
+	case schema.RecordType:
+		for _, attr := range t.Attributes {
+			c.cedarType(attr.Type)
+		}
+	}
+}
+
+// policy copies the entity types and actions named in p's scope and
+// conditions.
+func (c *subsetCollector) policy(p *ast.Policy) {
+	c.scope(p.Principal)
+	c.scope(p.Action)
+	c.scope(p.Resource)
+	for _, cond := range p.Conditions {
+		ast.Inspect(ast.NewNode(cond.Body), func(n ast.IsNode) bool {
+			switch n := n.(type) {
+			case ast.NodeValue:
+				c.value(n.Value)
+			case ast.NodeTypeIs:
+				c.entityType(n.EntityType)
+			case ast.NodeTypeIsIn:
+				c.entityType(n.EntityType)
+			}
+			return true
+		})
+	}
+}
+
+func (c *subsetCollector) scope(s ast.IsScopeNode) {
+	switch s := s.(type) {
+	case ast.ScopeTypeEq:
+		c.uid(s.Entity)
+	case ast.ScopeTypeIn:
+		c.uid(s.Entity)
+	case ast.ScopeTypeInSet:
+		for _, e := range s.Entities {
+			c.uid(e)
+		}
+	case ast.ScopeTypeIs:
+		c.entityType(s.Type)
+	case ast.ScopeTypeIsIn:
+		c.entityType(s.Type)
+		c.uid(s.Entity)
+	}
+}
+
+func (c *subsetCollector) uid(uid types.EntityUID) {
+	c.entityType(uid.Type)
+	c.action(uid)
+	if c.v.defaultNamespace != "" && !strings.Contains(string(uid.Type), "::") {
+		c.action(types.NewEntityUID(types.EntityType(c.v.defaultNamespace+"::"+string(uid.Type)), uid.ID))
+	}
+}
+
+func (c *subsetCollector) value(v types.Value) {
+	switch v := v.(type) {
+	case types.EntityUID:
+		c.uid(v)
+	case types.Set:
+		for e := range v.All() {
+			c.value(e)
+		}
+	case types.Record:
+		for _, e := range v.All() {
+			c.value(e)
+		}
+	}
+}
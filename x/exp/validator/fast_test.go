@@ -0,0 +1,130 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestValidatePolicyFast(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Org;
+		entity Team in [Org];
+		entity User in [Team] { manager: Manager, tags: Set<String> };
+		entity Manager { level: Long };
+		entity Doc { owner: User };
+		entity Invoice { amount: Long };
+		entity Auditor;
+		action read;
+		action view in [read] appliesTo { principal: User, resource: Doc, context: { via: Team } };
+		action edit in [read] appliesTo { principal: User, resource: Doc };
+		action pay appliesTo { principal: Auditor, resource: Invoice };
+		action archive;
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	v, err := New(s)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	policies := []string{
+		`permit(principal, action == Action::"view", resource) when { principal.manager.level > 1 };`,
+		`permit(principal in Org::"acme", action in Action::"read", resource) when { resource.owner == principal };`,
+		`permit(principal, action == Action::"view", resource) when { context.via in Org::"acme" };`,
+		`permit(principal, action in [Action::"view", Action::"pay"], resource);`,
+		`permit(principal, action == Action::"view", resource) when { principal.level > 1 };`,
+		`permit(principal, action == Action::"view", resource) when { principal.manager.level == "x" };`,
+		`permit(principal is Auditor, action == Action::"view", resource);`,
+		`permit(principal, action == Action::"view", resource) when { principal is Invoice };`,
+		`permit(principal, action == Action::"archive", resource);`,
+		`permit(principal, action == Action::"missing", resource);`,
+		`permit(principal, action, resource) when { resource has amount };`,
+	}
+	for _, src := range policies {
+		t.Run(src, func(t *testing.T) {
+			var policy cedar.Policy
+			if err := policy.UnmarshalCedar([]byte(src)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			ps := cedar.NewPolicySet()
+			ps.Add("", &policy)
+			want := v.ValidatePolicies(ps)
+			got := v.ValidatePolicyFast(&policy)
+			if got.Valid != want.Valid || !slices.Equal(messages(got.Errors), messages(want.Errors)) {
+				t.Errorf("ValidatePolicyFast() = %v %v, want %v %v", got.Valid, got.Errors, want.Valid, want.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatePolicyFastSubset(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Org;
+		entity User in [Org] { manager: Manager };
+		entity Manager;
+		entity Doc;
+		entity Invoice;
+		action view appliesTo { principal: User, resource: Doc };
+		action pay appliesTo { principal: User, resource: Invoice };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	v, err := New(s)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	var policy cedar.Policy
+	if err := policy.UnmarshalCedar([]byte(`permit(principal, action == Action::"view", resource);`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	sub := v.subsetFor((*ast.Policy)(policy.AST()))
+	if _, ok := sub.actionTypes[types.NewEntityUID("Action", "pay")]; ok {
+		t.Error("Expected unreferenced action to be excluded")
+	}
+	if _, ok := sub.entityTypes["Invoice"]; ok {
+		t.Error("Expected unreferenced entity type to be excluded")
+	}
+	for _, et := range []types.EntityType{"User", "Org", "Manager", "Doc"} {
+		if _, ok := sub.entityTypes[et]; !ok {
+			t.Errorf("Expected %s in the subset", et)
+		}
+	}
+
+	if err := policy.UnmarshalCedar([]byte(`permit(principal, action, resource);`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if sub := v.subsetFor((*ast.Policy)(policy.AST())); sub != v {
+		t.Error("Expected the full schema for an unconstrained action scope")
+	}
+}
+
+func messages(errs []PolicyError) []string {
+	out := make([]string, len(errs))
+	for i, e := range errs {
+		out[i] = e.Message
+	}
+	slices.Sort(out)
+	return out
+}
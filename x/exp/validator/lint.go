@@ -0,0 +1,276 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/resolved"
+)
+
+// -----------------------------------------------------------------------------
+// Schema Linting
+// -----------------------------------------------------------------------------
+
+// SchemaLintFinding reports a hygiene issue in a schema. Unlike validation
+// errors, findings do not make the schema unusable.
+type SchemaLintFinding struct {
+	// EntityType is the entity type the finding is about, if any.
+	EntityType types.EntityType
+	// CommonType is the fully qualified common type the finding is about, if
+	// any, and Usages lists, sorted, the declarations that use it in
+	// conflicting ways.
+	CommonType types.Path
+	Usages     []string
+	Message    string
+	Code       ValidationErrorCode
+}
+
+// LintSchema reports hygiene issues in a well-formed schema: first the
+// unreferenced entity types, sorted by entity type, then the inconsistently
+// used common types, sorted by common type.
+//
+// An entity type is unreferenced if it is never a principal or resource type
+// of an action, never the type of an attribute, tag, or context attribute, and
+// never listed in memberOfTypes. Such types cannot appear in any request or
+// entity data that involves the rest of the schema. Types used only as
+// ancestors of other types are referenced through memberOfTypes and are not
+// reported, and action entity types are never reported.
+//
+// A common type is used inconsistently if it is the type of both required and
+// optional attributes, or if it is both the context type of an action and
+// used elsewhere, for example as an attribute type. Either usually means that
+// a shared type is being bent to fit unrelated declarations.
+func LintSchema(s *schema.Schema) ([]SchemaLintFinding, error) {
+	rs, err := s.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[types.EntityType]bool)
+	for _, e := range rs.Entities {
+		for _, pt := range e.ParentTypes {
+			refs[pt] = true
+		}
+		collectTypeRefs(refs, e.Shape)
+		collectTypeRefs(refs, e.Tags)
+	}
+	for _, a := range rs.Actions {
+		if a.AppliesTo == nil {
+			continue
+		}
+		for _, et := range a.AppliesTo.Principals {
+			refs[et] = true
+		}
+		for _, et := range a.AppliesTo.Resources {
+			refs[et] = true
+		}
+		collectTypeRefs(refs, a.AppliesTo.Context)
+	}
+
+	var declared []types.EntityType
+	for et := range rs.Entities {
+		declared = append(declared, et)
+	}
+	for et := range rs.Enums {
+		declared = append(declared, et)
+	}
+	slices.Sort(declared)
+
+	var findings []SchemaLintFinding
+	for _, et := range declared {
+		if refs[et] {
+			continue
+		}
+		findings = append(findings, SchemaLintFinding{
+			EntityType: et,
+			Message:    fmt.Sprintf("entity type %s is unreferenced: no action, attribute, tag, or memberOfTypes declaration uses it", et),
+			Code:       ErrUnreferencedEntityType,
+		})
+	}
+	commonFindings, err := lintCommonTypes(s.AST())
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, commonFindings...), nil
+}
+
+// collectTypeRefs records the entity types that appear anywhere within t.
+func collectTypeRefs(refs map[types.EntityType]bool, t resolved.IsType) {
+	switch t := t.(type) {
+	case resolved.EntityType:
+		refs[types.EntityType(t)] = true
+	case resolved.SetType:
+		collectTypeRefs(refs, t.Element)
+	case resolved.RecordType:
+		for _, attr := range t {
+			collectTypeRefs(refs, attr.Type)
+		}
+	}
+}
+
+// commonTypeUsage describes one place where a common type is used.
+type commonTypeUsage struct {
+	// where names the declaration and, for attributes, the attribute path,
+	// such as "entity User attribute home.address".
+	where string
+	kind  usageKind
+}
+
+type usageKind int
+
+const (
+	usageOther usageKind = iota
+	usageRequired
+	usageOptional
+	usageContext
+)
+
+// commonTypeUses collects the usages of the common types of a schema.
+type commonTypeUses struct {
+	lookup func(ns types.Path, ref ast.TypeRef) (types.Path, bool)
+	uses   map[types.Path][]commonTypeUsage
+}
+
+// lintCommonTypes reports the common types of s that are used
+// inconsistently, as described in LintSchema.
+func lintCommonTypes(s *ast.Schema) ([]SchemaLintFinding, error) {
+	lookup, err := resolved.CommonTypeLookup(s)
+	if err != nil {
+		return nil, err
+	}
+	c := commonTypeUses{lookup: lookup, uses: map[types.Path][]commonTypeUsage{}}
+	namespaces := map[types.Path]ast.Namespace{
+		"": {Entities: s.Entities, Enums: s.Enums, Actions: s.Actions, CommonTypes: s.CommonTypes},
+	}
+	maps.Copy(namespaces, s.Namespaces)
+	for ns, n := range namespaces {
+		c.collectNamespace(ns, n)
+	}
+
+	var findings []SchemaLintFinding
+	for _, name := range slices.Sorted(maps.Keys(c.uses)) {
+		if f, ok := commonTypeFinding(name, c.uses[name]); ok {
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+func (c commonTypeUses) collectNamespace(ns types.Path, n ast.Namespace) {
+	for name, e := range n.Entities {
+		owner := "entity " + string(qualify(ns, string(name)))
+		c.collect(ns, e.Shape, commonTypeUsage{where: owner})
+		if e.Tags != nil {
+			c.collect(ns, e.Tags, commonTypeUsage{where: owner + " tags"})
+		}
+	}
+	for name, a := range n.Actions {
+		if a.AppliesTo != nil && a.AppliesTo.Context != nil {
+			where := fmt.Sprintf("action %s context", types.NewEntityUID(types.EntityType(qualify(ns, "Action")), name))
+			c.collect(ns, a.AppliesTo.Context, commonTypeUsage{where: where, kind: usageContext})
+		}
+	}
+	for name, ct := range n.CommonTypes {
+		c.collect(ns, ct.Type, commonTypeUsage{where: "common type " + string(qualify(ns, string(name)))})
+	}
+}
+
+// collect records the common types referenced by t, which is used as
+// described by u. The definitions of referenced common types are collected
+// separately, so references are not followed.
+func (c commonTypeUses) collect(ns types.Path, t ast.IsType, u commonTypeUsage) {
+	switch t := t.(type) {
+	case ast.TypeRef:
+		if name, ok := c.lookup(ns, t); ok {
+			c.uses[name] = append(c.uses[name], u)
+		}
+	case ast.SetType:
+		c.collect(ns, t.Element, commonTypeUsage{where: u.where + " element"})
+	case ast.RecordType:
+		prefix := u.where + " attribute "
+		if strings.Contains(u.where, " attribute ") {
+			prefix = u.where + "."
+		}
+		for name, attr := range t {
+			kind := usageRequired
+			if attr.Optional {
+				kind = usageOptional
+			}
+			c.collect(ns, attr.Type, commonTypeUsage{where: prefix + string(name), kind: kind})
+		}
+	}
+}
+
+func commonTypeFinding(name types.Path, uses []commonTypeUsage) (SchemaLintFinding, bool) {
+	has := map[usageKind]bool{}
+	for _, u := range uses {
+		has[u.kind] = true
+	}
+	var problem string
+	var conflicting func(commonTypeUsage) bool
+	switch {
+	case has[usageRequired] && has[usageOptional]:
+		problem = "is the type of both required and optional attributes"
+		conflicting = func(u commonTypeUsage) bool { return u.kind == usageRequired || u.kind == usageOptional }
+	case has[usageContext] && len(has) > 1:
+		problem = "is both an action context type and used elsewhere"
+		conflicting = func(commonTypeUsage) bool { return true }
+	default:
+		return SchemaLintFinding{}, false
+	}
+
+	usages := describeUsages(uses, conflicting)
+	return SchemaLintFinding{
+		CommonType: name,
+		Usages:     usages,
+		Message:    fmt.Sprintf("common type %s %s: %s", name, problem, strings.Join(usages, "; ")),
+		Code:       ErrInconsistentCommonType,
+	}, true
+}
+
+// describeUsages returns, sorted, the descriptions of the usages that keep
+// reports, noting the required-ness of attributes.
+func describeUsages(uses []commonTypeUsage, keep func(commonTypeUsage) bool) []string {
+	var usages []string
+	for _, u := range uses {
+		if !keep(u) {
+			continue
+		}
+		desc := u.where
+		switch u.kind {
+		case usageRequired:
+			desc += " (required)"
+		case usageOptional:
+			desc += " (optional)"
+		}
+		usages = append(usages, desc)
+	}
+	slices.Sort(usages)
+	return usages
+}
+
+func qualify(ns types.Path, name string) types.Path {
+	if ns == "" {
+		return types.Path(name)
+	}
+	return types.Path(string(ns) + "::" + name)
+}
@@ -0,0 +1,189 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestLintSchemaUnreferencedEntityTypes(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Org;
+		entity Group in [Org];
+		entity User in [Group] { address: Address };
+		entity Address;
+		entity Doc { tags: Set<Tag> } tags Label;
+		entity Tag;
+		entity Label;
+		entity Device;
+		entity Legacy in [Group] { owner: User };
+		entity Color enum ["red", "blue"];
+		entity Status enum ["open"];
+		action view appliesTo { principal: User, resource: Doc, context: { device: Device } };
+		action audit in [view];
+		namespace App {
+			entity Unused;
+			action "do" appliesTo { principal: User, resource: Doc, context: { color: Color } };
+		}
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	findings, err := LintSchema(s)
+	if err != nil {
+		t.Fatalf("LintSchema() error: %v", err)
+	}
+	var got []types.EntityType
+	for _, f := range findings {
+		if f.Code != ErrUnreferencedEntityType {
+			t.Errorf("unexpected code %q for %s", f.Code, f.EntityType)
+		}
+		if !strings.Contains(f.Message, string(f.EntityType)+" is unreferenced") {
+			t.Errorf("message %q should name the type and say it is unreferenced", f.Message)
+		}
+		got = append(got, f.EntityType)
+	}
+	want := []types.EntityType{"App::Unused", "Legacy", "Status"}
+	if len(got) != len(want) {
+		t.Fatalf("LintSchema() reported %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("LintSchema() reported %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestLintSchemaClean(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	findings, err := LintSchema(s)
+	if err != nil {
+		t.Fatalf("LintSchema() error: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+}
+
+func TestLintSchemaInconsistentCommonTypes(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		type Address = { street: String };
+		type Consistent = { level: Long };
+		type Shared = { ip: String };
+		entity User {
+			home: Address,
+			work?: Address,
+			profile: { billing: Address, rank: Consistent },
+		};
+		entity Doc { rank: Consistent, owners: Set<Address> };
+		entity Device { session: Shared };
+		action view appliesTo { principal: User, resource: Doc, context: Shared };
+		namespace App {
+			type Spot = { city: String };
+			entity Site { main: Spot, backup?: Spot };
+			entity Office { primary: Location, secondary?: Location };
+			type Location = { lat: Long };
+			action "do" appliesTo { principal: Site, resource: Site, context: { at: Location } };
+		}
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	findings, err := LintSchema(s)
+	if err != nil {
+		t.Fatalf("LintSchema() error: %v", err)
+	}
+	want := []SchemaLintFinding{
+		{
+			CommonType: "Address",
+			Usages: []string{
+				"entity User attribute home (required)",
+				"entity User attribute profile.billing (required)",
+				"entity User attribute work (optional)",
+			},
+		},
+		{
+			CommonType: "App::Location",
+			Usages: []string{
+				`action App::Action::"do" context attribute at (required)`,
+				"entity App::Office attribute primary (required)",
+				"entity App::Office attribute secondary (optional)",
+			},
+		},
+		{
+			CommonType: "App::Spot",
+			Usages:     []string{"entity App::Site attribute backup (optional)", "entity App::Site attribute main (required)"},
+		},
+		{
+			CommonType: "Shared",
+			Usages:     []string{`action Action::"view" context`, "entity Device attribute session (required)"},
+		},
+	}
+	var got []SchemaLintFinding
+	for _, f := range findings {
+		if f.Code != ErrInconsistentCommonType {
+			continue
+		}
+		if !strings.HasPrefix(f.Message, "common type "+string(f.CommonType)+" is ") {
+			t.Errorf("message %q should name the common type", f.Message)
+		}
+		for _, u := range f.Usages {
+			if !strings.Contains(f.Message, u) {
+				t.Errorf("message %q should list usage %q", f.Message, u)
+			}
+		}
+		got = append(got, SchemaLintFinding{CommonType: f.CommonType, Usages: f.Usages})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LintSchema() reported\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestLintSchemaCommonTypeShadowedByActionType(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		type Action = { id: String };
+		namespace App {
+			entity User { last: Action, next?: Action };
+			action view appliesTo { principal: User, resource: User };
+		}
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	findings, err := LintSchema(s)
+	if err != nil {
+		t.Fatalf("LintSchema() error: %v", err)
+	}
+	for _, f := range findings {
+		if f.Code == ErrInconsistentCommonType {
+			t.Errorf("App::Action refers to the action entity type, but LintSchema() reported %q", f.Message)
+		}
+	}
+}
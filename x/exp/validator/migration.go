@@ -0,0 +1,363 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Schema Migration
+// -----------------------------------------------------------------------------
+
+// SchemaChangeKind identifies a kind of schema change that can break policies.
+type SchemaChangeKind string
+
+const (
+	// ChangeEntityTypeRemoved indicates an entity type that is no longer declared.
+	ChangeEntityTypeRemoved SchemaChangeKind = "entity_type_removed"
+
+	// ChangeActionRemoved indicates an action that is no longer declared.
+	ChangeActionRemoved SchemaChangeKind = "action_removed"
+
+	// ChangeAppliesToNarrowed indicates an entity type that was removed from
+	// the principal or resource types of an action.
+	ChangeAppliesToNarrowed SchemaChangeKind = "applies_to_narrowed"
+
+	// ChangeAttributeRemoved indicates an entity or context attribute that is
+	// no longer declared.
+	ChangeAttributeRemoved SchemaChangeKind = "attribute_removed"
+
+	// ChangeAttributeTypeChanged indicates an entity or context attribute whose
+	// type changed.
+	ChangeAttributeTypeChanged SchemaChangeKind = "attribute_type_changed"
+
+	// ChangeAttributeOptional indicates a required entity or context attribute
+	// that became optional.
+	ChangeAttributeOptional SchemaChangeKind = "attribute_optional"
+)
+
+// SchemaChange describes one breaking difference between two schemas. A change
+// to an entity type sets EntityType; a change to an action, including its
+// context attributes, sets Action.
+type SchemaChange struct {
+	Kind       SchemaChangeKind
+	EntityType types.EntityType
+	Action     types.EntityUID
+	Attribute  string
+}
+
+// String describes the change, e.g. "attribute User.ssn removed".
+func (c SchemaChange) String() string {
+	owner := string(c.EntityType)
+	if c.Action != (types.EntityUID{}) {
+		owner = c.Action.String() + " context"
+	}
+	switch c.Kind {
+	case ChangeEntityTypeRemoved:
+		return fmt.Sprintf("entity type %s removed", c.EntityType)
+	case ChangeActionRemoved:
+		return fmt.Sprintf("action %s removed", c.Action)
+	case ChangeAppliesToNarrowed:
+		return fmt.Sprintf("action %s no longer applies to %s", c.Action, c.EntityType)
+	case ChangeAttributeRemoved:
+		return fmt.Sprintf("attribute %s.%s removed", owner, c.Attribute)
+	case ChangeAttributeTypeChanged:
+		return fmt.Sprintf("attribute %s.%s changed type", owner, c.Attribute)
+	default:
+		return fmt.Sprintf("attribute %s.%s became optional", owner, c.Attribute)
+	}
+}
+
+// MigrationBreakage describes a policy that validates under the old schema
+// but not under the new one.
+type MigrationBreakage struct {
+	PolicyID cedar.PolicyID
+	// Errors are the validation errors under the new schema.
+	Errors []PolicyError
+	// Changes are the schema changes the policy depends on, which explain
+	// the errors. It may be empty if the cause is not one of the tracked
+	// kinds of change.
+	Changes []SchemaChange
+}
+
+// MigrationReport is the result of CheckSchemaMigration.
+type MigrationReport struct {
+	// Broken lists, by policy ID, the policies broken by the migration.
+	Broken []MigrationBreakage
+	// Changes lists the breaking schema changes that at least one policy
+	// depends on, whether or not that policy still validates.
+	Changes []SchemaChange
+}
+
+// Compatible reports whether every policy that validated under the old schema
+// still validates under the new one.
+func (r MigrationReport) Compatible() bool {
+	return len(r.Broken) == 0
+}
+
+// checkSchemaMigration validates policies with both validators and attributes
+// each new failure to the schema changes the policy depends on. Policies that
+// were already invalid under the old schema are not reported as broken.
+func checkSchemaMigration(oldV, newV *Validator, policies *cedar.PolicySet) MigrationReport {
+	changes := schemaChanges(oldV, newV)
+	var report MigrationReport
+	used := map[SchemaChange]bool{}
+	ids := slices.Sorted(maps.Keys(policies.Map()))
+	for _, id := range ids {
+		policy := policies.Get(id)
+		deps := oldV.changesUsedBy((*ast.Policy)(policy.AST()), changes)
+		for _, c := range deps {
+			used[c] = true
+		}
+		if errs, _ := oldV.validatePolicy(id, policy); len(errs) > 0 {
+			continue
+		}
+		if errs, _ := newV.validatePolicy(id, policy); len(errs) > 0 {
+			report.Broken = append(report.Broken, MigrationBreakage{PolicyID: id, Errors: errs, Changes: deps})
+		}
+	}
+	for _, c := range changes {
+		if used[c] {
+			report.Changes = append(report.Changes, c)
+		}
+	}
+	return report
+}
+
+// schemaChanges returns, sorted, the breaking differences from oldV's schema
+// to newV's.
+func schemaChanges(oldV, newV *Validator) []SchemaChange {
+	var changes []SchemaChange
+	for et, oldInfo := range oldV.entityTypes {
+		newInfo, ok := newV.entityTypes[et]
+		if !ok {
+			changes = append(changes, SchemaChange{Kind: ChangeEntityTypeRemoved, EntityType: et})
+			continue
+		}
+		for _, c := range attributeChanges(oldInfo.Attributes, newInfo.Attributes) {
+			c.EntityType = et
+			changes = append(changes, c)
+		}
+	}
+	for action, oldInfo := range oldV.actionTypes {
+		newInfo, ok := newV.actionTypes[action]
+		if !ok {
+			changes = append(changes, SchemaChange{Kind: ChangeActionRemoved, Action: action})
+			continue
+		}
+		changes = append(changes, actionChanges(action, oldInfo, newInfo)...)
+	}
+	slices.SortFunc(changes, compareSchemaChanges)
+	return changes
+}
+
+func actionChanges(action types.EntityUID, oldInfo, newInfo *schema.ActionTypeInfo) []SchemaChange {
+	narrowed := map[types.EntityType]bool{}
+	for _, et := range oldInfo.PrincipalTypes {
+		if !slices.Contains(newInfo.PrincipalTypes, et) {
+			narrowed[et] = true
+		}
+	}
+	for _, et := range oldInfo.ResourceTypes {
+		if !slices.Contains(newInfo.ResourceTypes, et) {
+			narrowed[et] = true
+		}
+	}
+	var changes []SchemaChange
+	for et := range narrowed {
+		changes = append(changes, SchemaChange{Kind: ChangeAppliesToNarrowed, Action: action, EntityType: et})
+	}
+	for _, c := range attributeChanges(oldInfo.Context.Attributes, newInfo.Context.Attributes) {
+		c.Action = action
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// attributeChanges compares the top-level attributes of two declarations.
+func attributeChanges(oldAttrs, newAttrs map[string]schema.AttributeType) []SchemaChange {
+	var changes []SchemaChange
+	for name, oldAttr := range oldAttrs {
+		newAttr, ok := newAttrs[name]
+		switch {
+		case !ok:
+			changes = append(changes, SchemaChange{Kind: ChangeAttributeRemoved, Attribute: name})
+		case !sameType(oldAttr.Type, newAttr.Type):
+			changes = append(changes, SchemaChange{Kind: ChangeAttributeTypeChanged, Attribute: name})
+		case oldAttr.Required && !newAttr.Required:
+			changes = append(changes, SchemaChange{Kind: ChangeAttributeOptional, Attribute: name})
+		}
+	}
+	return changes
+}
+
+// sameType reports whether a and b are the same type. Annotations on nested
+// record attributes, such as @doc, do not change a type.
+func sameType(a, b schema.CedarType) bool {
+	switch a := a.(type) {
+	case schema.SetType:
+		b, ok := b.(schema.SetType)
+		return ok && sameType(a.Element, b.Element)
+	case schema.RecordType:
+		b, ok := b.(schema.RecordType)
+		if !ok || a.OpenRecord != b.OpenRecord || len(a.Attributes) != len(b.Attributes) {
+			return false
+		}
+		for name, attr := range a.Attributes {
+			other, ok := b.Attributes[name]
+			if !ok || attr.Required != other.Required || !sameType(attr.Type, other.Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compareSchemaChanges(a, b SchemaChange) int {
+	return cmp.Or(
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(a.EntityType, b.EntityType),
+		a.Action.Compare(b.Action),
+		cmp.Compare(a.Attribute, b.Attribute),
+	)
+}
+
+// policyRefs holds what a policy refers to, for matching against schema
+// changes. Attributes are matched by name, since the type of the entity they
+// are read from is not tracked.
+type policyRefs struct {
+	entityTypes map[types.EntityType]bool
+	actions     map[types.EntityUID]bool
+	allActions  bool
+	attributes  map[string]bool
+	context     map[string]bool
+}
+
+// changesUsedBy returns the changes that p depends on, in the order given.
+func (v *Validator) changesUsedBy(p *ast.Policy, changes []SchemaChange) []SchemaChange {
+	refs := v.policyRefs(p)
+	var used []SchemaChange
+	for _, c := range changes {
+		if refs.uses(c) {
+			used = append(used, c)
+		}
+	}
+	return used
+}
+
+func (r policyRefs) uses(c SchemaChange) bool {
+	switch c.Kind {
+	case ChangeEntityTypeRemoved:
+		return r.entityTypes[c.EntityType]
+	case ChangeActionRemoved:
+		return r.actions[c.Action]
+	case ChangeAppliesToNarrowed:
+		return r.entityTypes[c.EntityType] && (r.allActions || r.actions[c.Action])
+	}
+	if c.Action != (types.EntityUID{}) {
+		return r.context[c.Attribute] && (r.allActions || r.actions[c.Action])
+	}
+	return r.attributes[c.Attribute]
+}
+
+func (v *Validator) policyRefs(p *ast.Policy) policyRefs {
+	refs := policyRefs{
+		entityTypes: map[types.EntityType]bool{},
+		actions:     map[types.EntityUID]bool{},
+		attributes:  map[string]bool{},
+		context:     map[string]bool{},
+	}
+	refs.addScope(p.Principal)
+	refs.addScope(p.Resource)
+	refs.addActionScope(v, p.Action)
+	for _, cond := range p.Conditions {
+		ast.Inspect(ast.NewNode(cond.Body), func(n ast.IsNode) bool {
+			refs.addNode(n)
+			return true
+		})
+	}
+	return refs
+}
+
+func (r policyRefs) addScope(s ast.IsScopeNode) {
+	switch s := s.(type) {
+	case ast.ScopeTypeEq:
+		r.entityTypes[s.Entity.Type] = true
+	case ast.ScopeTypeIn:
+		r.entityTypes[s.Entity.Type] = true
+	case ast.ScopeTypeIs:
+		r.entityTypes[s.Type] = true
+	case ast.ScopeTypeIsIn:
+		r.entityTypes[s.Type] = true
+		r.entityTypes[s.Entity.Type] = true
+	}
+}
+
+// addActionScope records the actions the action scope names and those it
+// covers through memberOf in the old schema.
+func (r *policyRefs) addActionScope(v *Validator, s ast.IsActionScopeNode) {
+	var named []types.EntityUID
+	switch s := s.(type) {
+	case ast.ScopeTypeEq:
+		named = []types.EntityUID{s.Entity}
+	case ast.ScopeTypeIn:
+		named = []types.EntityUID{s.Entity}
+	case ast.ScopeTypeInSet:
+		named = s.Entities
+	default:
+		r.allActions = true
+		return
+	}
+	for _, uid := range slices.Concat(named, v.actionUIDsIn(named)) {
+		r.actions[uid] = true
+	}
+}
+
+func (r policyRefs) addNode(n ast.IsNode) {
+	switch n := n.(type) {
+	case ast.NodeTypeAccess:
+		r.addAttribute(n.Arg, string(n.Value))
+	case ast.NodeTypeHas:
+		r.addAttribute(n.Arg, string(n.Value))
+	case ast.NodeTypeIs:
+		r.entityTypes[n.EntityType] = true
+	case ast.NodeTypeIsIn:
+		r.entityTypes[n.EntityType] = true
+	case ast.NodeValue:
+		if uid, ok := n.Value.(types.EntityUID); ok {
+			r.entityTypes[uid.Type] = true
+			r.actions[uid] = true
+		}
+	}
+}
+
+func (r policyRefs) addAttribute(object ast.IsNode, name string) {
+	if v, ok := object.(ast.NodeTypeVariable); ok && v.Name == "context" {
+		r.context[name] = true
+		return
+	}
+	r.attributes[name] = true
+}
@@ -0,0 +1,146 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestCheckSchemaMigration(t *testing.T) {
+	oldSchema, err := schema.NewFromCedar("", []byte(`
+		entity User { dept: String, ssn: String, level: Long };
+		entity Doc;
+		entity Folder;
+		action view appliesTo { principal: User, resource: [Doc, Folder], context: { ip: String } };
+		action edit appliesTo { principal: User, resource: Doc };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	newSchema, err := schema.NewFromCedar("", []byte(`
+		entity User { dept?: String, level: String };
+		entity Doc;
+		entity Folder;
+		action view appliesTo { principal: User, resource: Doc };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+		permit(principal, action == Action::"view", resource) when { principal.ssn == "x" };
+		permit(principal, action == Action::"edit", resource);
+		permit(principal, action == Action::"view", resource is Folder);
+		permit(principal, action, resource) when { principal.level > 3 };
+		permit(principal, action == Action::"view", resource) when { context.ip == "10.0.0.1" };
+		permit(principal, action, resource) when { principal has dept && principal.dept == "eng" };
+		permit(principal, action, resource) when { principal.missing == 1 };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse policies: %v", err)
+	}
+
+	report, err := CheckSchemaMigration(oldSchema, newSchema, policies)
+	if err != nil {
+		t.Fatalf("CheckSchemaMigration() error = %v", err)
+	}
+	if report.Compatible() {
+		t.Fatal("Compatible() = true, want false")
+	}
+
+	view := types.NewEntityUID("Action", "view")
+	edit := types.NewEntityUID("Action", "edit")
+	wantBroken := map[cedar.PolicyID]SchemaChange{
+		"policy0": {Kind: ChangeAttributeRemoved, EntityType: "User", Attribute: "ssn"},
+		"policy1": {Kind: ChangeActionRemoved, Action: edit},
+		"policy2": {Kind: ChangeAppliesToNarrowed, Action: view, EntityType: "Folder"},
+		"policy3": {Kind: ChangeAttributeTypeChanged, EntityType: "User", Attribute: "level"},
+		"policy4": {Kind: ChangeAttributeRemoved, Action: view, Attribute: "ip"},
+	}
+	if len(report.Broken) != len(wantBroken) {
+		t.Fatalf("Broken = %+v, want %d policies", report.Broken, len(wantBroken))
+	}
+	for _, b := range report.Broken {
+		want, ok := wantBroken[b.PolicyID]
+		if !ok {
+			t.Errorf("unexpected broken policy %s: %v", b.PolicyID, b.Errors)
+			continue
+		}
+		if len(b.Errors) == 0 {
+			t.Errorf("policy %s has no errors", b.PolicyID)
+		}
+		if !slices.Equal(b.Changes, []SchemaChange{want}) {
+			t.Errorf("policy %s changes = %v, want [%v]", b.PolicyID, b.Changes, want)
+		}
+	}
+
+	wantChanges := []string{
+		`action Action::"edit" removed`,
+		`action Action::"view" no longer applies to Folder`,
+		`attribute User.dept became optional`,
+		`attribute Action::"view" context.ip removed`,
+		`attribute User.ssn removed`,
+		`attribute User.level changed type`,
+	}
+	var gotChanges []string
+	for _, c := range report.Changes {
+		gotChanges = append(gotChanges, c.String())
+	}
+	if !slices.Equal(gotChanges, wantChanges) {
+		t.Errorf("Changes = %q, want %q", gotChanges, wantChanges)
+	}
+
+	same, err := CheckSchemaMigration(oldSchema, oldSchema, policies)
+	if err != nil {
+		t.Fatalf("CheckSchemaMigration() error = %v", err)
+	}
+	if !same.Compatible() || len(same.Changes) != 0 {
+		t.Errorf("identical schemas: got %+v, want compatible with no changes", same)
+	}
+}
+
+func TestCheckSchemaMigrationIgnoresAnnotations(t *testing.T) {
+	oldSchema, err := schema.NewFromCedar("", []byte(`
+		entity User { info: { name: String, tags: Set<{ key: String }> } };
+		action view appliesTo { principal: User, resource: User, context: { device: { trusted: Bool } } };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	newSchema, err := schema.NewFromCedar("", []byte(`
+		entity User { info: { @doc("display name") name: String, tags: Set<{ @doc("tag key") key: String }> } };
+		action view appliesTo { principal: User, resource: User, context: { device: { @doc("managed device") trusted: Bool } } };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+		permit(principal, action, resource) when { principal.info.name == "a" && context.device.trusted };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse policies: %v", err)
+	}
+	report, err := CheckSchemaMigration(oldSchema, newSchema, policies)
+	if err != nil {
+		t.Fatalf("CheckSchemaMigration() error = %v", err)
+	}
+	if !report.Compatible() || len(report.Changes) != 0 {
+		t.Errorf("got %+v, want compatible with no changes", report)
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+)
+
+// -----------------------------------------------------------------------------
+// Default Namespace Resolution
+// -----------------------------------------------------------------------------
+
+// namespaceQualifier rewrites unqualified entity type references in a policy
+// so they resolve against the validator's default namespace. It collects an
+// error for each short name that is declared both with and without the
+// namespace.
+type namespaceQualifier struct {
+	v         *Validator
+	errs      []string
+	ambiguous map[string]bool
+}
+
+// qualifyPolicy returns a copy of p in which unqualified entity types that
+// are only declared in the default namespace are replaced by their qualified
+// names. The original policy is not modified.
+func (v *Validator) qualifyPolicy(p *ast.Policy) (*ast.Policy, []string) {
+	q := &namespaceQualifier{v: v, ambiguous: make(map[string]bool)}
+	out := *p
+	out.Principal = q.scope(p.Principal).(ast.IsPrincipalScopeNode)
+	out.Action = q.scope(p.Action).(ast.IsActionScopeNode)
+	out.Resource = q.scope(p.Resource).(ast.IsResourceScopeNode)
+	out.Conditions = make([]ast.ConditionType, len(p.Conditions))
+	for i, c := range p.Conditions {
+		out.Conditions[i] = ast.ConditionType{Condition: c.Condition, Body: q.node(c.Body)}
+	}
+	return &out, q.errs
+}
+
+func (q *namespaceQualifier) entityType(t types.EntityType) types.EntityType {
+	if strings.Contains(string(t), "::") {
+		return t
+	}
+	if resolved, ok := q.v.schema.ResolveEntityTypeIn(t, q.v.defaultNamespace); ok {
+		return resolved
+	}
+	// Resolution fails either because t is not declared at all, which the
+	// type checker reports, or because it is declared both as is and in the
+	// default namespace.
+	if _, declared := q.v.schema.ResolveEntityTypeIn(t, ""); declared {
+		q.reportAmbiguous(string(t), q.v.defaultNamespace+"::"+string(t))
+	}
+	return t
+}
+
+// uid qualifies the type of an entity UID. Action UIDs are resolved by the
+// action itself rather than by its type, since the empty namespace and the
+// default namespace may both declare actions.
+func (q *namespaceQualifier) uid(uid types.EntityUID) types.EntityUID {
+	if uid.Type != "Action" {
+		return types.NewEntityUID(q.entityType(uid.Type), uid.ID)
+	}
+	qualified := types.NewEntityUID(types.EntityType(q.v.defaultNamespace+"::Action"), uid.ID)
+	if _, ok := q.v.actionTypes[qualified]; !ok {
+		return uid
+	}
+	if _, ok := q.v.actionTypes[uid]; ok {
+		q.reportAmbiguous(uid.String(), qualified.String())
+		return uid
+	}
+	return qualified
+}
+
+func (q *namespaceQualifier) reportAmbiguous(short, qualified string) {
+	if q.ambiguous[short] {
+		return
+	}
+	q.ambiguous[short] = true
+	q.errs = append(q.errs, fmt.Sprintf("ambiguousType: %s could refer to %s or %s", short, short, qualified))
+}
+
+func (q *namespaceQualifier) scope(s ast.IsScopeNode) ast.IsScopeNode {
+	switch s := s.(type) {
+	case ast.ScopeTypeEq:
+		s.Entity = q.uid(s.Entity)
+		return s
+	case ast.ScopeTypeIn:
+		s.Entity = q.uid(s.Entity)
+		return s
+	case ast.ScopeTypeInSet:
+		entities := make([]types.EntityUID, len(s.Entities))
+		for i, e := range s.Entities {
+			entities[i] = q.uid(e)
+		}
+		s.Entities = entities
+		return s
+	case ast.ScopeTypeIs:
+		s.Type = q.entityType(s.Type)
+		return s
+	case ast.ScopeTypeIsIn:
+		s.Type = q.entityType(s.Type)
+		s.Entity = q.uid(s.Entity)
+		return s
+	}
+	return s
+}
+
+func (q *namespaceQualifier) value(v types.Value) types.Value {
+	switch v := v.(type) {
+	case types.EntityUID:
+		return q.uid(v)
+	case types.Set:
+		elems := make([]types.Value, 0, v.Len())
+		for e := range v.All() {
+			elems = append(elems, q.value(e))
+		}
+		return types.NewSet(elems...)
+	case types.Record:
+		m := make(types.RecordMap, v.Len())
+		for k, e := range v.All() {
+			m[k] = q.value(e)
+		}
+		return types.NewRecord(m)
+	}
+	return v
+}
+
+func (q *namespaceQualifier) binary(b ast.BinaryNode) ast.BinaryNode {
+	return ast.BinaryNode{Left: q.node(b.Left), Right: q.node(b.Right)}
+}
+
+func (q *namespaceQualifier) nodes(ns []ast.IsNode) []ast.IsNode {
+	out := make([]ast.IsNode, len(ns))
+	for i, n := range ns {
+		out[i] = q.node(n)
+	}
+	return out
+}
+
+func (q *namespaceQualifier) node(n ast.IsNode) ast.IsNode {
+	switch n := n.(type) {
+	case ast.NodeValue:
+		n.Value = q.value(n.Value)
+		return n
+	case ast.NodeTypeIs:
+		n.Left = q.node(n.Left)
+		n.EntityType = q.entityType(n.EntityType)
+		return n
+	case ast.NodeTypeIsIn:
+		n.Left = q.node(n.Left)
+		n.EntityType = q.entityType(n.EntityType)
+		n.Entity = q.node(n.Entity)
+		return n
+	case ast.NodeTypeIfThenElse:
+		n.If, n.Then, n.Else = q.node(n.If), q.node(n.Then), q.node(n.Else)
+		return n
+	case ast.NodeTypeExtensionCall:
+		n.Args = q.nodes(n.Args)
+		return n
+	case ast.NodeTypeSet:
+		n.Elements = q.nodes(n.Elements)
+		return n
+	case ast.NodeTypeRecord:
+		elems := make([]ast.RecordElementNode, len(n.Elements))
+		for i, e := range n.Elements {
+			elems[i] = ast.RecordElementNode{Key: e.Key, Value: q.node(e.Value)}
+		}
+		n.Elements = elems
+		return n
+	case ast.NodeTypeAccess:
+		n.Arg = q.node(n.Arg)
+		return n
+	case ast.NodeTypeHas:
+		n.Arg = q.node(n.Arg)
+		return n
+	case ast.NodeTypeLike:
+		n.Arg = q.node(n.Arg)
+		return n
+	case ast.NodeTypeNegate:
+		n.Arg = q.node(n.Arg)
+		return n
+	case ast.NodeTypeNot:
+		n.Arg = q.node(n.Arg)
+		return n
+	case ast.NodeTypeIsEmpty:
+		n.Arg = q.node(n.Arg)
+		return n
+	case ast.NodeTypeAnd:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeOr:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeEquals:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeNotEquals:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeLessThan:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeLessThanOrEqual:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeGreaterThan:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeGreaterThanOrEqual:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeIn:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeAdd:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeSub:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeMult:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeContains:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeContainsAll:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeContainsAny:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeHasTag:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	case ast.NodeTypeGetTag:
+		n.BinaryNode = q.binary(n.BinaryNode)
+		return n
+	}
+	return n
+}
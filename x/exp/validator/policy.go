@@ -102,13 +102,18 @@ func (v *Validator) validateActionScopeInSet(s ast.ScopeTypeInSet, errs *[]strin
 		return
 	}
 
+	// An action group without appliesTo of its own is still valid when any of
+	// its member actions has a valid appliesTo configuration.
 	allInvalid := true
 	for _, entity := range s.Entities {
-		info, ok := v.actionTypes[entity]
-		if !ok {
+		if _, ok := v.actionTypes[entity]; !ok {
 			*errs = append(*errs, fmt.Sprintf("action scope references unknown action: %s", entity))
-		} else if v.actionHasValidAppliesTo(info) {
-			allInvalid = false
+			continue
+		}
+		for _, uid := range v.actionUIDsIn([]types.EntityUID{entity}) {
+			if v.actionHasValidAppliesTo(v.actionTypes[uid]) {
+				allInvalid = false
+			}
 		}
 	}
 
@@ -214,7 +219,8 @@ func (v *Validator) reportActionAppliesToError(policyAST *ast.Policy, actionInfo
 
 // getActionInfos extracts schema.ActionTypeInfo(s) from an action scope.
 // Returns slice of action infos for single action or action set.
-// For ScopeTypeAll (unscoped action), returns all action infos.
+// For ScopeTypeAll (unscoped action), returns all action infos. For action
+// groups and sets, members of any listed action group are included.
 func (v *Validator) getActionInfos(scope ast.IsScopeNode) []*schema.ActionTypeInfo {
 	switch s := scope.(type) {
 	case ast.ScopeTypeAll:
@@ -229,18 +235,25 @@ func (v *Validator) getActionInfos(scope ast.IsScopeNode) []*schema.ActionTypeIn
 		if info, ok := v.actionTypes[s.Entity]; ok {
 			return []*schema.ActionTypeInfo{info}
 		}
+	case ast.ScopeTypeIn:
+		return v.actionInfosIn([]types.EntityUID{s.Entity})
 	case ast.ScopeTypeInSet:
-		var infos []*schema.ActionTypeInfo
-		for _, entity := range s.Entities {
-			if info, ok := v.actionTypes[entity]; ok {
-				infos = append(infos, info)
-			}
-		}
-		return infos
+		return v.actionInfosIn(s.Entities)
 	}
 	return nil
 }
 
+// actionInfosIn returns the action infos of the declared actions in the
+// given actions or action groups. Groups contribute the appliesTo of their
+// members.
+func (v *Validator) actionInfosIn(entities []types.EntityUID) []*schema.ActionTypeInfo {
+	var infos []*schema.ActionTypeInfo
+	for _, uid := range v.actionUIDsIn(entities) {
+		infos = append(infos, v.actionTypes[uid])
+	}
+	return infos
+}
+
 // unionPrincipalTypes returns the union of principal types from multiple action infos.
 func (v *Validator) unionPrincipalTypes(infos []*schema.ActionTypeInfo) []types.EntityType {
 	seen := make(map[types.EntityType]bool)
@@ -508,9 +521,10 @@ func (v *Validator) evaluateConstantIfThenElse(n ast.NodeTypeIfThenElse) (bool,
 // A schema is "empty" for policy validation if no action has a valid appliesTo
 // configuration (non-empty principalTypes AND resourceTypes).
 // This matches Lean's behavior where policies are "impossible" if there are
-// no valid (principal, action, resource) combinations.
+// no valid (principal, action, resource) combinations. It always considers
+// the whole schema, even for the subset used by ValidatePolicyFast.
 func (v *Validator) isSchemaEmpty() bool {
-	for _, info := range v.actionTypes {
+	for _, info := range v.schema.ActionTypesMap() {
 		// An action has a valid environment if it has at least one principal type
 		// AND at least one resource type in its appliesTo
 		if len(info.PrincipalTypes) > 0 && len(info.ResourceTypes) > 0 {
@@ -15,6 +15,7 @@
 package validator
 
 import (
+	"maps"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go"
@@ -470,3 +471,190 @@ func TestContextValidationStrict(t *testing.T) {
 		t.Error("Expected invalid request for missing required context")
 	}
 }
+
+func TestExtensionValuesInContext(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		action view appliesTo {
+			principal: User,
+			resource: Document,
+			context: {
+				clientIp: ipaddr,
+				amount: decimal,
+				at: datetime,
+				ttl: duration,
+			},
+		};
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	ip, err := types.ParseIPAddr("10.1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount, err := types.ParseDecimal("12.50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, err := types.ParseDatetime("2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttl, err := types.ParseDuration("1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	context := types.RecordMap{
+		"clientIp": ip,
+		"amount":   amount,
+		"at":       at,
+		"ttl":      ttl,
+	}
+	req := cedar.Request{
+		Principal: types.NewEntityUID("User", "alice"),
+		Action:    types.NewEntityUID("Action", "view"),
+		Resource:  types.NewEntityUID("Document", "doc1"),
+		Context:   types.NewRecord(context),
+	}
+
+	t.Run("ValidateRequestAcceptsExtensionValues", func(t *testing.T) {
+		if result := ValidateRequest(s, req); !result.Valid {
+			t.Errorf("Expected valid request, got error: %s", result.Error)
+		}
+	})
+
+	t.Run("ValidateRequestRejectsOtherExtension", func(t *testing.T) {
+		wrong := maps.Clone(context)
+		wrong["clientIp"] = amount
+		bad := req
+		bad.Context = types.NewRecord(wrong)
+		if result := ValidateRequest(s, bad); result.Valid {
+			t.Error("Expected decimal to be rejected for an ipaddr attribute")
+		}
+	})
+
+	t.Run("ValidateRequestRejectsUnparsedString", func(t *testing.T) {
+		wrong := maps.Clone(context)
+		wrong["clientIp"] = types.String("10.1.2.3")
+		bad := req
+		bad.Context = types.NewRecord(wrong)
+		if result := ValidateRequest(s, bad); result.Valid {
+			t.Error("Expected string to be rejected for an ipaddr attribute")
+		}
+	})
+
+	t.Run("ValidateRequestAcceptsExtensionValuesFromJSON", func(t *testing.T) {
+		var rec types.Record
+		err := rec.UnmarshalJSON([]byte(`{
+			"clientIp": {"__extn": {"fn": "ip", "arg": "10.1.2.3"}},
+			"amount": {"__extn": {"fn": "decimal", "arg": "12.50"}},
+			"at": {"__extn": {"fn": "datetime", "arg": "2024-01-01T00:00:00Z"}},
+			"ttl": {"__extn": {"fn": "duration", "arg": "1h"}}
+		}`))
+		if err != nil {
+			t.Fatalf("Failed to parse context: %v", err)
+		}
+		fromJSON := req
+		fromJSON.Context = rec
+		if result := ValidateRequest(s, fromJSON); !result.Valid {
+			t.Errorf("Expected valid request, got error: %s", result.Error)
+		}
+	})
+
+	policies := []struct {
+		name string
+		src  string
+	}{
+		{"ipaddr", `permit(principal, action == Action::"view", resource) when { context.clientIp.isInRange(ip("10.0.0.0/8")) && context.clientIp.isIpv4() };`},
+		{"decimal", `permit(principal, action == Action::"view", resource) when { context.amount.lessThan(decimal("100.0")) };`},
+		{"datetime", `permit(principal, action == Action::"view", resource) when { context.at.offset(context.ttl) > context.at && context.at.toDate() <= context.at };`},
+		{"duration", `permit(principal, action == Action::"view", resource) when { context.ttl.toMinutes() == 60 };`},
+	}
+	for _, tt := range policies {
+		t.Run("Policy_"+tt.name, func(t *testing.T) {
+			var policy cedar.Policy
+			if err := policy.UnmarshalCedar([]byte(tt.src)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			ps := cedar.NewPolicySet()
+			ps.Add("p", &policy)
+			if result := ValidatePolicies(s, ps); !result.Valid {
+				t.Errorf("Expected policy to validate, got errors: %v", result.Errors)
+			}
+			decision, diag := cedar.Authorize(ps, types.EntityMap{}, req)
+			if decision != cedar.Allow {
+				t.Errorf("Expected Allow, got %v (errors: %v)", decision, diag.Errors)
+			}
+		})
+	}
+
+	t.Run("PolicyRejectsWrongExtensionMethod", func(t *testing.T) {
+		var policy cedar.Policy
+		src := `permit(principal, action == Action::"view", resource) when { context.amount.isInRange(ip("10.0.0.0/8")) };`
+		if err := policy.UnmarshalCedar([]byte(src)); err != nil {
+			t.Fatalf("Failed to parse policy: %v", err)
+		}
+		ps := cedar.NewPolicySet()
+		ps.Add("p", &policy)
+		if result := ValidatePolicies(s, ps); result.Valid {
+			t.Error("Expected isInRange on a decimal to be rejected")
+		}
+	})
+}
+
+func TestValidateContextNestedValues(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Document;
+		action view appliesTo {
+			principal: User,
+			resource: Document,
+			context: { device: { trusted: Bool }, tags: Set<String> },
+		};
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	request := func(device types.RecordMap, tags ...types.Value) cedar.Request {
+		return cedar.Request{
+			Principal: types.NewEntityUID("User", "alice"),
+			Action:    types.NewEntityUID("Action", "view"),
+			Resource:  types.NewEntityUID("Document", "doc1"),
+			Context: types.NewRecord(types.RecordMap{
+				"device": types.NewRecord(device),
+				"tags":   types.NewSet(tags...),
+			}),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		req    cedar.Request
+		strict bool
+		want   string
+	}{
+		{"undeclared nested attribute", request(types.RecordMap{"trusted": types.True, "os": types.String("linux")}, types.String("a")), false, ""},
+		{"undeclared nested attribute strict", request(types.RecordMap{"trusted": types.True, "os": types.String("linux")}, types.String("a")), true,
+			"context validation failed: context.device.os: attribute is not declared"},
+		{"every set element", request(types.RecordMap{"trusted": types.True}, types.String("a"), types.Long(1)), false,
+			"context validation failed: context.tags.element: expected String, got long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ValidatorOption
+			if tt.strict {
+				opts = append(opts, WithStrictEntityValidation())
+			}
+			v, err := New(s, opts...)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if got := v.ValidateRequest(tt.req); got.Error != tt.want {
+				t.Errorf("ValidateRequest() error = %q, want %q", got.Error, tt.want)
+			}
+		})
+	}
+}
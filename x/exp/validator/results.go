@@ -15,14 +15,21 @@
 package validator
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+
 	"github.com/cedar-policy/cedar-go"
 	"github.com/cedar-policy/cedar-go/types"
 )
 
 // PolicyValidationResult contains the result of validating policies.
+// Warnings report problems that were downgraded by a validator option; they
+// do not affect Valid.
 type PolicyValidationResult struct {
-	Valid  bool
-	Errors []PolicyError
+	Valid    bool
+	Errors   []PolicyError
+	Warnings []PolicyError
 }
 
 // PolicyError represents a validation error for a specific policy.
@@ -32,6 +39,52 @@ type PolicyError struct {
 	// Code is an optional structured error code for programmatic handling.
 	// This field is being gradually populated across the codebase.
 	Code ValidationErrorCode
+	// Position is the source position of the policy, as recorded when it was
+	// parsed. It is the zero value for policies built from an AST.
+	Position cedar.Position
+}
+
+// Severity values of the lines written by WriteJSONL.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// jsonlPolicyError is the line written by WriteJSONL for one PolicyError.
+type jsonlPolicyError struct {
+	PolicyID cedar.PolicyID      `json:"policyId"`
+	Severity string              `json:"severity"`
+	Code     ValidationErrorCode `json:"code,omitempty"`
+	Message  string              `json:"message"`
+	Location cedar.Position      `json:"location"`
+}
+
+// WriteJSONL writes the errors and then the warnings of r to w in the JSON
+// Lines format, one object per line, so that results for a large policy set
+// can be streamed into a log pipeline. Each object holds the policyId,
+// severity ("error" or "warning"), code, message and location of the problem:
+//
+//	{"policyId":"policy0","severity":"error","code":"unexpected_type","message":"...","location":{"filename":"policies.cedar","offset":0,"line":1,"column":1}}
+func (r PolicyValidationResult) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, group := range []struct {
+		severity string
+		errs     []PolicyError
+	}{{SeverityError, r.Errors}, {SeverityWarning, r.Warnings}} {
+		for _, e := range group.errs {
+			line := jsonlPolicyError{
+				PolicyID: e.PolicyID,
+				Severity: group.severity,
+				Code:     e.Code,
+				Message:  e.Message,
+				Location: e.Position,
+			}
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // EntityValidationResult contains the result of validating entities.
@@ -54,3 +107,44 @@ type RequestValidationResult struct {
 	Valid bool
 	Error string
 }
+
+// AllResult contains the consolidated result of validating policies, entities,
+// and requests together. Requests holds one result per input request, in order.
+type AllResult struct {
+	Policies PolicyValidationResult
+	Entities EntityValidationResult
+	Requests []RequestValidationResult
+}
+
+// HasErrors reports whether any policy, entity, or request failed validation.
+// This is suitable for deriving a process exit code. Policy warnings are not
+// counted; use HasWarnings to also fail on them.
+func (r AllResult) HasErrors() bool {
+	if !r.Policies.Valid || !r.Entities.Valid {
+		return true
+	}
+	for _, req := range r.Requests {
+		if !req.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWarnings reports whether policy validation produced any warnings.
+func (r AllResult) HasWarnings() bool {
+	return len(r.Policies.Warnings) > 0
+}
+
+// Summary returns a one-line, human-readable summary of the result, e.g.
+// "policies: 2 error(s), 1 warning(s); entities: 0 error(s); requests: 1 of 3 invalid".
+func (r AllResult) Summary() string {
+	invalidRequests := 0
+	for _, req := range r.Requests {
+		if !req.Valid {
+			invalidRequests++
+		}
+	}
+	return fmt.Sprintf("policies: %d error(s), %d warning(s); entities: %d error(s); requests: %d of %d invalid",
+		len(r.Policies.Errors), len(r.Policies.Warnings), len(r.Entities.Errors), invalidRequests, len(r.Requests))
+}
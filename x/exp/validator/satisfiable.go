@@ -0,0 +1,204 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/internal/eval"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Policy Satisfiability
+// -----------------------------------------------------------------------------
+
+// IsPolicySatisfiable reports whether some request allowed by the schema
+// could make the policy match. When it returns false, reason describes why
+// the policy can never apply.
+//
+// The check combines the validator's impossiblePolicy checks on the scope
+// and on type relationships with a partial evaluation of the policy for each
+// action the schema declares. With the action known and the principal,
+// resource and context left unknown, conditions that fold to a constant,
+// such as `when { 1 > 2 }`, and conditions that always error, such as
+// `when { 1 + "a" == 2 }`, are detected. Only the schema's action entities
+// are known, so a condition that depends on the data of any other entity,
+// such as `when { User::"a" in Group::"g" }`, is never taken to fail. A
+// result of true means no such contradiction was found; it does not
+// guarantee a matching request exists.
+func (v *Validator) IsPolicySatisfiable(policy *cedar.Policy) (satisfiable bool, reason string) {
+	p := (*ast.Policy)(policy.AST())
+	if v.defaultNamespace != "" {
+		p, _ = v.qualifyPolicy(p)
+	}
+	if v.isSchemaEmpty() {
+		return false, "schema declares no action with both principal and resource types"
+	}
+
+	for _, msg := range v.validatePolicyScope(p) {
+		if strings.HasPrefix(msg, "impossiblePolicy") {
+			return false, msg
+		}
+	}
+	typeErrs, _ := v.typecheckPolicy(p)
+	for _, msg := range typeErrs {
+		if strings.HasPrefix(msg, "impossiblePolicy") {
+			return false, msg
+		}
+	}
+
+	reasons := make(map[types.EntityUID]string)
+	for _, action := range v.applicableActions(p) {
+		env, _ := v.partialEnv(action)
+		scopeOnly := &ast.Policy{Effect: p.Effect, Principal: p.Principal, Action: p.Action, Resource: p.Resource}
+		if _, keep := eval.PartialPolicy(env, scopeOnly); !keep {
+			continue
+		}
+		reason, ok := v.conditionsMatch(action, p)
+		if ok {
+			return true, ""
+		}
+		reasons[action] = reason
+	}
+	return false, summarizeReasons(reasons)
+}
+
+// applicableActions returns the declared actions, in a stable order, that
+// have an appliesTo configuration compatible with the policy's principal and
+// resource scopes.
+func (v *Validator) applicableActions(p *ast.Policy) []types.EntityUID {
+	var actions []types.EntityUID
+	for uid, info := range v.actionTypes {
+		if len(info.PrincipalTypes) == 0 || len(info.ResourceTypes) == 0 {
+			continue
+		}
+		candidates := []*schema.ActionTypeInfo{info}
+		if len(v.filterByResourceScope(v.filterByPrincipalScope(candidates, p.Principal), p.Resource)) > 0 {
+			actions = append(actions, uid)
+		}
+	}
+	slices.SortFunc(actions, func(a, b types.EntityUID) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	return actions
+}
+
+// partialEnv returns an environment for the given action in which the
+// principal, resource and context are unknown. Only the schema's action
+// entities are known; the returned getter records whether any other entity
+// was looked up.
+func (v *Validator) partialEnv(action types.EntityUID) (eval.Env, *actionEntities) {
+	entities := &actionEntities{actions: v.schema.ActionEntities()}
+	return eval.Env{
+		Entities:  entities,
+		Principal: eval.Variable("principal"),
+		Action:    action,
+		Resource:  eval.Variable("resource"),
+		Context:   eval.Variable("context"),
+	}, entities
+}
+
+// actionEntities is an entity getter over the schema's action entities. The
+// data of any other entity depends on the request, so a lookup of one is
+// recorded to keep conditions that depend on it from being decided.
+type actionEntities struct {
+	actions types.EntityMap
+	unknown bool
+}
+
+func (e *actionEntities) Get(uid types.EntityUID) (types.Entity, bool) {
+	if entity, ok := e.actions[uid]; ok {
+		return entity, true
+	}
+	e.unknown = true
+	return types.Entity{}, false
+}
+
+// conditionsMatch partially evaluates the conditions of p for the given
+// action. It reports whether they may still be satisfied, or otherwise why
+// not.
+func (v *Validator) conditionsMatch(action types.EntityUID, p *ast.Policy) (string, bool) {
+	for _, c := range splitConjuncts(eval.PolicyToNode(p).AsIsNode(), true, nil) {
+		_, holds, decided, err := v.partialConjunct(action, c)
+		if err != nil {
+			return fmt.Sprintf("condition always errors: %v", err), false
+		}
+		if decided && !holds {
+			return "conditions are never satisfied", false
+		}
+	}
+	return "", true
+}
+
+// neverHolds reports whether a residual condition can only evaluate to a
+// result that blocks the policy or to an error, such as
+// `when { principal.age > 1 && false }`. The partial evaluator keeps such
+// conditions because the unknown operand could still error.
+func neverHolds(c ast.ConditionType) bool {
+	if c.Condition == ast.ConditionWhen {
+		return isConstant(c.Body, false)
+	}
+	return isConstant(c.Body, true)
+}
+
+// isConstant reports whether n evaluates to want whenever it does not error.
+func isConstant(n ast.IsNode, want bool) bool {
+	switch n := n.(type) {
+	case ast.NodeValue:
+		b, ok := n.Value.(types.Boolean)
+		return ok && bool(b) == want
+	case ast.NodeTypeNot:
+		return isConstant(n.Arg, !want)
+	case ast.NodeTypeAnd:
+		if want {
+			return isConstant(n.Left, true) && isConstant(n.Right, true)
+		}
+		return isConstant(n.Left, false) || isConstant(n.Right, false)
+	case ast.NodeTypeOr:
+		if want {
+			return isConstant(n.Left, true) || isConstant(n.Right, true)
+		}
+		return isConstant(n.Left, false) && isConstant(n.Right, false)
+	}
+	return false
+}
+
+// summarizeReasons combines the per-action reasons a policy cannot match. A
+// reason shared by every action is reported once.
+func summarizeReasons(reasons map[types.EntityUID]string) string {
+	if len(reasons) == 0 {
+		return "no action declared in the schema matches the policy scope"
+	}
+	distinct := slices.Compact(slices.Sorted(maps.Values(reasons)))
+	if len(distinct) == 1 {
+		return distinct[0]
+	}
+	actions := slices.SortedFunc(maps.Keys(reasons), func(a, b types.EntityUID) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	parts := make([]string, len(actions))
+	for i, a := range actions {
+		parts[i] = fmt.Sprintf("%s: %s", a, reasons[a])
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,97 @@
+// Copyright Cedar Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+)
+
+func TestIsPolicySatisfiable(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User { age: Long };
+		entity Team;
+		entity Group;
+		entity Doc;
+		action read;
+		action view in [read] appliesTo { principal: User, resource: Doc, context: { level: Long } };
+		action edit appliesTo { principal: User, resource: Doc };
+		action manage appliesTo { principal: Team, resource: Group };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		wantSat    bool
+		wantReason string
+	}{
+		{"unconstrained", `permit(principal, action, resource);`, true, ""},
+		{"residual condition", `forbid(principal, action, resource) when { context.level > 1 };`, true, ""},
+		{"entity membership", `permit(principal, action, resource) when { User::"a" in Group::"g" };`, true, ""},
+		{"entity attribute", `permit(principal, action, resource) when { User::"a".age > 1 };`, true, ""},
+		{"action group", `permit(principal, action in Action::"read", resource) when { action == Action::"view" };`, true, ""},
+		{"constant false", `permit(principal, action, resource) when { 1 > 2 };`, false, "conditions are never satisfied"},
+		{"constant unless", `permit(principal, action, resource) unless { true };`, false, "conditions are never satisfied"},
+		{"false conjunct", `permit(principal, action, resource) when { principal.age > 1 && 1 > 2 };`, false, "conditions are never satisfied"},
+		{"true disjunct in unless", `permit(principal, action, resource) unless { principal.age > 1 || !false };`, false, "conditions are never satisfied"},
+		{"entity data and constant false", `permit(principal, action, resource) when { User::"a" in Group::"g" } when { 1 > 2 };`, false, "conditions are never satisfied"},
+		{"always errors", `permit(principal, action, resource) when { 1 + "a" == 2 };`, false, "condition always errors"},
+		{"action contradiction", `permit(principal, action == Action::"view", resource) when { action == Action::"edit" };`, false, "conditions are never satisfied"},
+		{"excluded group member", `permit(principal, action in Action::"read", resource) when { action != Action::"view" };`, false, "conditions are never satisfied"},
+		{"scope type", `permit(principal is Team, action == Action::"view", resource);`, false, "impossiblePolicy"},
+		{"disjoint equality", `permit(principal, action, resource) when { principal == resource };`, false, "disjoint types"},
+		{"no matching action", `permit(principal, action, resource is Group) when { action == Action::"view" };`, false, "conditions are never satisfied"},
+		{"action without appliesTo", `permit(principal, action == Action::"read", resource);`, false, "impossiblePolicy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy cedar.Policy
+			if err := policy.UnmarshalCedar([]byte(tt.policy)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			sat, reason := IsPolicySatisfiable(s, &policy)
+			if sat != tt.wantSat {
+				t.Fatalf("IsPolicySatisfiable() = %v (%s), want %v", sat, reason, tt.wantSat)
+			}
+			if tt.wantSat && reason != "" {
+				t.Errorf("Expected no reason for a satisfiable policy, got %q", reason)
+			}
+			if !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("Expected reason containing %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestIsPolicySatisfiableEmptySchema(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`entity User; action view;`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	var policy cedar.Policy
+	if err := policy.UnmarshalCedar([]byte(`permit(principal, action, resource);`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if sat, reason := IsPolicySatisfiable(s, &policy); sat || reason == "" {
+		t.Errorf("Expected unsatisfiable with a reason, got %v %q", sat, reason)
+	}
+}
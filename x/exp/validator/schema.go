@@ -15,10 +15,10 @@
 package validator
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 
-	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
 )
 
 // -----------------------------------------------------------------------------
@@ -51,66 +51,19 @@ func (e *SchemaValidationError) Error() string {
 //
 // Note: Duplicate types in principalTypes/resourceTypes/memberOfTypes are allowed
 // (they are semantically redundant but not invalid). This matches Lean's behavior.
+//
+// The reference checks are performed by [schema.Schema.Precompile], which
+// caches its result on the schema so validators built over the same schema
+// share the work.
 func (v *Validator) validateSchemaWellFormedness() error {
-	var errors []string
-
-	// Check that referenced types exist (unless in Lean compatibility mode)
-	if !v.allowUnknownEntityTypes {
-		errors = append(errors, v.checkMemberOfTypesExist()...)
-	}
-	errors = append(errors, v.checkActionTypesExist()...)
-
-	if len(errors) > 0 {
-		return &SchemaValidationError{Errors: errors}
-	}
-	return nil
-}
-
-// checkMemberOfTypesExist validates that all memberOfTypes reference existing entity types.
-func (v *Validator) checkMemberOfTypesExist() []string {
-	var errors []string
-	for entityType, info := range v.entityTypes {
-		for _, mot := range info.MemberOfTypes {
-			if _, exists := v.entityTypes[mot]; !exists {
-				errors = append(errors, fmt.Sprintf("entity type %s references unknown memberOfTypes: %s", entityType, mot))
-			}
-		}
-	}
-	return errors
-}
-
-// checkActionTypesExist validates that principalTypes and resourceTypes reference existing entity types.
-// This check is skipped if allowUnknownEntityTypes is true (Lean compatibility mode).
-func (v *Validator) checkActionTypesExist() []string {
+	// Unknown types are allowed in Lean compatibility mode
 	if v.allowUnknownEntityTypes {
-		return nil // Skip validation in Lean compatibility mode
-	}
-	var errors []string
-	for actionName, info := range v.actionTypes {
-		errors = append(errors, v.checkPrincipalTypesExist(actionName, info.PrincipalTypes)...)
-		errors = append(errors, v.checkResourceTypesExist(actionName, info.ResourceTypes)...)
-	}
-	return errors
-}
-
-// checkPrincipalTypesExist validates that all principalTypes for an action exist.
-func (v *Validator) checkPrincipalTypesExist(actionName types.EntityUID, principalTypes []types.EntityType) []string {
-	var errors []string
-	for _, pt := range principalTypes {
-		if _, exists := v.entityTypes[pt]; !exists {
-			errors = append(errors, fmt.Sprintf("action %s references unknown principalType: %s", actionName, pt))
-		}
+		return nil
 	}
-	return errors
-}
-
-// checkResourceTypesExist validates that all resourceTypes for an action exist.
-func (v *Validator) checkResourceTypesExist(actionName types.EntityUID, resourceTypes []types.EntityType) []string {
-	var errors []string
-	for _, rt := range resourceTypes {
-		if _, exists := v.entityTypes[rt]; !exists {
-			errors = append(errors, fmt.Sprintf("action %s references unknown resourceType: %s", actionName, rt))
-		}
+	err := v.schema.Precompile()
+	var refErr *schema.ReferenceError
+	if errors.As(err, &refErr) {
+		return &SchemaValidationError{Errors: refErr.Errors}
 	}
-	return errors
+	return err
 }
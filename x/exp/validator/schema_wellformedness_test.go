@@ -16,6 +16,7 @@ package validator
 
 import (
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go/x/exp/schema"
@@ -421,3 +422,47 @@ func TestSchemaValidation_EmptySchema(t *testing.T) {
 		t.Error("Expected non-nil validator")
 	}
 }
+
+func TestSchemaValidation_SharedAcrossValidators(t *testing.T) {
+	// Validators built concurrently over the same schema share its cached
+	// precompiled checks.
+	schemaJSON := `{
+		"": {
+			"entityTypes": {
+				"User": {},
+				"Document": {}
+			},
+			"actions": {
+				"view": {
+					"appliesTo": {
+						"principalTypes": ["User"],
+						"resourceTypes": ["Document"]
+					}
+				}
+			}
+		}
+	}`
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Schema parsing should succeed: %v", err)
+	}
+	if err := s.Precompile(); err != nil {
+		t.Fatalf("Precompile should succeed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = New(s, WithStrictEntityValidation())
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("validator %d: unexpected error: %v", i, err)
+		}
+	}
+}
@@ -262,6 +262,73 @@ func TestValidateActionInScope(t *testing.T) {
 	}
 }
 
+func TestValidateActionGroupScope(t *testing.T) {
+	s, err := schema.NewFromCedar("test.cedarschema", []byte(`
+		entity User;
+		entity Document;
+		action readWrite;
+		action read in [readWrite] appliesTo { principal: User, resource: Document };
+		action write in [readWrite] appliesTo { principal: User, resource: Document };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		policy    string
+		wantValid bool
+		wantError string
+	}{
+		{
+			name:      "in group",
+			policy:    `permit(principal, action in Action::"readWrite", resource);`,
+			wantValid: true,
+		},
+		{
+			name:      "in set containing group",
+			policy:    `permit(principal, action in [Action::"readWrite"], resource);`,
+			wantValid: true,
+		},
+		{
+			name:      "in group typechecks against members",
+			policy:    `permit(principal is User, action in [Action::"readWrite"], resource is Document) when { principal == resource };`,
+			wantValid: false,
+			wantError: "impossiblePolicy",
+		},
+		{
+			name:      "in group with scope types",
+			policy:    `permit(principal is User, action in [Action::"readWrite"], resource is Document);`,
+			wantValid: true,
+		},
+		{
+			name:      "in group with wrong principal type",
+			policy:    `permit(principal is Document, action in Action::"readWrite", resource);`,
+			wantValid: false,
+			wantError: "principal type",
+		},
+		{
+			name:      "in set containing group with wrong principal type",
+			policy:    `permit(principal is Document, action in [Action::"readWrite"], resource);`,
+			wantValid: false,
+			wantError: "principal type",
+		},
+		{
+			name:      "equals group",
+			policy:    `permit(principal, action == Action::"readWrite", resource);`,
+			wantValid: false,
+			wantError: "impossiblePolicy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validatePolicyString(t, s, tt.policy)
+			checkPolicyResult(t, result, tt.wantValid, tt.wantError)
+		})
+	}
+}
+
 func TestScopeTypeIsIn(t *testing.T) {
 	schemaJSON := `{
 		"": {
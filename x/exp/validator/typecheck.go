@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 
 	"github.com/cedar-policy/cedar-go/types"
 	"github.com/cedar-policy/cedar-go/x/exp/ast"
@@ -31,12 +32,19 @@ type typeContext struct {
 	resourceTypes  []types.EntityType // Possible types for resource
 	actionUID      *types.EntityUID   // Specific action (if known)
 	contextType    schema.RecordType  // Context type for the effective actions
+	contextActions []types.EntityUID  // Effective actions, whose contexts are intersected
 	errors         []string
+	warnings       []string
 	currentLevel   int // Current attribute dereference level
+	// capabilities holds the attribute access paths (e.g., "context.reason")
+	// that are known to be present because an enclosing `has` guard succeeded,
+	// and the `is` tests on variables (e.g., "principal is User") that hold.
+	capabilities map[string]bool
 }
 
-// typecheckPolicy performs full type-checking on a policy
-func (v *Validator) typecheckPolicy(p *ast.Policy) []string {
+// typecheckPolicy performs full type-checking on a policy, returning the
+// errors and warnings found.
+func (v *Validator) typecheckPolicy(p *ast.Policy) (errs, warnings []string) {
 	ctx := &typeContext{
 		v: v,
 	}
@@ -51,10 +59,15 @@ func (v *Validator) typecheckPolicy(p *ast.Policy) []string {
 	ctx.resourceTypes = v.extractEffectiveResourceTypes(p.Resource, effectiveActions)
 	ctx.actionUID = v.extractActionUID(p.Action)
 	ctx.contextType = v.extractEffectiveContextType(effectiveActions)
+	ctx.contextActions = v.actionUIDsOf(effectiveActions)
 
-	// Type-check each condition
+	// Type-check each condition. Conditions are conjoined, so the `has`
+	// guards established by a when clause also hold for later clauses.
 	for _, cond := range p.Conditions {
 		inferredType := ctx.typecheck(cond.Body)
+		if cond.Condition == ast.ConditionWhen {
+			ctx.addCapabilities(ctx.guardsOf(cond.Body))
+		}
 
 		// Condition must evaluate to Boolean.
 		// We allow UnknownType here for cases where the type can't be determined
@@ -70,7 +83,7 @@ func (v *Validator) typecheckPolicy(p *ast.Policy) []string {
 		}
 	}
 
-	return ctx.errors
+	return ctx.errors, ctx.warnings
 }
 
 // getEffectiveActions returns the actions that could potentially match all scope constraints.
@@ -102,6 +115,8 @@ func (v *Validator) getCandidateActions(actionScope ast.IsActionScopeNode) []*sc
 		if info, ok := v.actionTypes[a.Entity]; ok {
 			return []*schema.ActionTypeInfo{info}
 		}
+	case ast.ScopeTypeIn:
+		return v.actionsInSet([]types.EntityUID{a.Entity})
 	case ast.ScopeTypeInSet:
 		return v.actionsInSet(a.Entities)
 	}
@@ -117,17 +132,72 @@ func (v *Validator) allActions() []*schema.ActionTypeInfo {
 	return actions
 }
 
-// actionsInSet returns actions matching the given entity UIDs.
+// actionsInSet returns the actions an `action in` scope over the given
+// entity UIDs can match: each listed action and every action that is a
+// transitive member of one, keeping only those that declare appliesTo. When
+// none do, the listed actions are returned as-is so that the scope checks
+// can report them.
 func (v *Validator) actionsInSet(entities []types.EntityUID) []*schema.ActionTypeInfo {
-	var actions []*schema.ActionTypeInfo
-	for _, entity := range entities {
-		if info, ok := v.actionTypes[entity]; ok {
+	var actions, listed []*schema.ActionTypeInfo
+	for _, uid := range v.actionUIDsIn(entities) {
+		info := v.actionTypes[uid]
+		if slices.Contains(entities, uid) {
+			listed = append(listed, info)
+		}
+		if len(info.PrincipalTypes) > 0 || len(info.ResourceTypes) > 0 {
 			actions = append(actions, info)
 		}
 	}
+	if len(actions) == 0 {
+		return listed
+	}
 	return actions
 }
 
+// actionUIDsIn returns, sorted, the declared actions that are one of the
+// given UIDs or a transitive member of one.
+func (v *Validator) actionUIDsIn(entities []types.EntityUID) []types.EntityUID {
+	var uids []types.EntityUID
+	for uid := range v.actionTypes {
+		if v.actionInAny(uid, entities, map[types.EntityUID]bool{}) {
+			uids = append(uids, uid)
+		}
+	}
+	slices.SortFunc(uids, types.EntityUID.Compare)
+	return uids
+}
+
+// actionInAny reports whether action is one of targets or a transitive member
+// of one, following memberOf declarations in the schema.
+func (v *Validator) actionInAny(action types.EntityUID, targets []types.EntityUID, visited map[types.EntityUID]bool) bool {
+	if slices.Contains(targets, action) {
+		return true
+	}
+	if visited[action] {
+		return false
+	}
+	visited[action] = true
+	info, ok := v.actionTypes[action]
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(info.MemberOf, func(parent types.EntityUID) bool {
+		return v.actionInAny(parent, targets, visited)
+	})
+}
+
+// actionUIDsOf returns, sorted, the UIDs of the given declared actions.
+func (v *Validator) actionUIDsOf(actions []*schema.ActionTypeInfo) []types.EntityUID {
+	var uids []types.EntityUID
+	for uid, info := range v.actionTypes {
+		if slices.Contains(actions, info) {
+			uids = append(uids, uid)
+		}
+	}
+	slices.SortFunc(uids, types.EntityUID.Compare)
+	return uids
+}
+
 // filterByPrincipalScope filters actions by principal scope compatibility.
 func (v *Validator) filterByPrincipalScope(actions []*schema.ActionTypeInfo, scope ast.IsPrincipalScopeNode) []*schema.ActionTypeInfo {
 	principalType := v.extractScopeEntityType(scope)
@@ -416,7 +486,7 @@ func (ctx *typeContext) typecheckConditional(n ast.NodeTypeIfThenElse) schema.Ce
 	if !isTypeBoolean(condType) && !isTypeUnknown(condType) {
 		ctx.errors = append(ctx.errors, fmt.Sprintf("unexpectedType: if condition must be boolean, got %s", condType))
 	}
-	thenType := ctx.typecheck(n.Then)
+	thenType := ctx.typecheckWithCapabilities(n.Then, ctx.guardsOf(n.If))
 	elseType := ctx.typecheck(n.Else)
 	unified := unifyTypes(thenType, elseType)
 	// Check if unification failed - report lubErr
@@ -437,25 +507,60 @@ func (ctx *typeContext) typecheckSetLiteral(n ast.NodeTypeSet) schema.CedarType
 		ctx.errors = append(ctx.errors, "emptySetErr: cannot infer element type of empty set literal")
 		return schema.SetType{Element: schema.UnknownType{}}
 	}
+	elemTypes := make([]schema.CedarType, len(n.Elements))
+	for i, elem := range n.Elements {
+		elemTypes[i] = ctx.typecheck(elem)
+	}
+	return schema.SetType{Element: ctx.unifySetElements(elemTypes)}
+}
+
+// unifySetElements returns the common type of a set literal's elements. If
+// the elements do not unify, it reports incompatibleSetTypes and returns
+// UnknownType. The report is a warning when the validator was configured with
+// WithHeterogeneousSetWarnings and an error otherwise.
+func (ctx *typeContext) unifySetElements(elemTypes []schema.CedarType) schema.CedarType {
 	var elemType schema.CedarType = schema.UnknownType{}
-	var incompatibleTypes []schema.CedarType
-	for _, elem := range n.Elements {
-		t := ctx.typecheck(elem)
+	var first, incompatible schema.CedarType
+	for _, t := range elemTypes {
 		unified := unifyTypes(elemType, t)
 		// Check if unification failed (resulted in UnknownType when both inputs were known)
 		if _, isUnknown := unified.(schema.UnknownType); isUnknown {
-			if !isTypeUnknown(elemType) && !isTypeUnknown(t) {
-				// Types are incompatible - collect them for error reporting
-				incompatibleTypes = append(incompatibleTypes, t)
+			if !isTypeUnknown(elemType) && !isTypeUnknown(t) && incompatible == nil {
+				first, incompatible = elemType, t
 			}
 		}
 		elemType = unified
 	}
-	// Report incompatible set types if any were found
-	if len(incompatibleTypes) > 0 {
-		ctx.errors = append(ctx.errors, "incompatibleSetTypes: set elements have incompatible types")
+	if incompatible != nil {
+		msg := fmt.Sprintf("incompatibleSetTypes: set elements have incompatible types %s and %s", first, incompatible)
+		if ctx.v.heterogeneousSetWarnings {
+			ctx.warnings = append(ctx.warnings, msg)
+		} else {
+			ctx.errors = append(ctx.errors, msg)
+		}
+	}
+	return elemType
+}
+
+// checkSetValue applies the set literal homogeneity check to set values that
+// appear as constants in the policy, including sets nested in records and
+// other sets. It returns the unified element type when val is a set, and
+// UnknownType otherwise.
+func (ctx *typeContext) checkSetValue(val types.Value) schema.CedarType {
+	switch v := val.(type) {
+	case types.Set:
+		var elemTypes []schema.CedarType
+		for elem := range v.All() {
+			ctx.checkSetValue(elem)
+			elemTypes = append(elemTypes, ctx.v.inferType(elem))
+		}
+		return ctx.unifySetElements(elemTypes)
+	case types.Record:
+		for elem := range v.Values() {
+			ctx.checkSetValue(elem)
+		}
 	}
-	return schema.SetType{Element: elemType}
+	return schema.UnknownType{}
 }
 
 // typecheckRecordLiteral handles record literal expressions.
@@ -470,12 +575,25 @@ func (ctx *typeContext) typecheckRecordLiteral(n ast.NodeTypeRecord) schema.Ceda
 
 // typecheckValue handles literal values and checks for unknown entity types.
 func (ctx *typeContext) typecheckValue(val types.Value) schema.CedarType {
-	if euid, ok := val.(types.EntityUID); ok {
-		ctx.checkEntityTypeKnown(euid)
+	ctx.checkValueEntitiesKnown(val)
+	elemType := ctx.checkSetValue(val)
+	if _, ok := val.(types.Set); ok {
+		// Like a set literal, a set whose elements do not unify has an
+		// unknown element type, so it is reported only once.
+		return schema.SetType{Element: elemType}
 	}
 	return ctx.v.inferType(val)
 }
 
+// checkValueEntitiesKnown checks the entity literals in a constant value,
+// including those nested in sets and records, such as the values built with
+// ast.Value or left by partial evaluation.
+func (ctx *typeContext) checkValueEntitiesKnown(val types.Value) {
+	for uid := range types.EntityUIDsIn(val) {
+		ctx.checkEntityTypeKnown(uid)
+	}
+}
+
 // checkEntityTypeKnown verifies that an entity literal references a known type.
 func (ctx *typeContext) checkEntityTypeKnown(euid types.EntityUID) {
 	if _, exists := ctx.v.entityTypes[euid.Type]; exists {
@@ -485,7 +603,7 @@ func (ctx *typeContext) checkEntityTypeKnown(euid types.EntityUID) {
 	if ctx.v.isActionEntityType(euid.Type) {
 		// For action entity types, the specific entity must be a defined action
 		if !ctx.v.isKnownActionEntity(euid) {
-			ctx.errors = append(ctx.errors, fmt.Sprintf("unknownEntity: entity %s is not defined in schema", euid))
+			ctx.errors = append(ctx.errors, fmt.Sprintf("unknownEntity: action %s is not defined in schema", euid))
 		}
 		return
 	}
@@ -498,8 +616,8 @@ func (ctx *typeContext) checkEntityTypeKnown(euid types.EntityUID) {
 func (ctx *typeContext) typecheckVariable(n ast.NodeTypeVariable) schema.CedarType {
 	switch string(n.Name) {
 	case "principal":
-		if len(ctx.principalTypes) == 1 {
-			return schema.EntityCedarType{Name: ctx.principalTypes[0]}
+		if ets := ctx.narrowTypes("principal", ctx.principalTypes); len(ets) == 1 {
+			return schema.EntityCedarType{Name: ets[0]}
 		}
 		return schema.EntityCedarType{} // Unknown entity type
 	case "action":
@@ -508,8 +626,8 @@ func (ctx *typeContext) typecheckVariable(n ast.NodeTypeVariable) schema.CedarTy
 		}
 		return schema.EntityCedarType{Name: "Action"}
 	case "resource":
-		if len(ctx.resourceTypes) == 1 {
-			return schema.EntityCedarType{Name: ctx.resourceTypes[0]}
+		if ets := ctx.narrowTypes("resource", ctx.resourceTypes); len(ets) == 1 {
+			return schema.EntityCedarType{Name: ets[0]}
 		}
 		return schema.EntityCedarType{}
 	case "context":
@@ -531,7 +649,15 @@ func (ctx *typeContext) typecheckBooleanBinary(node ast.IsNode) schema.CedarType
 	}
 
 	leftType := ctx.typecheck(left)
-	rightType := ctx.typecheck(right)
+
+	// For &&, the right operand is only evaluated when the left one is true,
+	// so any `has` guards in the left operand hold while checking the right.
+	var rightType schema.CedarType
+	if _, isAnd := node.(ast.NodeTypeAnd); isAnd {
+		rightType = ctx.typecheckWithCapabilities(right, ctx.guardsOf(left))
+	} else {
+		rightType = ctx.typecheck(right)
+	}
 
 	if !isTypeBoolean(leftType) && !isTypeUnknown(leftType) {
 		ctx.errors = append(ctx.errors,
@@ -561,6 +687,9 @@ func (ctx *typeContext) typecheckEquality(node ast.IsNode) schema.CedarType {
 	// This is a type error, not a runtime behavior (which would return false).
 	// Note: typesAreComparable handles unknown types by allowing comparisons,
 	// matching Lean's lenient behavior with unresolved types.
+	if ctx.checkContextLiteralEquality(left, right, leftType, rightType) {
+		return schema.BoolType{}
+	}
 	if !isTypeUnknown(leftType) && !isTypeUnknown(rightType) {
 		if !ctx.typesAreComparable(leftType, rightType) {
 			ctx.errors = append(ctx.errors,
@@ -577,6 +706,54 @@ func (ctx *typeContext) typecheckEquality(node ast.IsNode) schema.CedarType {
 	return schema.BoolType{}
 }
 
+// checkContextLiteralEquality checks a comparison of the context with a record
+// literal, such as `context == {ip: "x", authenticated: true}`, against the
+// context type of the effective actions, reporting each attribute that does
+// not match. It returns false, leaving the comparison to the generic check, if
+// the operands are not the context and a record literal or the context type is
+// unknown.
+func (ctx *typeContext) checkContextLiteralEquality(left, right ast.IsNode, leftType, rightType schema.CedarType) bool {
+	literalType := rightType
+	if _, ok := left.(ast.NodeTypeRecord); ok {
+		left, literalType = right, leftType
+	} else if _, ok := right.(ast.NodeTypeRecord); !ok {
+		return false
+	}
+	v, ok := left.(ast.NodeTypeVariable)
+	literal, isRecord := literalType.(schema.RecordType)
+	if !ok || v.Name != "context" || !isRecord || ctx.contextType.Attributes == nil {
+		return false
+	}
+
+	ctx.errors = append(ctx.errors, contextLiteralMismatches(ctx.contextType, literal)...)
+	return true
+}
+
+// contextLiteralMismatches describes the attributes of a record literal that
+// do not match the context type expected.
+func contextLiteralMismatches(expected, literal schema.RecordType) []string {
+	var errs []string
+	for _, name := range slices.Sorted(maps.Keys(expected.Attributes)) {
+		attr := expected.Attributes[name]
+		got, ok := literal.Attributes[name]
+		switch {
+		case !ok && attr.Required:
+			errs = append(errs, fmt.Sprintf("lubErr: context record literal is missing required attribute %q", name))
+		case ok && !schema.TypesMatch(attr.Type, got.Type):
+			errs = append(errs, fmt.Sprintf("lubErr: context record literal attribute %q has type %s, expected %s", name, got.Type, attr.Type))
+		}
+	}
+	if expected.OpenRecord {
+		return errs
+	}
+	for _, name := range slices.Sorted(maps.Keys(literal.Attributes)) {
+		if _, ok := expected.Attributes[name]; !ok {
+			errs = append(errs, fmt.Sprintf("lubErr: context record literal has attribute %q, which the context type does not declare", name))
+		}
+	}
+	return errs
+}
+
 // checkPrincipalResourceEquality detects impossible equality between principal and resource.
 // When principal and resource have disjoint type sets, comparing them for equality
 // will always be false, making any policy with such a condition impossible.
@@ -635,17 +812,36 @@ func (ctx *typeContext) typecheckComparison(node ast.IsNode) schema.CedarType {
 	leftType := ctx.typecheck(left)
 	rightType := ctx.typecheck(right)
 
+	// datetime and duration values are ordered too, but only against a value
+	// of the same type.
+	if isTypeOrderedExtension(leftType) || isTypeOrderedExtension(rightType) {
+		if !isTypeUnknown(leftType) && !isTypeUnknown(rightType) && !schema.TypesMatch(leftType, rightType) {
+			ctx.errors = append(ctx.errors,
+				fmt.Sprintf("unexpectedType: comparison operator requires operands of the same type, got %s and %s", leftType, rightType))
+		}
+		return schema.BoolType{}
+	}
+
 	if !isTypeLong(leftType) && !isTypeUnknown(leftType) {
-		ctx.errors = append(ctx.errors,
-			fmt.Sprintf("unexpectedType: comparison operator requires Long operands, got %s", leftType))
+		ctx.errors = append(ctx.errors, comparisonOperandError(leftType))
 	}
 	if !isTypeLong(rightType) && !isTypeUnknown(rightType) {
-		ctx.errors = append(ctx.errors,
-			fmt.Sprintf("unexpectedType: comparison operator requires Long operands, got %s", rightType))
+		ctx.errors = append(ctx.errors, comparisonOperandError(rightType))
 	}
 	return schema.BoolType{}
 }
 
+// comparisonOperandError describes a non-Long operand of a comparison
+// operator. Decimals are never converted implicitly, so the message for them
+// points at the decimal comparison methods.
+func comparisonOperandError(t schema.CedarType) string {
+	msg := fmt.Sprintf("unexpectedType: comparison operator requires Long operands, got %s", t)
+	if ext, ok := t.(schema.ExtensionType); ok && ext.Name == "decimal" {
+		msg += "; compare decimals with lessThan, lessThanOrEqual, greaterThan, or greaterThanOrEqual"
+	}
+	return msg
+}
+
 // typecheckArithmetic handles +, -, * operators
 func (ctx *typeContext) typecheckArithmetic(node ast.IsNode) schema.CedarType {
 	var left, right ast.IsNode
@@ -683,11 +879,7 @@ func (ctx *typeContext) typecheckIn(n ast.NodeTypeIn) schema.CedarType {
 			fmt.Sprintf("unexpectedType: 'in' operator left operand must be entity, got %s", leftType))
 	}
 
-	// Right must be an entity or set of entities
-	if !isTypeEntity(rightType) && !isTypeSet(rightType) && !isTypeUnknown(rightType) {
-		ctx.errors = append(ctx.errors,
-			fmt.Sprintf("unexpectedType: 'in' operator right operand must be entity or set, got %s", rightType))
-	}
+	ctx.checkInRightOperand(rightType)
 
 	// Check for impossible "in" relationships in conditions.
 	// When the left operand is principal or resource, and the right operand is
@@ -698,6 +890,23 @@ func (ctx *typeContext) typecheckIn(n ast.NodeTypeIn) schema.CedarType {
 	return schema.BoolType{}
 }
 
+// checkInRightOperand checks that the right operand of 'in' is an entity, for
+// an ancestry test, or a set of entities, for an ancestry test against each
+// element.
+func (ctx *typeContext) checkInRightOperand(rightType schema.CedarType) {
+	if set, ok := rightType.(schema.SetType); ok {
+		if !isTypeEntity(set.Element) && !isTypeUnknown(set.Element) {
+			ctx.errors = append(ctx.errors,
+				fmt.Sprintf("unexpectedType: 'in' operator right operand must be a set of entities, got %s", rightType))
+		}
+		return
+	}
+	if !isTypeEntity(rightType) && !isTypeUnknown(rightType) {
+		ctx.errors = append(ctx.errors,
+			fmt.Sprintf("unexpectedType: 'in' operator right operand must be entity or set, got %s", rightType))
+	}
+}
+
 // checkImpossibleInRelationship detects when an "in" relationship is impossible.
 // For example, "principal in Type3::X" is impossible if principal's type has no
 // memberOfTypes chain that includes Type3.
@@ -728,9 +937,9 @@ func (ctx *typeContext) getPossibleTypesForVariable(node ast.IsNode) ([]types.En
 
 	switch string(varNode.Name) {
 	case "principal":
-		return ctx.principalTypes, "principal"
+		return ctx.narrowTypes("principal", ctx.principalTypes), "principal"
 	case "resource":
-		return ctx.resourceTypes, "resource"
+		return ctx.narrowTypes("resource", ctx.resourceTypes), "resource"
 	default:
 		return nil, ""
 	}
@@ -814,11 +1023,20 @@ func (ctx *typeContext) typecheckAccess(n ast.NodeTypeAccess) schema.CedarType {
 	baseType := ctx.typecheckWithoutLevelIncrement(n.Arg)
 	attrName := string(n.Value)
 
+	guarded := ctx.hasCapability(n)
+
+	if candidates, varName := ctx.getPossibleTypesForVariable(n.Arg); len(candidates) > 1 {
+		return ctx.typecheckMultiEntityAttrAccess(varName, candidates, attrName, guarded)
+	}
+
 	switch t := baseType.(type) {
 	case schema.EntityCedarType:
-		return ctx.typecheckEntityAttrAccess(t, attrName)
+		return ctx.typecheckEntityAttrAccess(t, attrName, guarded)
 	case schema.RecordType:
-		return ctx.typecheckRecordAttrAccess(t, attrName)
+		if v, ok := n.Arg.(ast.NodeTypeVariable); ok && v.Name == "context" {
+			return ctx.typecheckContextAttrAccess(t, attrName, guarded)
+		}
+		return ctx.typecheckRecordAttrAccess(t, attrName, guarded)
 	case schema.UnknownType:
 		return schema.UnknownType{}
 	default:
@@ -829,7 +1047,8 @@ func (ctx *typeContext) typecheckAccess(n ast.NodeTypeAccess) schema.CedarType {
 }
 
 // typecheckEntityAttrAccess handles attribute access on entity types.
-func (ctx *typeContext) typecheckEntityAttrAccess(t schema.EntityCedarType, attrName string) schema.CedarType {
+// If guarded is true, an enclosing `has` check has established that the attribute is present.
+func (ctx *typeContext) typecheckEntityAttrAccess(t schema.EntityCedarType, attrName string, guarded bool) schema.CedarType {
 	info, ok := ctx.v.entityTypes[t.Name]
 	if !ok {
 		ctx.errors = append(ctx.errors,
@@ -844,15 +1063,101 @@ func (ctx *typeContext) typecheckEntityAttrAccess(t schema.EntityCedarType, attr
 		return schema.UnknownType{}
 	}
 
-	if !attr.Required {
+	if !attr.Required && !guarded {
 		ctx.errors = append(ctx.errors,
 			fmt.Sprintf("attrNotFound: attribute '%s' on entity type %s is optional; use `has` to check for its presence first", attrName, t.Name))
 	}
 	return attr.Type
 }
 
+// typecheckMultiEntityAttrAccess handles attribute access on a principal or
+// resource that may have any of several entity types. The access can only
+// succeed for every request if the attribute is declared with the same type
+// on all candidates. Missing (when not guarded by `has`) and conflicting
+// declarations are reported as warnings when the validator was configured
+// with WithMultiTypeAttributeWarnings and as errors otherwise.
+func (ctx *typeContext) typecheckMultiEntityAttrAccess(varName string, candidates []types.EntityType, attrName string, guarded bool) schema.CedarType {
+	candidates = slices.Sorted(slices.Values(candidates))
+	var declared []types.EntityType
+	var missing []string
+	for _, et := range candidates {
+		if info, ok := ctx.v.entityTypes[et]; ok {
+			if _, ok := info.Attributes[attrName]; ok {
+				declared = append(declared, et)
+				continue
+			}
+		}
+		missing = append(missing, string(et))
+	}
+	if len(declared) == 0 {
+		ctx.errors = append(ctx.errors,
+			fmt.Sprintf("attrNotFound: no possible type of %s has attribute '%s'", varName, attrName))
+		return schema.UnknownType{}
+	}
+	if len(missing) > 0 && !guarded {
+		ctx.reportMultiType(fmt.Sprintf("attrNotFound: attribute '%s' is not declared on every possible type of %s (missing on %s)",
+			attrName, varName, strings.Join(missing, ", ")))
+	}
+	return ctx.unifyEntityAttrTypes(varName, declared, attrName, guarded)
+}
+
+// unifyEntityAttrTypes returns the type of attrName shared by the given
+// entity types, reporting optional attributes accessed without a `has` guard
+// and declarations whose types conflict.
+func (ctx *typeContext) unifyEntityAttrTypes(varName string, declared []types.EntityType, attrName string, guarded bool) schema.CedarType {
+	first := ctx.v.entityTypes[declared[0]].Attributes[attrName]
+	for _, et := range declared {
+		attr := ctx.v.entityTypes[et].Attributes[attrName]
+		if !attr.Required && !guarded {
+			ctx.errors = append(ctx.errors,
+				fmt.Sprintf("attrNotFound: attribute '%s' on entity type %s is optional; use `has` to check for its presence first", attrName, et))
+		}
+		if !schema.TypesMatch(first.Type, attr.Type) {
+			ctx.reportMultiType(fmt.Sprintf("incompatibleAttrTypes: attribute '%s' of %s has type %s on %s but %s on %s",
+				attrName, varName, first.Type, declared[0], attr.Type, et))
+			return schema.UnknownType{}
+		}
+	}
+	return first.Type
+}
+
+// reportMultiType records a finding of the multi-type attribute check.
+func (ctx *typeContext) reportMultiType(msg string) {
+	if ctx.v.multiTypeAttributeWarnings {
+		ctx.warnings = append(ctx.warnings, msg)
+	} else {
+		ctx.errors = append(ctx.errors, msg)
+	}
+}
+
+// typecheckContextAttrAccess handles attribute access on the context. When
+// the policy applies to several actions, the context type is the intersection
+// of their declared contexts, so an attribute missing from it is reported
+// along with the actions that lack it.
+func (ctx *typeContext) typecheckContextAttrAccess(t schema.RecordType, attrName string, guarded bool) schema.CedarType {
+	if _, ok := t.Attributes[attrName]; ok || len(ctx.contextActions) < 2 {
+		return ctx.typecheckRecordAttrAccess(t, attrName, guarded)
+	}
+	var missing []string
+	for _, uid := range ctx.contextActions {
+		if _, ok := ctx.v.actionTypes[uid].Context.Attributes[attrName]; !ok {
+			missing = append(missing, uid.String())
+		}
+	}
+	if len(missing) > 0 {
+		ctx.errors = append(ctx.errors,
+			fmt.Sprintf("attrNotFound: context attribute '%s' is not declared for every action the policy applies to (missing for %s)",
+				attrName, strings.Join(missing, ", ")))
+	} else {
+		ctx.errors = append(ctx.errors,
+			fmt.Sprintf("attrNotFound: context attribute '%s' has different types across the actions the policy applies to", attrName))
+	}
+	return schema.UnknownType{}
+}
+
 // typecheckRecordAttrAccess handles attribute access on record types.
-func (ctx *typeContext) typecheckRecordAttrAccess(t schema.RecordType, attrName string) schema.CedarType {
+// If guarded is true, an enclosing `has` check has established that the attribute is present.
+func (ctx *typeContext) typecheckRecordAttrAccess(t schema.RecordType, attrName string, guarded bool) schema.CedarType {
 	attr, ok := t.Attributes[attrName]
 	if !ok {
 		// If we have a known record type (Attributes is not nil), accessing a
@@ -865,13 +1170,114 @@ func (ctx *typeContext) typecheckRecordAttrAccess(t schema.RecordType, attrName
 		return schema.UnknownType{}
 	}
 
-	if !attr.Required {
+	if !attr.Required && !guarded {
 		ctx.errors = append(ctx.errors,
 			fmt.Sprintf("attrNotFound: attribute '%s' is optional; use `has` to check for its presence first", attrName))
 	}
 	return attr.Type
 }
 
+// ============================================================================
+// Capability Tracking
+// ============================================================================
+
+// guardsOf returns the access paths that are known to be present whenever
+// the given boolean expression evaluates to true, along with the `is` tests
+// on variables that then hold.
+func (ctx *typeContext) guardsOf(node ast.IsNode) map[string]bool {
+	switch n := node.(type) {
+	case ast.NodeTypeHas:
+		if path, ok := ast.AttributePathOf(n); ok {
+			return map[string]bool{path.String(): true}
+		}
+	case ast.NodeTypeIs:
+		return typeGuard(n)
+	case ast.NodeTypeIsIn:
+		return typeGuard(n.NodeTypeIs)
+	case ast.NodeTypeAnd:
+		guards := ctx.guardsOf(n.Left)
+		maps.Copy(guards, ctx.guardsOf(n.Right))
+		return guards
+	case ast.NodeTypeOr:
+		return intersectGuards(ctx.guardsOf(n.Left), ctx.guardsOf(n.Right))
+	case ast.NodeTypeIfThenElse:
+		thenGuards := ctx.guardsOf(n.If)
+		maps.Copy(thenGuards, ctx.guardsOf(n.Then))
+		return intersectGuards(thenGuards, ctx.guardsOf(n.Else))
+	}
+	return map[string]bool{}
+}
+
+// typeGuard returns the guard established by an `is` test on a variable,
+// such as "principal is User", which narrows the possible types of the
+// variable while the test holds.
+func typeGuard(n ast.NodeTypeIs) map[string]bool {
+	if v, ok := n.Left.(ast.NodeTypeVariable); ok {
+		return map[string]bool{typeGuardKey(string(v.Name), n.EntityType): true}
+	}
+	return map[string]bool{}
+}
+
+func typeGuardKey(varName string, et types.EntityType) string {
+	return varName + " is " + string(et)
+}
+
+// narrowTypes returns the types of candidates that the `is` guards on
+// varName allow. Guards that no candidate satisfies are left to the checks
+// for impossible policies, and candidates is returned unchanged.
+func (ctx *typeContext) narrowTypes(varName string, candidates []types.EntityType) []types.EntityType {
+	narrowed := candidates
+	for key := range ctx.capabilities {
+		if et, ok := strings.CutPrefix(key, varName+" is "); ok {
+			narrowed = slices.DeleteFunc(slices.Clone(narrowed), func(c types.EntityType) bool {
+				return string(c) != et
+			})
+		}
+	}
+	if len(narrowed) == 0 {
+		return candidates
+	}
+	return narrowed
+}
+
+// intersectGuards returns the access paths present in both guard sets.
+func intersectGuards(a, b map[string]bool) map[string]bool {
+	result := make(map[string]bool)
+	for path := range a {
+		if b[path] {
+			result[path] = true
+		}
+	}
+	return result
+}
+
+// addCapabilities records access paths as known to be present.
+func (ctx *typeContext) addCapabilities(guards map[string]bool) {
+	if len(guards) == 0 {
+		return
+	}
+	if ctx.capabilities == nil {
+		ctx.capabilities = make(map[string]bool)
+	}
+	maps.Copy(ctx.capabilities, guards)
+}
+
+// typecheckWithCapabilities type-checks a node with additional access paths
+// known to be present, restoring the previous capabilities afterwards.
+func (ctx *typeContext) typecheckWithCapabilities(node ast.IsNode, guards map[string]bool) schema.CedarType {
+	saved := ctx.capabilities
+	ctx.capabilities = maps.Clone(saved)
+	ctx.addCapabilities(guards)
+	defer func() { ctx.capabilities = saved }()
+	return ctx.typecheck(node)
+}
+
+// hasCapability reports whether an attribute access is guarded by a `has` check.
+func (ctx *typeContext) hasCapability(n ast.NodeTypeAccess) bool {
+	path, ok := ast.AttributePathOf(n)
+	return ok && ctx.capabilities[path.String()]
+}
+
 // typecheckWithoutLevelIncrement is used for nested access to avoid double counting
 func (ctx *typeContext) typecheckWithoutLevelIncrement(node ast.IsNode) schema.CedarType {
 	if node == nil {
@@ -890,13 +1296,14 @@ func (ctx *typeContext) typecheckWithoutLevelIncrement(node ast.IsNode) schema.C
 // typecheckSetOp handles contains, containsAll, containsAny
 func (ctx *typeContext) typecheckSetOp(node ast.IsNode) schema.CedarType {
 	var left, right ast.IsNode
+	var op string
 	switch n := node.(type) {
 	case ast.NodeTypeContains:
-		left, right = n.Left, n.Right
+		left, right, op = n.Left, n.Right, "contains"
 	case ast.NodeTypeContainsAll:
-		left, right = n.Left, n.Right
+		left, right, op = n.Left, n.Right, "containsAll"
 	case ast.NodeTypeContainsAny:
-		left, right = n.Left, n.Right
+		left, right, op = n.Left, n.Right, "containsAny"
 	}
 
 	leftType := ctx.typecheck(left)
@@ -906,11 +1313,36 @@ func (ctx *typeContext) typecheckSetOp(node ast.IsNode) schema.CedarType {
 		ctx.errors = append(ctx.errors,
 			fmt.Sprintf("unexpectedType: set operation requires Set operand, got %s", leftType))
 	}
-
-	_ = rightType // Right operand type depends on the specific operation
+	ctx.checkSetOpArgument(op, leftType, rightType)
 	return schema.BoolType{}
 }
 
+// checkSetOpArgument checks the argument of contains, which must be an
+// element, or of containsAll and containsAny, which must be a set, against
+// the element type of the receiver.
+func (ctx *typeContext) checkSetOpArgument(op string, leftType, rightType schema.CedarType) {
+	elemType := rightType
+	if op != "contains" {
+		set, ok := rightType.(schema.SetType)
+		if !ok {
+			if !isTypeUnknown(rightType) {
+				ctx.errors = append(ctx.errors,
+					fmt.Sprintf("unexpectedType: %s expects a Set argument, got %s", op, rightType))
+			}
+			return
+		}
+		elemType = set.Element
+	}
+	leftSet, ok := leftType.(schema.SetType)
+	if !ok || isTypeUnknown(leftSet.Element) || isTypeUnknown(elemType) {
+		return
+	}
+	if !ctx.typesAreComparable(leftSet.Element, elemType) {
+		ctx.errors = append(ctx.errors,
+			fmt.Sprintf("lubErr: %s argument of type %s is incompatible with %s", op, rightType, leftType))
+	}
+}
+
 // typecheckExtensionCall handles extension function calls
 func (ctx *typeContext) typecheckExtensionCall(n ast.NodeTypeExtensionCall) schema.CedarType {
 	// Type-check all arguments and collect their types
@@ -920,86 +1352,50 @@ func (ctx *typeContext) typecheckExtensionCall(n ast.NodeTypeExtensionCall) sche
 	}
 
 	funcName := string(n.Name)
-
-	// Validate argument types and determine return type based on function name
-	switch funcName {
-	// IP address constructor: ip(String) -> ipaddr
-	case "ip", "ipaddr":
-		ctx.expectArgs(funcName, argTypes, schema.StringType{})
-		// Validate IP address literal if argument is a literal string
-		ctx.validateExtensionLiteral(n.Args, "ip", isValidIPLiteral)
-		return schema.ExtensionType{Name: "ipaddr"}
-
-	// IP address methods (called on ipaddr, no additional args)
-	case "isIpv4", "isIpv6", "isLoopback", "isMulticast":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "ipaddr"})
-		return schema.BoolType{}
-
-	// isInRange: ipaddr.isInRange(ipaddr) -> Bool
-	case "isInRange":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "ipaddr"}, schema.ExtensionType{Name: "ipaddr"})
-		return schema.BoolType{}
-
-	// Decimal constructor: decimal(String) -> decimal
-	case "decimal":
-		ctx.expectArgs(funcName, argTypes, schema.StringType{})
-		ctx.validateExtensionLiteral(n.Args, "decimal", isValidDecimalLiteral)
-		return schema.ExtensionType{Name: "decimal"}
-
-	// Decimal comparison methods: decimal.lessThan(decimal) -> Bool
-	case "lessThan", "lessThanOrEqual", "greaterThan", "greaterThanOrEqual":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "decimal"}, schema.ExtensionType{Name: "decimal"})
-		return schema.BoolType{}
-
-	// Datetime constructor: datetime(String) -> datetime
-	case "datetime":
-		ctx.expectArgs(funcName, argTypes, schema.StringType{})
-		ctx.validateExtensionLiteral(n.Args, "datetime", isValidDatetimeLiteral)
-		return schema.ExtensionType{Name: "datetime"}
-
-	// Duration constructor: duration(String) -> duration
-	case "duration":
-		ctx.expectArgs(funcName, argTypes, schema.StringType{})
-		ctx.validateExtensionLiteral(n.Args, "duration", isValidDurationLiteral)
-		return schema.ExtensionType{Name: "duration"}
-
-	// Datetime arithmetic: datetime.offset(duration) -> datetime
-	case "offset":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "datetime"}, schema.ExtensionType{Name: "duration"})
-		return schema.ExtensionType{Name: "datetime"}
-
-	// Datetime difference: datetime.durationSince(datetime) -> duration
-	case "durationSince":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "datetime"}, schema.ExtensionType{Name: "datetime"})
-		return schema.ExtensionType{Name: "duration"}
-
-	// Datetime extraction methods (called on datetime, no additional args)
-	case "toDate", "toTime":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "datetime"})
-		return schema.ExtensionType{Name: "datetime"}
-
-	// Duration conversion methods (called on duration, no additional args)
-	case "toDays", "toHours", "toMinutes", "toSeconds", "toMilliseconds":
-		ctx.expectArgs(funcName, argTypes, schema.ExtensionType{Name: "duration"})
-		return schema.LongType{}
-
-	default:
+	sig, ok := extSignatures[funcName]
+	if !ok {
 		return schema.UnknownType{}
 	}
+	ctx.expectArgs(funcName, sig, argTypes)
+	if sig.literal != nil {
+		ctx.validateExtensionLiteral(n.Args, funcName, sig.literal)
+	}
+	return sig.ret
 }
 
-// expectArgs validates that the provided argument types match the expected types.
-// If there's a mismatch, it reports a type error.
-func (ctx *typeContext) expectArgs(funcName string, actual []schema.CedarType, expected ...schema.CedarType) {
-	if len(actual) != len(expected) {
+// expectArgs validates that the provided argument types match the signature.
+// If there's a mismatch, it reports a type error. For methods the receiver is
+// not counted as an argument.
+func (ctx *typeContext) expectArgs(funcName string, sig extSignature, actual []schema.CedarType) {
+	if len(actual) != len(sig.params) {
+		kind, want, got := "function", len(sig.params), len(actual)
+		if sig.isMethod {
+			kind, want, got = "method", want-1, max(got-1, 0)
+		}
+		noun := "arguments"
+		if want == 1 {
+			noun = "argument"
+		}
 		ctx.errors = append(ctx.errors,
-			fmt.Sprintf("extensionErr: %s() expects %d argument(s), got %d", funcName, len(expected), len(actual)))
+			fmt.Sprintf("extensionErr: %s %s expects %d %s, got %d", kind, funcName, want, noun, got))
 		return
 	}
 
-	for i, exp := range expected {
+	for i, exp := range sig.params {
 		act := actual[i]
-		if !isTypeUnknown(act) && !schema.TypesMatch(exp, act) {
+		if isTypeUnknown(act) || schema.TypesMatch(exp, act) {
+			continue
+		}
+		switch {
+		case sig.isMethod && i == 0:
+			// The receiver is often the result of another method call, so
+			// name it as such to make mistakes in method chains obvious.
+			ctx.errors = append(ctx.errors,
+				fmt.Sprintf("extensionErr: %s() called on %s, expected %s", funcName, act, exp))
+		case sig.isMethod:
+			ctx.errors = append(ctx.errors,
+				fmt.Sprintf("extensionErr: %s() argument %d: expected %s, got %s", funcName, i, exp, act))
+		default:
 			ctx.errors = append(ctx.errors,
 				fmt.Sprintf("extensionErr: %s() argument %d: expected %s, got %s", funcName, i+1, exp, act))
 		}
@@ -1087,6 +1483,13 @@ func isTypeLong(t schema.CedarType) bool {
 	return ok
 }
 
+// isTypeOrderedExtension reports whether t is an extension type that
+// supports the comparison operators.
+func isTypeOrderedExtension(t schema.CedarType) bool {
+	ext, ok := t.(schema.ExtensionType)
+	return ok && (ext.Name == "datetime" || ext.Name == "duration")
+}
+
 // isTypeString returns true if the type is StringType.
 func isTypeString(t schema.CedarType) bool {
 	_, ok := t.(schema.StringType)
@@ -15,10 +15,13 @@
 package validator
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/cedar-policy/cedar-go"
+	"github.com/cedar-policy/cedar-go/types"
+	"github.com/cedar-policy/cedar-go/x/exp/ast"
 	"github.com/cedar-policy/cedar-go/x/exp/schema"
 )
 
@@ -274,7 +277,9 @@ func TestTypecheckConditionals(t *testing.T) {
 						"type": "Record",
 						"attributes": {
 							"active": {"type": "Boolean", "required": true},
-							"premium": {"type": "Boolean", "required": true}
+							"premium": {"type": "Boolean", "required": true},
+							"name": {"type": "String", "required": true},
+							"roles": {"type": "Set", "element": {"type": "String"}, "required": true}
 						}
 					}
 				},
@@ -300,6 +305,7 @@ func TestTypecheckConditionals(t *testing.T) {
 		name        string
 		policy      string
 		expectValid bool
+		errorSubstr string
 	}{
 		{
 			name:        "valid if-then-else",
@@ -311,14 +317,45 @@ func TestTypecheckConditionals(t *testing.T) {
 			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { !principal.active || principal.premium };`,
 			expectValid: true,
 		},
+		{
+			name:        "Long branches",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { (if principal.premium then 100 else 10) > 50 };`,
+			expectValid: true,
+		},
+		{
+			name:        "String branches",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { (if principal.premium then principal.name else "guest") like "a*" };`,
+			expectValid: true,
+		},
+		{
+			name:        "Set branches",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { (if principal.premium then principal.roles else ["viewer"]).contains("admin") };`,
+			expectValid: true,
+		},
+		{
+			name:        "Long branches used as String",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { (if principal.premium then 100 else 10) like "1*" };`,
+			expectValid: false,
+			errorSubstr: "like operator requires String operand, got Long",
+		},
+		{
+			name:        "mismatched branches",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { (if principal.premium then "gold" else 10) == 10 };`,
+			expectValid: false,
+			errorSubstr: "lubErr: if-then-else branches have incompatible types: String and Long",
+		},
+		{
+			name:        "mismatched Set branches",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { (if principal.premium then principal.roles else [1]).contains("admin") };`,
+			expectValid: false,
+			errorSubstr: "lubErr",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			result := validatePolicyString(t, s, tc.policy)
-			if tc.expectValid && !result.Valid {
-				t.Errorf("Expected valid, got errors: %v", result.Errors)
-			}
+			checkPolicyResult(t, result, tc.expectValid, tc.errorSubstr)
 		})
 	}
 }
@@ -331,7 +368,17 @@ func TestTypecheckInOperator(t *testing.T) {
 					"memberOfTypes": ["Group"]
 				},
 				"Group": {},
-				"Document": {}
+				"Document": {
+					"memberOfTypes": ["User"],
+					"shape": {
+						"type": "Record",
+						"attributes": {
+							"group": {"type": "Entity", "name": "Group", "required": true},
+							"owners": {"type": "Set", "element": {"type": "Entity", "name": "User"}, "required": true},
+							"name": {"type": "String", "required": true}
+						}
+					}
+				}
 			},
 			"actions": {
 				"view": {
@@ -353,6 +400,7 @@ func TestTypecheckInOperator(t *testing.T) {
 		name        string
 		policy      string
 		expectValid bool
+		errorSubstr string
 	}{
 		{
 			name:        "valid in operator",
@@ -369,6 +417,31 @@ func TestTypecheckInOperator(t *testing.T) {
 			policy:      `permit(principal is User in Group::"admins", action == Action::"view", resource);`,
 			expectValid: true,
 		},
+		{
+			name:        "resource in principal",
+			policy:      `permit(principal, action == Action::"view", resource) when { resource in principal };`,
+			expectValid: true,
+		},
+		{
+			name:        "principal in resource attribute",
+			policy:      `permit(principal, action == Action::"view", resource) when { principal in resource.group };`,
+			expectValid: true,
+		},
+		{
+			name:        "principal in resource set attribute",
+			policy:      `permit(principal, action == Action::"view", resource) when { principal in resource.owners };`,
+			expectValid: true,
+		},
+		{
+			name:        "principal in non-entity resource attribute",
+			policy:      `permit(principal, action == Action::"view", resource) when { principal in resource.name };`,
+			errorSubstr: "'in' operator right operand must be entity or set, got String",
+		},
+		{
+			name:        "non-entity resource attribute in principal",
+			policy:      `permit(principal, action == Action::"view", resource) when { resource.name in principal };`,
+			errorSubstr: "'in' operator left operand must be entity, got String",
+		},
 	}
 
 	for _, tc := range tests {
@@ -377,6 +450,9 @@ func TestTypecheckInOperator(t *testing.T) {
 			if tc.expectValid && !result.Valid {
 				t.Errorf("Expected valid, got errors: %v", result.Errors)
 			}
+			if tc.errorSubstr != "" {
+				checkPolicyResult(t, result, false, tc.errorSubstr)
+			}
 		})
 	}
 }
@@ -468,6 +544,7 @@ func TestTypecheckExtensionCalls(t *testing.T) {
 		name        string
 		policy      string
 		expectValid bool
+		errorSubstr string
 	}{
 		{
 			name:        "valid ip function",
@@ -479,6 +556,31 @@ func TestTypecheckExtensionCalls(t *testing.T) {
 			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { decimal("10.5").greaterThan(decimal("5.0")) };`,
 			expectValid: true,
 		},
+		{
+			name:        "decimal attribute method comparison",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { principal.balance.greaterThan(decimal("5.0")) && principal.balance.lessThanOrEqual(decimal("100.0")) };`,
+			expectValid: true,
+		},
+		{
+			name:        "decimal greater than Long",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { principal.balance > 5 };`,
+			errorSubstr: "comparison operator requires Long operands, got decimal; compare decimals with",
+		},
+		{
+			name:        "Long less than decimal",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { 5 < principal.balance };`,
+			errorSubstr: "got decimal; compare decimals with",
+		},
+		{
+			name:        "decimal greater than decimal",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { principal.balance > decimal("5.0") };`,
+			errorSubstr: "got decimal; compare decimals with",
+		},
+		{
+			name:        "decimal method with Long argument",
+			policy:      `permit(principal == User::"alice", action == Action::"view", resource) when { principal.balance.greaterThan(5) };`,
+			errorSubstr: "greaterThan() argument 1: expected decimal, got Long",
+		},
 	}
 
 	for _, tc := range tests {
@@ -487,6 +589,9 @@ func TestTypecheckExtensionCalls(t *testing.T) {
 			if tc.expectValid && !result.Valid {
 				t.Errorf("Expected valid, got errors: %v", result.Errors)
 			}
+			if tc.errorSubstr != "" {
+				checkPolicyResult(t, result, false, tc.errorSubstr)
+			}
 		})
 	}
 }
@@ -981,7 +1086,7 @@ func TestTypecheckExtensionCallAllFunctions(t *testing.T) {
 		{"offset", `permit(principal, action, resource) when { datetime("2024-01-01").offset(duration("1d")) == datetime("2024-01-02") };`},
 		{"durationSince", `permit(principal, action, resource) when { datetime("2024-01-02").durationSince(datetime("2024-01-01")) == duration("1d") };`},
 		{"toDate", `permit(principal, action, resource) when { datetime("2024-01-01T12:00:00Z").toDate() == datetime("2024-01-01") };`},
-		{"toTime", `permit(principal, action, resource) when { datetime("2024-01-01T12:00:00Z").toTime() == datetime("12:00:00") };`},
+		{"toTime", `permit(principal, action, resource) when { datetime("2024-01-01T12:00:00Z").toTime() == duration("12h") };`},
 		{"toDays", `permit(principal, action, resource) when { duration("2d").toDays() == 2 };`},
 		{"toHours", `permit(principal, action, resource) when { duration("2h").toHours() == 2 };`},
 		{"toMinutes", `permit(principal, action, resource) when { duration("2m").toMinutes() == 2 };`},
@@ -1322,6 +1427,81 @@ func TestTypecheckSetLiteralEmpty(t *testing.T) {
 	}
 }
 
+// TestTypecheckSetLiteralHeterogeneous tests that set literals with elements
+// of incompatible types are reported, as errors by default and as warnings
+// with WithHeterogeneousSetWarnings.
+func TestTypecheckSetLiteralHeterogeneous(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User { tags: Set<String> };
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		policy string
+		mixed  bool
+	}{
+		{"homogeneous literals", `permit(principal, action, resource) when { [1, 2, 3].contains(1) };`, false},
+		{"homogeneous expressions", `permit(principal, action, resource) when { [principal, resource].contains(principal) };`, false},
+		{"mixed literals", `permit(principal, action, resource) when { [1, "two"].contains(1) };`, true},
+		{"mixed with expression", `permit(principal, action, resource) when { [1, principal.tags].contains(1) };`, true},
+		{"nested mixed sets", `permit(principal, action, resource) when { [[1], ["a"]].contains([1]) };`, true},
+		{"mixed inside record", `permit(principal, action, resource) when { {a: [true, 1]} has a };`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy cedar.Policy
+			if err := policy.UnmarshalCedar([]byte(tt.policy)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			policies := cedar.NewPolicySet()
+			policies.Add("test", &policy)
+
+			result := ValidatePolicies(s, policies)
+			if !tt.mixed {
+				checkPolicyResult(t, result, true, "")
+				return
+			}
+			checkPolicyResult(t, result, false, "incompatibleSetTypes")
+
+			result = ValidatePolicies(s, policies, WithHeterogeneousSetWarnings())
+			if !result.Valid {
+				t.Errorf("Expected valid with warnings, got errors: %v", result.Errors)
+			}
+			if !hasErrorContaining(result.Warnings, "incompatibleSetTypes") {
+				t.Errorf("Expected incompatibleSetTypes warning, got: %v", result.Warnings)
+			}
+		})
+	}
+}
+
+// TestTypecheckSetValueHeterogeneous tests that constant set values built
+// programmatically are checked the same way as set literals.
+func TestTypecheckSetValueHeterogeneous(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	v, err := New(s)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	mixed := types.NewSet(types.Long(1), types.String("two"))
+	p := ast.Permit().When(ast.Value(mixed).Contains(ast.Long(1)))
+	errs, _ := v.typecheckPolicy(p)
+	if len(errs) != 1 || !strings.Contains(errs[0], "incompatibleSetTypes: set elements have incompatible types") {
+		t.Errorf("Expected one incompatibleSetTypes error, got: %v", errs)
+	}
+}
+
 func TestTypecheckWithNilNode(t *testing.T) {
 
 	schemaJSON := `{
@@ -1405,8 +1585,111 @@ func TestTypecheckVariableMultiplePrincipalTypes(t *testing.T) {
 	policies.Add("test", &policy)
 
 	result := ValidatePolicies(s, policies)
+	checkPolicyResult(t, result, true, "")
+}
 
-	_ = result
+// TestTypecheckContextActionSet tests that a policy over several actions may
+// only access context attributes that every one of those actions declares.
+func TestTypecheckContextActionSet(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		entity Doc;
+		action all;
+		action view in [all] appliesTo { principal: User, resource: Doc, context: { ip: ipaddr, reason: String } };
+		action edit in [all] appliesTo { principal: User, resource: Doc, context: { ip: ipaddr, reason: Long } };
+		action share appliesTo { principal: User, resource: Doc, context: { ip: ipaddr, target: User } };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		policy    string
+		wantError string
+	}{
+		{"shared attribute in set", `permit(principal, action in [Action::"view", Action::"share"], resource) when { context.ip.isLoopback() };`, ""},
+		{"missing in set", `permit(principal, action in [Action::"view", Action::"share"], resource) when { context.target == principal };`,
+			`context attribute 'target' is not declared for every action the policy applies to (missing for Action::"view")`},
+		{"conflicting types in set", `permit(principal, action in [Action::"view", Action::"edit"], resource) when { context.reason == "x" };`,
+			"context attribute 'reason' has different types across the actions"},
+		{"shared attribute in group", `permit(principal, action in Action::"all", resource) when { context.ip.isLoopback() };`, ""},
+		{"group expands to members", `permit(principal, action in [Action::"all", Action::"share"], resource) when { context.target == principal };`,
+			`(missing for Action::"edit", Action::"view")`},
+		{"single action", `permit(principal, action in [Action::"share"], resource) when { context.target == principal };`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validatePolicyString(t, s, tt.policy)
+			checkPolicyResult(t, result, tt.wantError == "", tt.wantError)
+		})
+	}
+}
+
+// TestTypecheckMultiplePrincipalTypesAttributes tests that attribute accesses
+// on a principal or resource with several possible types are checked against
+// every one of them.
+func TestTypecheckMultiplePrincipalTypesAttributes(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User { name: String, email: String, level?: Long };
+		entity Admin { name: String, email: Long, level?: Long, root: Bool };
+		entity Doc { owner: String };
+		entity Folder { owner: String, size: Long };
+		action view appliesTo { principal: [User, Admin], resource: [Doc, Folder] };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		wantError string
+		warnable  bool
+	}{
+		{"shared attribute", `principal.name == "a"`, "", false},
+		{"shared resource attribute", `resource.owner == "a"`, "", false},
+		{"guarded optional", `principal has level && principal.level > 1`, "", false},
+		{"guarded partial", `principal has root && principal.root`, "", false},
+		{"missing on some", `principal.root`, "attrNotFound: attribute 'root' is not declared on every possible type of principal (missing on User)", true},
+		{"missing on some resource", `resource.size > 0`, "missing on Doc", true},
+		{"missing on all", `principal.nope == 1`, "attrNotFound: no possible type of principal has attribute 'nope'", false},
+		{"conflicting types", `principal.email == "a"`, "incompatibleAttrTypes: attribute 'email' of principal has type Long on Admin but String on User", true},
+		{"unguarded optional", `principal.level > 1`, "is optional", false},
+		{"narrowed by is", `principal is Admin && principal.root`, "", false},
+		{"narrowed resource", `resource is Folder && resource.size > 0`, "", false},
+		{"narrowed in later clause", `principal is Admin } when { principal.root`, "", false},
+		{"narrowed type", `principal is Admin && principal.email == "a"`, "cannot compare Long with String", false},
+		{"narrowed by either branch", `(principal is Admin || principal is Admin) && principal.root`, "", false},
+		{"narrowed in then branch", `if principal is Admin then principal.root else false`, "", false},
+		{"not narrowed by or", `(principal is Admin || principal.name == "a") && principal.root`, "missing on User", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := `permit(principal, action == Action::"view", resource) when { ` + tt.condition + ` };`
+			result := validatePolicyString(t, s, policy)
+			checkPolicyResult(t, result, tt.wantError == "", tt.wantError)
+			if !tt.warnable {
+				return
+			}
+
+			var p cedar.Policy
+			if err := p.UnmarshalCedar([]byte(policy)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			policies := cedar.NewPolicySet()
+			policies.Add("test", &p)
+			result = ValidatePolicies(s, policies, WithMultiTypeAttributeWarnings())
+			if !result.Valid {
+				t.Errorf("Expected valid with warnings, got errors: %v", result.Errors)
+			}
+			if !hasErrorContaining(result.Warnings, tt.wantError) {
+				t.Errorf("Expected warning containing %q, got: %v", tt.wantError, result.Warnings)
+			}
+		})
+	}
 }
 
 func TestTypecheckResourceVariable(t *testing.T) {
@@ -1497,6 +1780,65 @@ func TestTypecheckUnknownVariableName(t *testing.T) {
 	}
 }
 
+func TestTypecheckContextRecordLiteral(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		action login appliesTo {
+			principal: User,
+			resource: User,
+			context: { ip: String, authenticated: Bool, device?: String }
+		};
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cond    string
+		wantErr string
+	}{
+		{"matching literal", `context == { ip: "x", authenticated: true }`, ""},
+		{"matching literal with optional attribute", `context == { ip: "x", authenticated: true, device: "phone" }`, ""},
+		{"literal on the left", `{ authenticated: true, ip: "x" } != context`, ""},
+		{"missing required attribute", `context == { ip: "x" }`,
+			`lubErr: context record literal is missing required attribute "authenticated"`},
+		{"extra attribute", `context == { ip: "x", authenticated: true, admin: true }`,
+			`lubErr: context record literal has attribute "admin", which the context type does not declare`},
+		{"wrong attribute type", `context == { ip: "x", authenticated: "yes" }`,
+			`lubErr: context record literal attribute "authenticated" has type String, expected Bool`},
+		{"wrong optional attribute type", `{ ip: "x", authenticated: true, device: 1 } == context`,
+			`lubErr: context record literal attribute "device" has type Long, expected String`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := `permit(principal, action == Action::"login", resource) when { ` + tc.cond + ` };`
+			result := validatePolicyString(t, s, src)
+			checkPolicyResult(t, result, tc.wantErr == "", tc.wantErr)
+		})
+	}
+
+	t.Run("reports every wrong attribute", func(t *testing.T) {
+		v, err := New(s)
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+		var policy cedar.Policy
+		if err := policy.UnmarshalCedar([]byte(`permit(principal, action == Action::"login", resource) when { context == { ip: 1, extra: 2 } };`)); err != nil {
+			t.Fatalf("Failed to parse policy: %v", err)
+		}
+		errs, _ := v.typecheckPolicy((*ast.Policy)(policy.AST()))
+		want := []string{
+			`lubErr: context record literal is missing required attribute "authenticated"`,
+			`lubErr: context record literal attribute "ip" has type Long, expected String`,
+			`lubErr: context record literal has attribute "extra", which the context type does not declare`,
+		}
+		if !slices.Equal(errs, want) {
+			t.Errorf("got %q, want %q", errs, want)
+		}
+	})
+}
+
 func TestTypecheckContextWithoutActionConstraint(t *testing.T) {
 	// When action is unconstrained and actions have different contexts,
 	// accessing an attribute that doesn't exist in ALL actions' contexts
@@ -1917,7 +2259,13 @@ func TestExtensionLiteralValidation(t *testing.T) {
 				"view": {
 					"appliesTo": {
 						"principalTypes": ["User"],
-						"resourceTypes": ["User"]
+						"resourceTypes": ["User"],
+						"context": {
+							"type": "Record",
+							"attributes": {
+								"text": {"type": "String"}
+							}
+						}
 					}
 				}
 			}
@@ -1940,11 +2288,130 @@ func TestExtensionLiteralValidation(t *testing.T) {
 		{"valid decimal literal", `permit(principal, action, resource) when { decimal("10.5").lessThan(decimal("20.0")) };`, true, ""},
 		{"invalid decimal literal", `permit(principal, action, resource) when { decimal("not-a-decimal").lessThan(decimal("1.0")) };`, false, "extensionErr"},
 		{"valid datetime literal", `permit(principal, action, resource) when { datetime("2024-01-01T00:00:00Z").toDate() == datetime("2024-01-01T00:00:00Z").toDate() };`, true, ""},
-		{"invalid datetime literal", `permit(principal, action, resource) when { datetime("not-a-datetime").toDate() == datetime("2024-01-01").toDate() };`, false, "extensionErr"},
+		{"invalid datetime literal", `permit(principal, action, resource) when { datetime("not-a-datetime").toDate() == datetime("2024-01-01").toDate() };`, false, `extensionErr: invalid datetime literal: "not-a-datetime"`},
 		{"valid duration literal", `permit(principal, action, resource) when { duration("1h30m").toMinutes() > 0 };`, true, ""},
 		{"invalid duration literal", `permit(principal, action, resource) when { duration("not-a-duration").toMinutes() > 0 };`, false, "extensionErr"},
 		{"empty IP literal", `permit(principal, action, resource) when { ip("").isLoopback() };`, false, "extensionErr"},
 		{"empty decimal literal", `permit(principal, action, resource) when { decimal("").lessThan(decimal("1.0")) };`, false, "extensionErr"},
+		{"non-literal IP argument", `permit(principal, action, resource) when { ip(context.text).isLoopback() };`, true, ""},
+		{"non-literal decimal argument", `permit(principal, action, resource) when { decimal(context.text).lessThan(decimal("1.0")) };`, true, ""},
+		{"non-literal datetime argument", `permit(principal, action, resource) when { datetime(context.text) < datetime("2024-01-01") };`, true, ""},
+		{"non-literal duration argument", `permit(principal, action, resource) when { duration(context.text).toMinutes() > 0 };`, true, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runExtensionLiteralTest(t, s, tc.policy, tc.expectValid, tc.errorSubstr)
+		})
+	}
+}
+
+// TestExtensionMethodChaining tests that the return type of each extension
+// method feeds the next method in a chain.
+func TestExtensionMethodChaining(t *testing.T) {
+	schemaJSON := `{
+		"": {
+			"entityTypes": {
+				"User": {}
+			},
+			"actions": {
+				"view": {
+					"appliesTo": {
+						"principalTypes": ["User"],
+						"resourceTypes": ["User"]
+					}
+				}
+			}
+		}
+	}`
+
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		expectValid bool
+		errorSubstr string
+	}{
+		{"toDate then durationSince compared to duration", `permit(principal, action, resource) when { datetime("2024-01-01T12:00:00Z").toDate().durationSince(datetime("2024-01-01")) > duration("0s") };`, true, ""},
+		{"durationSince then toDays", `permit(principal, action, resource) when { datetime("2024-01-03").durationSince(datetime("2024-01-01")).toDays() == 2 };`, true, ""},
+		{"toTime returns duration", `permit(principal, action, resource) when { datetime("2024-01-01T12:00:00Z").toTime().toHours() == 12 };`, true, ""},
+		{"four-deep chain", `permit(principal, action, resource) when { datetime("2024-01-01").offset(duration("1d")).toDate().toTime().toMilliseconds() == 0 };`, true, ""},
+		{"datetime comparison", `permit(principal, action, resource) when { datetime("2024-01-01").offset(duration("1h")) >= datetime("2024-01-01") };`, true, ""},
+		{"toDays on datetime", `permit(principal, action, resource) when { datetime("2024-01-01T12:00:00Z").toDate().toDays() > 0 };`, false, "toDays() called on datetime, expected duration"},
+		{"toDate on Long", `permit(principal, action, resource) when { duration("1d").toDays().toDate() == datetime("2024-01-01") };`, false, "toDate() called on Long, expected datetime"},
+		{"toTime result passed to offset receiver", `permit(principal, action, resource) when { datetime("2024-01-01").toTime().offset(duration("1h")) == datetime("2024-01-01") };`, false, "offset() called on duration, expected datetime"},
+		{"wrong argument in chain", `permit(principal, action, resource) when { datetime("2024-01-01").offset(datetime("2024-01-02").toDate()) == datetime("2024-01-01") };`, false, "offset() argument 1: expected duration, got datetime"},
+		{"comparison of Long with duration", `permit(principal, action, resource) when { duration("1d").toDays() > duration("1h") };`, false, "same type"},
+		{"comparison of datetime with duration", `permit(principal, action, resource) when { datetime("2024-01-01").toDate() < datetime("2024-01-01").toTime() };`, false, "same type"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runExtensionLiteralTest(t, s, tc.policy, tc.expectValid, tc.errorSubstr)
+		})
+	}
+}
+
+// TestExtensionArity tests that calling an extension function or method with
+// the wrong number of arguments reports the expected count. Method receivers
+// are not counted as arguments.
+func TestExtensionArity(t *testing.T) {
+	schemaJSON := `{
+		"": {
+			"entityTypes": {
+				"User": {}
+			},
+			"actions": {
+				"view": {
+					"appliesTo": {
+						"principalTypes": ["User"],
+						"resourceTypes": ["User"]
+					}
+				}
+			}
+		}
+	}`
+
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		expectValid bool
+		errorSubstr string
+	}{
+		{"correct arity", `permit(principal, action, resource) when { ip("1.2.3.4").isInRange(ip("1.2.3.0/24")) && decimal("1.0").lessThan(decimal("2.0")) };`, true, ""},
+		{"ip with no arguments", `permit(principal, action, resource) when { ip() };`, false, "function ip expects 1 argument, got 0"},
+		{"ip with two arguments", `permit(principal, action, resource) when { ip("1.2.3.4", "5.6.7.8") };`, false, "function ip expects 1 argument, got 2"},
+		{"decimal with no arguments", `permit(principal, action, resource) when { decimal() };`, false, "function decimal expects 1 argument, got 0"},
+		{"datetime with two arguments", `permit(principal, action, resource) when { datetime("2024-01-01", "2024-01-02") };`, false, "function datetime expects 1 argument, got 2"},
+		{"duration with no arguments", `permit(principal, action, resource) when { duration() };`, false, "function duration expects 1 argument, got 0"},
+		{"isIpv4 with an argument", `permit(principal, action, resource) when { ip("1.2.3.4").isIpv4(ip("1.2.3.4")) };`, false, "method isIpv4 expects 0 arguments, got 1"},
+		{"isIpv6 with an argument", `permit(principal, action, resource) when { ip("1.2.3.4").isIpv6(ip("1.2.3.4")) };`, false, "method isIpv6 expects 0 arguments, got 1"},
+		{"isLoopback with an argument", `permit(principal, action, resource) when { ip("1.2.3.4").isLoopback(ip("1.2.3.4")) };`, false, "method isLoopback expects 0 arguments, got 1"},
+		{"isMulticast with an argument", `permit(principal, action, resource) when { ip("1.2.3.4").isMulticast(ip("1.2.3.4")) };`, false, "method isMulticast expects 0 arguments, got 1"},
+		{"isInRange with no arguments", `permit(principal, action, resource) when { ip("1.2.3.4").isInRange() };`, false, "method isInRange expects 1 argument, got 0"},
+		{"isInRange with two arguments", `permit(principal, action, resource) when { ip("1.2.3.4").isInRange(ip("1.2.3.0/24"), ip("1.2.4.0/24")) };`, false, "method isInRange expects 1 argument, got 2"},
+		{"lessThan with no arguments", `permit(principal, action, resource) when { decimal("1.0").lessThan() };`, false, "method lessThan expects 1 argument, got 0"},
+		{"lessThanOrEqual with two arguments", `permit(principal, action, resource) when { decimal("1.0").lessThanOrEqual(decimal("2.0"), decimal("3.0")) };`, false, "method lessThanOrEqual expects 1 argument, got 2"},
+		{"greaterThan with no arguments", `permit(principal, action, resource) when { decimal("1.0").greaterThan() };`, false, "method greaterThan expects 1 argument, got 0"},
+		{"greaterThanOrEqual with no arguments", `permit(principal, action, resource) when { decimal("1.0").greaterThanOrEqual() };`, false, "method greaterThanOrEqual expects 1 argument, got 0"},
+		{"offset with no arguments", `permit(principal, action, resource) when { datetime("2024-01-01").offset() == datetime("2024-01-01") };`, false, "method offset expects 1 argument, got 0"},
+		{"durationSince with two arguments", `permit(principal, action, resource) when { datetime("2024-01-02").durationSince(datetime("2024-01-01"), datetime("2024-01-01")) == duration("1d") };`, false, "method durationSince expects 1 argument, got 2"},
+		{"toDate with an argument", `permit(principal, action, resource) when { datetime("2024-01-01").toDate(duration("1h")) == datetime("2024-01-01") };`, false, "method toDate expects 0 arguments, got 1"},
+		{"toTime with an argument", `permit(principal, action, resource) when { datetime("2024-01-01").toTime(duration("1h")) == duration("0s") };`, false, "method toTime expects 0 arguments, got 1"},
+		{"toDays with an argument", `permit(principal, action, resource) when { duration("1d").toDays(1) == 1 };`, false, "method toDays expects 0 arguments, got 1"},
+		{"toHours with an argument", `permit(principal, action, resource) when { duration("1h").toHours(1) == 1 };`, false, "method toHours expects 0 arguments, got 1"},
+		{"toMinutes with an argument", `permit(principal, action, resource) when { duration("1m").toMinutes(1) == 1 };`, false, "method toMinutes expects 0 arguments, got 1"},
+		{"toSeconds with an argument", `permit(principal, action, resource) when { duration("1s").toSeconds(1) == 1 };`, false, "method toSeconds expects 0 arguments, got 1"},
+		{"toMilliseconds with an argument", `permit(principal, action, resource) when { duration("1ms").toMilliseconds(1) == 1 };`, false, "method toMilliseconds expects 0 arguments, got 1"},
 	}
 
 	for _, tc := range tests {
@@ -2123,6 +2590,156 @@ func TestArithmeticOperations(t *testing.T) {
 	}
 }
 
+// TestNestedUnaryOperators tests that ! and - typecheck compound operands and
+// that an operand type error is reported once, by the operator at fault.
+func TestNestedUnaryOperators(t *testing.T) {
+	schemaJSON := `{
+		"": {
+			"entityTypes": {
+				"User": {
+					"shape": {
+						"type": "Record",
+						"attributes": {
+							"score": {"type": "Long", "required": true},
+							"bonus": {"type": "Long", "required": true},
+							"name": {"type": "String", "required": true},
+							"active": {"type": "Boolean", "required": true},
+							"verified": {"type": "Boolean", "required": true}
+						}
+					}
+				}
+			},
+			"actions": {
+				"view": {
+					"appliesTo": {
+						"principalTypes": ["User"],
+						"resourceTypes": ["User"]
+					}
+				}
+			}
+		}
+	}`
+
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		cond     string
+		wantErrs []string
+	}{
+		{"negate sum", `-(principal.score + principal.bonus) < 0`, nil},
+		{"double negate", `-(-principal.score) == principal.score`, nil},
+		{"negate min long", `-(-9223372036854775808) < 0`, nil},
+		{"not conjunction", `!(principal.active && principal.verified)`, nil},
+		{"not disjunction", `!(principal.active || !principal.verified)`, nil},
+		{"not comparison", `!(-principal.score > 0)`, nil},
+		{"negate boolean expression", `-(principal.active && principal.verified) < 0`,
+			[]string{"unexpectedType: negation requires Long operand, got Bool"}},
+		{"not long expression", `!(principal.score + 1)`,
+			[]string{"unexpectedType: ! operator requires boolean operand, got Long"}},
+		{"error inside negated sum", `-(principal.name + 1) < 0`,
+			[]string{"unexpectedType: arithmetic operator requires Long operands, got String"}},
+		{"error inside negated conjunction", `!(principal.active && principal.score)`,
+			[]string{"unexpectedType: boolean operator requires boolean operands, got Long"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := `permit(principal == User::"alice", action == Action::"view", resource) when { ` + tc.cond + ` };`
+			result := validatePolicyString(t, s, src)
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Message)
+			}
+			if len(got) != len(tc.wantErrs) {
+				t.Fatalf("errors = %q, want %q", got, tc.wantErrs)
+			}
+			for i, want := range tc.wantErrs {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+// TestInOperandTypes tests that in accepts an entity, for ancestry, or a set
+// of entities, for ancestry against each element, and rejects other sets.
+func TestInOperandTypes(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Group;
+		entity User in [Group] { group: Group, managed: Set<Doc>, tags: Set<String>, name: String };
+		entity Doc in [Doc] { parent: Doc };
+		action view appliesTo { principal: User, resource: Doc };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cond        string
+		expectValid bool
+		errorSubstr string
+	}{
+		{"ancestry", `principal in principal.group`, true, ""},
+		{"set of entities", `resource.parent in principal.managed`, true, ""},
+		{"set literal", `resource in [resource.parent, Doc::"root"]`, true, ""},
+		{"set of strings", `resource in principal.tags`, false, "must be a set of entities, got Set<String>"},
+		{"string", `resource in principal.name`, false, "must be entity or set, got String"},
+		{"contains entity", `principal.managed.contains(resource)`, true, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := `permit(principal, action == Action::"view", resource) when { ` + tc.cond + ` };`
+			checkPolicyResult(t, validatePolicyString(t, s, src), tc.expectValid, tc.errorSubstr)
+		})
+	}
+}
+
+// TestSetOperationArguments tests that contains takes an element and
+// containsAll and containsAny take a set, each compatible with the elements of
+// the receiver.
+func TestSetOperationArguments(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Group;
+		entity User in [Group] { roles: Set<String>, levels: Set<Long>, groups: Set<Group>, role: String };
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cond        string
+		expectValid bool
+		errorSubstr string
+	}{
+		{"contains element", `principal.roles.contains(principal.role)`, true, ""},
+		{"contains entity", `principal.groups.contains(Group::"admins")`, true, ""},
+		{"contains wrong element", `principal.roles.contains(1)`, false, "lubErr: contains argument of type Long is incompatible with Set<String>"},
+		{"containsAll set", `principal.roles.containsAll(["admin", principal.role])`, true, ""},
+		{"containsAll empty set", `principal.roles.containsAll([])`, false, "emptySetErr"},
+		{"containsAll non-set", `principal.roles.containsAll("admin")`, false, "unexpectedType: containsAll expects a Set argument, got String"},
+		{"containsAll wrong elements", `principal.roles.containsAll(principal.levels)`, false, "lubErr: containsAll argument of type Set<Long> is incompatible with Set<String>"},
+		{"containsAny set", `principal.levels.containsAny([1, 2])`, true, ""},
+		{"containsAny non-set", `principal.roles.containsAny(principal.role)`, false, "unexpectedType: containsAny expects a Set argument, got String"},
+		{"containsAny wrong elements", `principal.groups.containsAny(["admins"])`, false, "lubErr: containsAny argument of type Set<String> is incompatible with Set<Entity<Group>>"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := `permit(principal, action == Action::"view", resource) when { ` + tc.cond + ` };`
+			checkPolicyResult(t, validatePolicyString(t, s, src), tc.expectValid, tc.errorSubstr)
+		})
+	}
+}
+
 // TestRecordAttributeAccess tests type checking for record attribute access
 func TestRecordAttributeAccess(t *testing.T) {
 	schemaJSON := `{
@@ -2177,3 +2794,204 @@ func TestRecordAttributeAccess(t *testing.T) {
 		})
 	}
 }
+
+// TestHasNarrowing tests that `has` guards narrow optional attributes
+func TestHasNarrowing(t *testing.T) {
+	schemaJSON := `{
+		"": {
+			"entityTypes": {
+				"User": {
+					"shape": {
+						"type": "Record",
+						"attributes": {
+							"manager": {"type": "Entity", "name": "User", "required": false},
+							"profile": {
+								"type": "Record",
+								"required": false,
+								"attributes": {
+									"age": {"type": "Long", "required": false}
+								}
+							}
+						}
+					}
+				}
+			},
+			"actions": {
+				"view": {
+					"appliesTo": {
+						"principalTypes": ["User"],
+						"resourceTypes": ["User"],
+						"context": {
+							"type": "Record",
+							"attributes": {
+								"reason": {"type": "String", "required": false}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		expectValid bool
+		errorSubstr string
+	}{
+		{"unguarded context access", `permit(principal, action == Action::"view", resource) when { context.reason == "x" };`, false, "optional"},
+		{"and-guarded context access", `permit(principal, action == Action::"view", resource) when { context has reason && context.reason == "x" };`, true, ""},
+		{"if-guarded context access", `permit(principal, action == Action::"view", resource) when { if context has reason then context.reason == "x" else false };`, true, ""},
+		{"access in else branch", `permit(principal, action == Action::"view", resource) when { if context has reason then true else context.reason == "x" };`, false, "optional"},
+		{"or does not guard right operand", `permit(principal, action == Action::"view", resource) when { context has reason || context.reason == "x" };`, false, "optional"},
+		{"guard from earlier when clause", `permit(principal, action == Action::"view", resource) when { context has reason } when { context.reason == "x" };`, true, ""},
+		{"guard from unless clause", `permit(principal, action == Action::"view", resource) unless { context has reason } when { context.reason == "x" };`, false, "optional"},
+		{"and-guarded entity access", `permit(principal == User::"alice", action == Action::"view", resource) when { principal has manager && principal.manager == User::"bob" };`, true, ""},
+		{"nested has guards", `permit(principal == User::"alice", action == Action::"view", resource) when { principal has profile && principal.profile has age && principal.profile.age > 18 };`, true, ""},
+		{"nested access guarded only at outer level", `permit(principal == User::"alice", action == Action::"view", resource) when { principal has profile && principal.profile.age > 18 };`, false, "optional"},
+		{"guard on different attribute", `permit(principal == User::"alice", action == Action::"view", resource) when { principal has manager && principal.profile has age };`, false, "optional"},
+		{"guard from both or branches", `permit(principal, action == Action::"view", resource) when { (context has reason || context has reason) && context.reason == "x" };`, true, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runExtensionLiteralTest(t, s, tc.policy, tc.expectValid, tc.errorSubstr)
+		})
+	}
+}
+
+// TestCrossVariableEquality tests that == accepts any two entity-typed
+// operands, including entities of different declared types, and rejects
+// comparing an entity with a primitive.
+func TestCrossVariableEquality(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity Team;
+		entity User { name: String, team: Team, manager?: User };
+		entity Doc { owner: User, team: Team, title: String };
+		action view appliesTo { principal: User, resource: Doc };
+		action follow appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		cond     string
+		wantErrs []string
+	}{
+		{"owner equals principal", "view", `resource.owner == principal`, nil},
+		{"principal equals owner", "view", `principal != resource.owner`, nil},
+		{"attributes of same entity type", "view", `resource.team == principal.team`, nil},
+		{"principal equals resource", "follow", `principal == resource`, nil},
+		{"entity equals different entity type", "view", `resource.team == principal`, nil},
+		{"guarded optional entity", "follow", `principal has manager && principal.manager == resource`, nil},
+		{"entity equals string", "view", `principal == principal.name`,
+			[]string{"lubErr: type mismatch in equality: cannot compare Entity<User> with String"}},
+		{"string equals entity", "view", `resource.title != resource.owner`,
+			[]string{"lubErr: type mismatch in equality: cannot compare String with Entity<User>"}},
+		{"entity equals long", "view", `resource.owner == 1`,
+			[]string{"lubErr: type mismatch in equality: cannot compare Entity<User> with Long"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := `permit(principal, action == Action::"` + tc.action + `", resource) when { ` + tc.cond + ` };`
+			result := validatePolicyString(t, s, src)
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Message)
+			}
+			if len(got) != len(tc.wantErrs) {
+				t.Fatalf("errors = %q, want %q", got, tc.wantErrs)
+			}
+			for i, want := range tc.wantErrs {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+
+	t.Run("disjoint principal and resource types", func(t *testing.T) {
+		src := `permit(principal, action == Action::"view", resource) when { principal == resource };`
+		result := validatePolicyString(t, s, src)
+		checkPolicyResult(t, result, false, "impossiblePolicy")
+	})
+}
+
+// TestActionReferencesInConditions tests that action literals are checked
+// against the schema wherever they appear in a condition, not only in the
+// scope.
+func TestActionReferencesInConditions(t *testing.T) {
+	s, err := schema.NewFromCedar("", []byte(`
+		entity User;
+		action view, delete appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cond    string
+		wantErr string
+	}{
+		{"known action", `action == Action::"delete"`, ""},
+		{"known actions in set", `action in [Action::"view", Action::"delete"]`, ""},
+		{"unknown action", `action == Action::"nonexistent"`,
+			`unknownEntity: action Action::"nonexistent" is not defined in schema`},
+		{"unknown action in set", `action in [Action::"view", Action::"nonexistent"]`,
+			`unknownEntity: action Action::"nonexistent" is not defined in schema`},
+		{"unknown action in contains", `[Action::"nonexistent"].contains(action)`,
+			`unknownEntity: action Action::"nonexistent" is not defined in schema`},
+		{"unknown action in record", `{a: Action::"nonexistent"}.a == action`,
+			`unknownEntity: action Action::"nonexistent" is not defined in schema`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := `permit(principal, action == Action::"view", resource) when { ` + tc.cond + ` };`
+			result := validatePolicyString(t, s, src)
+			checkPolicyResult(t, result, tc.wantErr == "", tc.wantErr)
+		})
+	}
+
+	t.Run("unless clause", func(t *testing.T) {
+		src := `permit(principal, action, resource) unless { action == Action::"nonexistent" };`
+		result := validatePolicyString(t, s, src)
+		checkPolicyResult(t, result, false, `action Action::"nonexistent" is not defined`)
+	})
+
+	v, err := New(s)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	constants := []struct {
+		name  string
+		value types.Value
+	}{
+		{"set constant", types.NewSet(types.NewEntityUID("Action", "view"), types.NewEntityUID("Action", "nonexistent"))},
+		{"record constant", types.NewRecord(types.RecordMap{"a": types.NewEntityUID("Action", "nonexistent")})},
+		{"nested constant", types.NewSet(types.NewRecord(types.RecordMap{"a": types.NewEntityUID("Action", "nonexistent")}))},
+	}
+	for _, tc := range constants {
+		t.Run(tc.name, func(t *testing.T) {
+			p := ast.Permit().ActionEq(types.NewEntityUID("Action", "view")).When(ast.Value(tc.value).Equal(ast.Action()))
+			errs, _ := v.typecheckPolicy(p)
+			found := false
+			for _, e := range errs {
+				found = found || strings.Contains(e, `unknownEntity: action Action::"nonexistent" is not defined in schema`)
+			}
+			if !found {
+				t.Errorf("expected unknown action error, got %v", errs)
+			}
+		})
+	}
+}
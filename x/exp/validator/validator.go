@@ -40,12 +40,32 @@ type Validator struct {
 	// strictEntityValidation when true, validates that entities don't have
 	// attributes that aren't declared in the schema.
 	strictEntityValidation bool
+	// strictEntityTypes and openEntityTypes override strictEntityValidation
+	// for individual entity types.
+	strictEntityTypes map[types.EntityType]bool
+	openEntityTypes   map[types.EntityType]bool
+	// entityReferences when true, validates that entity references in
+	// entity attributes name entities that exist.
+	entityReferences bool
 	// allowUnknownEntityTypes when true, allows unknown entity types in
 	// principalTypes and resourceTypes. This matches Lean's behavior where
 	// unknown types are handled at policy validation time (impossiblePolicy).
 	// By default (false), unknown types are rejected at schema validation
 	// time, matching Cedar Rust behavior.
 	allowUnknownEntityTypes bool
+	// defaultNamespace, when set, is tried for unqualified entity types and
+	// actions in policies that are not otherwise declared.
+	defaultNamespace string
+	// heterogeneousSetWarnings when true, reports set literals whose elements
+	// have incompatible types as warnings instead of errors.
+	heterogeneousSetWarnings bool
+	// multiTypeAttributeWarnings when true, reports attribute accesses on a
+	// principal or resource with several possible types whose declarations
+	// are missing or conflicting as warnings instead of errors.
+	multiTypeAttributeWarnings bool
+	// requiredAnnotations lists the annotation keys, without the leading @,
+	// that every policy must carry.
+	requiredAnnotations []string
 }
 
 // ValidatorOption configures a Validator.
@@ -93,6 +113,107 @@ func WithStrictEntityValidation() ValidatorOption {
 	}
 }
 
+// WithStrictEntityValidationFor enables strict entity validation for the given
+// entity types only, regardless of WithStrictEntityValidation. Types whose
+// schema allows additional attributes (OpenRecord) still accept them.
+func WithStrictEntityValidationFor(entityTypes ...types.EntityType) ValidatorOption {
+	return func(v *Validator) {
+		if v.strictEntityTypes == nil {
+			v.strictEntityTypes = map[types.EntityType]bool{}
+		}
+		for _, et := range entityTypes {
+			v.strictEntityTypes[et] = true
+			delete(v.openEntityTypes, et)
+		}
+	}
+}
+
+// WithOpenEntityTypes allows undeclared attributes on entities of the given
+// types even when WithStrictEntityValidation is enabled. This is useful when
+// a few types carry free-form data while the rest of the schema is strict.
+func WithOpenEntityTypes(entityTypes ...types.EntityType) ValidatorOption {
+	return func(v *Validator) {
+		if v.openEntityTypes == nil {
+			v.openEntityTypes = map[types.EntityType]bool{}
+		}
+		for _, et := range entityTypes {
+			v.openEntityTypes[et] = true
+			delete(v.strictEntityTypes, et)
+		}
+	}
+}
+
+// WithValidateEntityReferences makes entity validation check that every
+// entity referenced by a declared attribute, such as manager in
+// User::"alice" with manager: User::"bob", including references nested in
+// sets and records, exists in the validated entities. Each element of a set
+// is checked on its own: a missing entity is reported with
+// ErrMissingEntityReference, while a reference of the wrong type remains an
+// ErrUnexpectedType error. References to actions declared in the schema are
+// always considered to exist.
+func WithValidateEntityReferences() ValidatorOption {
+	return func(v *Validator) {
+		v.entityReferences = true
+	}
+}
+
+// WithDefaultNamespace resolves unqualified entity type and action references
+// in policies against the given namespace. For example, with "MyApp" a policy
+// may write User::"alice" or Action::"view" to mean MyApp::User::"alice" or
+// MyApp::Action::"view". Qualified references are unaffected, and unqualified
+// names declared outside any namespace keep their meaning. A short name that
+// is declared both outside and inside the namespace is reported as ambiguous.
+// Entity types are resolved as by [schema.Schema.ResolveEntityTypeIn].
+//
+// This eases migrating a flat schema to a namespaced one without rewriting
+// every policy at once.
+func WithDefaultNamespace(ns string) ValidatorOption {
+	return func(v *Validator) {
+		v.defaultNamespace = ns
+	}
+}
+
+// WithHeterogeneousSetWarnings downgrades the incompatibleSetTypes check for
+// set literals whose elements have incompatible types, such as [1, "two"],
+// from an error to a warning. Warnings are reported in
+// [PolicyValidationResult.Warnings] and do not make the result invalid.
+//
+// By default such sets are errors, matching Cedar's requirement that sets be
+// homogeneous.
+func WithHeterogeneousSetWarnings() ValidatorOption {
+	return func(v *Validator) {
+		v.heterogeneousSetWarnings = true
+	}
+}
+
+// WithMultiTypeAttributeWarnings downgrades the checks on attribute accesses
+// such as principal.name, where the action allows several principal (or
+// resource) types, from errors to warnings. Such an access is checked against
+// every possible type: an attribute missing from some of them (without a
+// `has` guard) is reported as attrNotFound, and one declared with different
+// types is reported as incompatibleAttrTypes. Warnings are reported in
+// [PolicyValidationResult.Warnings] and do not make the result invalid.
+func WithMultiTypeAttributeWarnings() ValidatorOption {
+	return func(v *Validator) {
+		v.multiTypeAttributeWarnings = true
+	}
+}
+
+// RequireAnnotations makes policy validation report an error for each policy
+// that lacks one of the given annotation keys, such as "id" or "owner". Keys
+// may be written with or without the leading @. When "id" is required,
+// ValidatePolicies also reports a policy whose @id differs from its ID in the
+// policy set with ErrMismatchedIDAnnotation. This is a governance check,
+// independent of typechecking, so analyses such as IsPolicySatisfiable and
+// CheckSchemaMigration ignore it.
+func RequireAnnotations(keys ...string) ValidatorOption {
+	return func(v *Validator) {
+		for _, k := range keys {
+			v.requiredAnnotations = append(v.requiredAnnotations, strings.TrimPrefix(k, "@"))
+		}
+	}
+}
+
 // New creates a new Validator from a schema.
 // Options can be provided to configure the validator behavior.
 //
@@ -130,21 +251,37 @@ func (v *Validator) ValidatePolicies(policies *cedar.PolicySet) PolicyValidation
 	result := PolicyValidationResult{Valid: true}
 
 	for id, policy := range policies.All() {
-		if errs := v.validatePolicy(id, policy); len(errs) > 0 {
+		errs, warnings := v.checkPolicy(id, policy)
+		errs = append(errs, v.checkIDAnnotation(id, policy)...)
+		if len(errs) > 0 {
 			result.Valid = false
 			result.Errors = append(result.Errors, errs...)
 		}
+		result.Warnings = append(result.Warnings, warnings...)
 	}
 
 	return result
 }
 
+// ValidatePolicyString parses src as a single Cedar policy and validates it
+// against the schema. The returned error is non-nil only if src does not
+// parse; validation problems are reported in the result, with the policy ID
+// "policy0" and positions relative to src.
+func (v *Validator) ValidatePolicyString(src string) (PolicyValidationResult, error) {
+	var policy cedar.Policy
+	if err := policy.UnmarshalCedar([]byte(src)); err != nil {
+		return PolicyValidationResult{}, err
+	}
+	errs, warnings := v.checkPolicy("policy0", &policy)
+	return PolicyValidationResult{Valid: len(errs) == 0, Errors: errs, Warnings: warnings}, nil
+}
+
 // ValidateEntities validates all entities against the schema.
 func (v *Validator) ValidateEntities(entities types.EntityMap) EntityValidationResult {
 	result := EntityValidationResult{Valid: true}
 
 	for uid, entity := range entities {
-		if errs := v.validateEntity(uid, entity); len(errs) > 0 {
+		if errs := v.validateEntity(uid, entity, entities); len(errs) > 0 {
 			result.Valid = false
 			result.Errors = append(result.Errors, errs...)
 		}
@@ -153,6 +290,22 @@ func (v *Validator) ValidateEntities(entities types.EntityMap) EntityValidationR
 	return result
 }
 
+// ValidateEntitiesGrouped validates all entities against the schema and
+// groups the errors by entity. Every entity in entities has an entry, which is
+// empty when the entity is valid, and each entry lists all of that entity's
+// problems.
+func (v *Validator) ValidateEntitiesGrouped(entities types.EntityMap) map[types.EntityUID][]ValidationError {
+	result := make(map[types.EntityUID][]ValidationError, len(entities))
+	for uid, entity := range entities {
+		errs := []ValidationError{}
+		for _, e := range v.validateEntity(uid, entity, entities) {
+			errs = append(errs, ValidationError{Code: e.Code, Message: e.Message})
+		}
+		result[uid] = errs
+	}
+	return result
+}
+
 // ValidateRequest validates a request against the schema.
 func (v *Validator) ValidateRequest(req cedar.Request) RequestValidationResult {
 	// Check if the action is defined
@@ -194,39 +347,104 @@ func (v *Validator) ValidateRequest(req cedar.Request) RequestValidationResult {
 	return RequestValidationResult{Valid: true}
 }
 
-// validatePolicy validates a single policy.
-func (v *Validator) validatePolicy(id cedar.PolicyID, policy *cedar.Policy) []PolicyError {
-	var errs []PolicyError
+// ValidateAll validates policies, entities, and requests against the schema
+// and returns a consolidated result. A nil policy set or entity map is treated
+// as empty.
+func (v *Validator) ValidateAll(policies *cedar.PolicySet, entities types.EntityMap, requests []cedar.Request) AllResult {
+	result := AllResult{
+		Policies: PolicyValidationResult{Valid: true},
+		Entities: v.ValidateEntities(entities),
+		Requests: make([]RequestValidationResult, len(requests)),
+	}
+	if policies != nil {
+		result.Policies = v.ValidatePolicies(policies)
+	}
+	for i, req := range requests {
+		result.Requests[i] = v.ValidateRequest(req)
+	}
+	return result
+}
+
+// checkPolicy validates a single policy for the policy validation entry
+// points, which, unlike the analyses built on validatePolicy, also report the
+// annotations required by RequireAnnotations.
+func (v *Validator) checkPolicy(id cedar.PolicyID, policy *cedar.Policy) (errs, warnings []PolicyError) {
+	annotations := policy.Annotations()
+	for _, key := range v.requiredAnnotations {
+		if _, ok := annotations[types.Ident(key)]; !ok {
+			errs = append(errs, PolicyError{
+				PolicyID: id,
+				Message:  fmt.Sprintf("missingAnnotation: policy is missing required annotation @%s", key),
+				Code:     ErrMissingAnnotation,
+				Position: policy.Position(),
+			})
+		}
+	}
+	typeErrs, warnings := v.validatePolicy(id, policy)
+	return append(errs, typeErrs...), warnings
+}
 
+// checkIDAnnotation reports, when RequireAnnotations requires @id, a policy
+// whose @id differs from its ID in the policy set.
+func (v *Validator) checkIDAnnotation(id cedar.PolicyID, policy *cedar.Policy) []PolicyError {
+	if !slices.Contains(v.requiredAnnotations, "id") {
+		return nil
+	}
+	annotated, ok := policy.Annotations()["id"]
+	if !ok || cedar.PolicyID(annotated) == id {
+		return nil
+	}
+	return []PolicyError{{
+		PolicyID: id,
+		Message:  fmt.Sprintf("mismatchedAnnotation: policy annotation @id(%q) does not match policy ID %q", annotated, id),
+		Code:     ErrMismatchedIDAnnotation,
+		Position: policy.Position(),
+	}}
+}
+
+// validatePolicy validates a single policy, returning its errors and warnings.
+func (v *Validator) validatePolicy(id cedar.PolicyID, policy *cedar.Policy) (errs, warnings []PolicyError) {
 	// Get the policy AST - convert from public to internal ast type
 	publicAST := policy.AST()
 	policyAST := (*ast.Policy)(publicAST)
+	pos := policy.Position()
+
+	if v.defaultNamespace != "" {
+		var nsErrs []string
+		policyAST, nsErrs = v.qualifyPolicy(policyAST)
+		for _, msg := range nsErrs {
+			errs = append(errs, PolicyError{PolicyID: id, Message: msg, Position: pos})
+		}
+	}
 
 	// Check for impossible policy - a policy that can never match any valid environment.
 	// This matches Lean's impossiblePolicy check.
 	if v.isSchemaEmpty() {
-		errs = append(errs, PolicyError{PolicyID: id, Message: "impossiblePolicy"})
-		return errs
+		errs = append(errs, PolicyError{PolicyID: id, Message: "impossiblePolicy", Position: pos})
+		return errs, nil
 	}
 
 	// Check scope constraints reference valid types
 	scopeErrs := v.validatePolicyScope(policyAST)
 	for _, msg := range scopeErrs {
-		errs = append(errs, PolicyError{PolicyID: id, Message: msg})
+		errs = append(errs, PolicyError{PolicyID: id, Message: msg, Position: pos})
 	}
 
 	// Check for impossible conditions (e.g., when { false } or unless { true })
 	if v.hasImpossibleCondition(policyAST) {
-		errs = append(errs, PolicyError{PolicyID: id, Message: "impossiblePolicy"})
+		errs = append(errs, PolicyError{PolicyID: id, Message: "impossiblePolicy", Position: pos})
 	}
 
 	// Full type-checking of conditions
-	typeErrs := v.typecheckPolicy(policyAST)
+	typeErrs, typeWarnings := v.typecheckPolicy(policyAST)
 	for _, msg := range typeErrs {
-		errs = append(errs, PolicyError{PolicyID: id, Message: msg})
+		errs = append(errs, PolicyError{PolicyID: id, Message: msg, Position: pos})
+	}
+	for _, msg := range typeWarnings {
+		warnings = append(warnings, PolicyError{PolicyID: id, Message: msg, Position: pos})
 	}
 
-	return errs
+	return errs, warnings
 }
 
 // isActionEntityType checks if an entity type looks like an action entity type.
@@ -15,6 +15,10 @@
 package validator
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
@@ -133,6 +137,208 @@ func TestValidatePolicies(t *testing.T) {
 	}
 }
 
+func TestValidatePolicyString(t *testing.T) {
+	s, err := schema.NewFromCedar("test.cedarschema", []byte(`
+		entity User;
+		entity Document;
+		action view appliesTo { principal: User, resource: Document };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		result, err := ValidatePolicyString(s, `permit(principal, action, resource);`)
+		if err != nil {
+			t.Fatalf("Unexpected parse error: %v", err)
+		}
+		assertPolicyResult(t, result).valid()
+	})
+
+	t.Run("validation error has position", func(t *testing.T) {
+		result, err := ValidatePolicyString(s, "\n  permit(principal is Document, action, resource);")
+		if err != nil {
+			t.Fatalf("Unexpected parse error: %v", err)
+		}
+		assertPolicyResult(t, result).invalid()
+		for _, e := range result.Errors {
+			if e.PolicyID != "policy0" || e.Position.Line != 2 || e.Position.Column != 3 {
+				t.Errorf("Expected policy0 at 2:3, got %s at %d:%d", e.PolicyID, e.Position.Line, e.Position.Column)
+			}
+		}
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		result, err := ValidatePolicyString(s, `permit(principal, action);`)
+		if err == nil {
+			t.Fatal("Expected parse error")
+		}
+		if result.Valid || len(result.Errors) != 0 {
+			t.Errorf("Expected empty result with parse error, got %v", result)
+		}
+	})
+
+	t.Run("nil schema", func(t *testing.T) {
+		result, err := ValidatePolicyString(nil, `permit(principal, action, resource);`)
+		if err != nil {
+			t.Fatalf("Unexpected parse error: %v", err)
+		}
+		assertPolicyResult(t, result).invalid()
+
+		if _, err := ValidatePolicyString(nil, `permit(`); err == nil {
+			t.Error("Expected parse error with nil schema")
+		}
+	})
+}
+
+func TestRequireAnnotations(t *testing.T) {
+	s, err := schema.NewFromCedar("test.cedarschema", []byte(`
+		entity User;
+		action view appliesTo { principal: User, resource: User };
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	policies, err := cedar.NewPolicySetFromBytes("policies.cedar", []byte(`
+		@id("policy0") @owner("alice") permit(principal, action, resource);
+		@id("policy1") permit(principal, action, resource);
+		permit(principal, action, resource);
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse policies: %v", err)
+	}
+
+	assertPolicyResult(t, ValidatePolicies(s, policies)).valid()
+
+	result := ValidatePolicies(s, policies, RequireAnnotations("id", "@owner"))
+	assertPolicyResult(t, result).invalid()
+	var got []string
+	for _, e := range result.Errors {
+		if e.Code != ErrMissingAnnotation {
+			t.Errorf("Expected code %s, got %s", ErrMissingAnnotation, e.Code)
+		}
+		got = append(got, fmt.Sprintf("%s: %s", e.PolicyID, e.Message))
+	}
+	slices.Sort(got)
+	want := []string{
+		"policy1: missingAnnotation: policy is missing required annotation @owner",
+		"policy2: missingAnnotation: policy is missing required annotation @id",
+		"policy2: missingAnnotation: policy is missing required annotation @owner",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected errors %v, got %v", want, got)
+	}
+
+	mismatched := cedar.NewPolicySet()
+	mismatched.Add("policy0", policies.Get("policy1"))
+	result = ValidatePolicies(s, mismatched, RequireAnnotations("id"))
+	assertPolicyResult(t, result).invalid()
+	if len(result.Errors) != 1 || result.Errors[0].Code != ErrMismatchedIDAnnotation ||
+		result.Errors[0].Message != `mismatchedAnnotation: policy annotation @id("policy1") does not match policy ID "policy0"` {
+		t.Errorf("Expected a mismatched @id error, got %v", result.Errors)
+	}
+	assertPolicyResult(t, ValidatePolicies(s, mismatched)).valid()
+
+	// Analyses of a policy's behavior ignore the governance check.
+	v, err := New(s, RequireAnnotations("id"))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	unannotated := policies.Get("policy2")
+	if ok, reason := v.IsPolicySatisfiable(unannotated); !ok {
+		t.Errorf("Expected unannotated policy to be satisfiable, got: %s", reason)
+	}
+	if ok, err := v.PoliciesEquivalent(unannotated, policies.Get("policy1")); err != nil || !ok {
+		t.Errorf("Expected equivalent policies, got %v, %v", ok, err)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	schemaJSON := `{
+		"entityTypes": {
+			"User": {},
+			"Document": {}
+		},
+		"actions": {
+			"view": {
+				"appliesTo": {
+					"principalTypes": ["User"],
+					"resourceTypes": ["Document"]
+				}
+			}
+		}
+	}`
+
+	s, err := schema.NewFromJSON([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	policies := cedar.NewPolicySet()
+	var policy cedar.Policy
+	if err := policy.UnmarshalCedar([]byte(`permit(principal, action, resource);`)); err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	policies.Add("test", &policy)
+
+	alice := types.NewEntityUID("User", "alice")
+	doc := types.NewEntityUID("Document", "doc")
+	view := types.NewEntityUID("Action", "view")
+	entities := types.EntityMap{alice: {UID: alice}}
+
+	t.Run("valid", func(t *testing.T) {
+		result := ValidateAll(s, policies, entities, []cedar.Request{
+			{Principal: alice, Action: view, Resource: doc, Context: types.Record{}},
+		})
+		if result.HasErrors() {
+			t.Errorf("Expected no errors, got: %s", result.Summary())
+		}
+		if got, want := result.Summary(), "policies: 0 error(s), 0 warning(s); entities: 0 error(s); requests: 0 of 1 invalid"; got != want {
+			t.Errorf("Summary() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("invalid request and entity", func(t *testing.T) {
+		bogus := types.NewEntityUID("Bogus", "x")
+		result := ValidateAll(s, policies, types.EntityMap{bogus: {UID: bogus}}, []cedar.Request{
+			{Principal: alice, Action: view, Resource: doc, Context: types.Record{}},
+			{Principal: doc, Action: view, Resource: doc, Context: types.Record{}},
+		})
+		if !result.HasErrors() {
+			t.Fatal("Expected errors")
+		}
+		if !result.Requests[0].Valid || result.Requests[1].Valid {
+			t.Errorf("Expected only the second request to be invalid, got: %v", result.Requests)
+		}
+		if got, want := result.Summary(), "policies: 0 error(s), 0 warning(s); entities: 1 error(s); requests: 1 of 2 invalid"; got != want {
+			t.Errorf("Summary() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		var mixed cedar.Policy
+		if err := mixed.UnmarshalCedar([]byte(`permit(principal, action, resource) when { [1, "two"].contains(1) };`)); err != nil {
+			t.Fatalf("Failed to parse policy: %v", err)
+		}
+		warned := cedar.NewPolicySet()
+		warned.Add("mixed", &mixed)
+		result := ValidateAll(s, warned, entities, nil, WithHeterogeneousSetWarnings())
+		if result.HasErrors() || !result.HasWarnings() {
+			t.Errorf("Expected only warnings, got: %s", result.Summary())
+		}
+		if got, want := result.Summary(), "policies: 0 error(s), 1 warning(s); entities: 0 error(s); requests: 0 of 0 invalid"; got != want {
+			t.Errorf("Summary() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("nil schema", func(t *testing.T) {
+		result := ValidateAll(nil, nil, nil, nil)
+		if !result.HasErrors() || result.Policies.Valid {
+			t.Errorf("Expected schema error in policy result, got: %v", result)
+		}
+	})
+}
+
 func TestRecordAttributesMatchAdditional(t *testing.T) {
 
 	expected := schema.RecordType{
@@ -607,6 +813,89 @@ func TestNamespacedActionValidation(t *testing.T) {
 	}
 }
 
+// TestDefaultNamespace tests that WithDefaultNamespace resolves unqualified
+// entity types and actions in policies against the configured namespace.
+func TestDefaultNamespace(t *testing.T) {
+	schemaText := `
+		entity Robot;
+		action audit appliesTo { principal: Robot, resource: Robot };
+		namespace MyApp {
+			entity Group;
+			entity User in [Group] { manager?: User };
+			entity Document;
+			action view, edit appliesTo { principal: User, resource: Document };
+		}
+	`
+	s, err := schema.NewFromCedar("", []byte(schemaText))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	v, err := New(s, WithDefaultNamespace("MyApp"))
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		policy    string
+		wantValid bool
+		wantError string
+	}{
+		{
+			"unqualified scope",
+			`permit(principal == User::"alice", action == Action::"view", resource is Document);`,
+			true, "",
+		},
+		{
+			"unqualified action set and group",
+			`permit(principal is User in Group::"admins", action in [Action::"view", Action::"edit"], resource);`,
+			true, "",
+		},
+		{
+			"unqualified references in conditions",
+			`permit(principal, action == Action::"edit", resource) when { principal has manager && principal.manager == User::"bob" && resource is Document };`,
+			true, "",
+		},
+		{
+			"qualified references still work",
+			`permit(principal == MyApp::User::"alice", action == MyApp::Action::"view", resource);`,
+			true, "",
+		},
+		{
+			"names outside the namespace keep their meaning",
+			`permit(principal == Robot::"r2", action == Action::"audit", resource);`,
+			true, "",
+		},
+		{
+			"unknown short name stays unknown",
+			`permit(principal == Bot::"b1", action == Action::"view", resource);`,
+			false, "unknown entity type: Bot",
+		},
+		{
+			"short name declared in both namespaces is ambiguous",
+			`permit(principal, action, resource) when { action is Action };`,
+			false, "ambiguousType: Action could refer to Action or MyApp::Action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy cedar.Policy
+			if err := policy.UnmarshalCedar([]byte(tt.policy)); err != nil {
+				t.Fatalf("Failed to parse policy: %v", err)
+			}
+			policies := cedar.NewPolicySet()
+			policies.Add("test", &policy)
+			checkPolicyResult(t, v.ValidatePolicies(policies), tt.wantValid, tt.wantError)
+		})
+	}
+
+	// Without the option, unqualified references are not resolved.
+	result := validatePolicyString(t, s, `permit(principal == User::"alice", action == Action::"view", resource);`)
+	assertPolicyResult(t, result).invalid()
+}
+
 // TestOpenRecordAllowsExtraAttributes tests that entities with no shape definition
 // allow extra attributes even in strict mode (entities without a shape are open by default).
 func TestOpenRecordAllowsExtraAttributes(t *testing.T) {
@@ -3169,3 +3458,89 @@ func TestExpectArgsError(t *testing.T) {
 		}
 	}
 }
+
+func TestPolicyValidationResultWriteJSONL(t *testing.T) {
+	result := PolicyValidationResult{
+		Errors: []PolicyError{
+			{
+				PolicyID: "policy0",
+				Code:     ErrUnexpectedType,
+				Message:  "unexpected type\nspanning lines",
+				Position: cedar.Position{Filename: "policies.cedar", Offset: 10, Line: 2, Column: 1},
+			},
+			{PolicyID: "policy1", Message: "no code"},
+		},
+		Warnings: []PolicyError{
+			{PolicyID: "policy2", Code: ErrIncompatibleTypes, Message: "mixed set"},
+		},
+	}
+
+	var buf strings.Builder
+	if err := result.WriteJSONL(&buf); err != nil {
+		t.Fatalf("WriteJSONL() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	type line struct {
+		PolicyID string `json:"policyId"`
+		Severity string `json:"severity"`
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+		Location struct {
+			Filename string `json:"filename"`
+			Offset   int    `json:"offset"`
+			Line     int    `json:"line"`
+			Column   int    `json:"column"`
+		} `json:"location"`
+	}
+	var got []line
+	for _, l := range lines {
+		var decoded line
+		if err := json.Unmarshal([]byte(l), &decoded); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", l, err)
+		}
+		got = append(got, decoded)
+	}
+
+	if got[0].PolicyID != "policy0" || got[0].Severity != SeverityError || got[0].Code != "unexpected_type" ||
+		got[0].Message != "unexpected type\nspanning lines" {
+		t.Errorf("line 0 = %+v", got[0])
+	}
+	if loc := got[0].Location; loc.Filename != "policies.cedar" || loc.Offset != 10 || loc.Line != 2 || loc.Column != 1 {
+		t.Errorf("line 0 location = %+v", loc)
+	}
+	if got[1].PolicyID != "policy1" || got[1].Severity != SeverityError || got[1].Code != "" {
+		t.Errorf("line 1 = %+v", got[1])
+	}
+	if strings.Contains(lines[1], `"code"`) {
+		t.Errorf("line 1 should omit an empty code: %s", lines[1])
+	}
+	if got[2].PolicyID != "policy2" || got[2].Severity != SeverityWarning || got[2].Code != "incompatible_types" {
+		t.Errorf("line 2 = %+v", got[2])
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		var buf strings.Builder
+		if err := (PolicyValidationResult{Valid: true}).WriteJSONL(&buf); err != nil {
+			t.Fatalf("WriteJSONL() error = %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("expected no output, got %q", buf.String())
+		}
+	})
+
+	t.Run("writeError", func(t *testing.T) {
+		if err := result.WriteJSONL(failingWriter{}); !errors.Is(err, errWrite) {
+			t.Errorf("WriteJSONL() error = %v, want %v", err, errWrite)
+		}
+	})
+}
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }